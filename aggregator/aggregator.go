@@ -0,0 +1,249 @@
+package aggregator
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// Common timeframes, any duration dividing a day evenly is accepted
+const (
+	M1  = time.Minute
+	M5  = 5 * time.Minute
+	M15 = 15 * time.Minute
+	M30 = 30 * time.Minute
+	H1  = time.Hour
+	H4  = 4 * time.Hour
+	D1  = 24 * time.Hour
+)
+
+// Trade is a single execution reported by an exchange feed
+type Trade struct {
+	Price float64   `json:"price"`
+	Qty   float64   `json:"qty"`
+	Time  time.Time `json:"time"`
+}
+
+type Config struct {
+	// Candle length
+	Timeframe time.Duration
+	// Buckets are aligned to midnight of Location, Jakarta when nil
+	Location *time.Location
+	// How long a candle is kept open after its end to accept late ticks
+	Delay time.Duration
+	// Emit flat, zero volume candles (at the previous close) for
+	// timeframes without any trade
+	FillGaps bool
+	// Length of the candles passed to AddCandle when rolling a lower
+	// timeframe up, used to tell when the last one of a bucket arrived
+	Source time.Duration
+	// Completed candles are passed to Emit, or sent to Out when Emit is
+	// nil. One of them is required.
+	Emit func(c indikators.Candle)
+	Out  chan<- indikators.Candle
+}
+
+// Aggregator turns a stream of trades, or of lower timeframe candles, into
+// candles of a fixed timeframe. Candles are emitted in time order once the
+// stream moved past their end (plus Delay). Ticks for a candle which was
+// already emitted are dropped and counted in Late. An Aggregator is not
+// safe for concurrent use.
+//
+// Higher timeframes are built by chaining, e.g. a 1m aggregator whose Emit
+// calls AddCandle of a 1h aggregator configured with Source: M1.
+type Aggregator struct {
+	cfg       Config
+	pending   []*bucket
+	watermark time.Time
+	last      *indikators.Candle
+	lastEnd   time.Time
+	late      int64
+}
+
+type bucket struct {
+	c     indikators.Candle
+	first time.Time
+	last  time.Time
+}
+
+func NewAggregator(cfg Config) (*Aggregator, error) {
+	if cfg.Timeframe <= 0 || D1%cfg.Timeframe != 0 {
+		return nil, errors.New("aggregator: timeframe must divide a day evenly")
+	}
+	if cfg.Emit == nil && cfg.Out == nil {
+		return nil, errors.New("aggregator: Emit or Out is required")
+	}
+	if cfg.Location == nil {
+		cfg.Location = indikators.Jakarta
+	}
+	return &Aggregator{
+		cfg: cfg,
+	}, nil
+}
+
+// AddTrade merges a trade into its candle
+func (a *Aggregator) AddTrade(t Trade) {
+	a.add(indikators.Candle{
+		Time:   t.Time,
+		Open:   t.Price,
+		High:   t.Price,
+		Low:    t.Price,
+		Close:  t.Price,
+		Volume: t.Qty,
+	}, t.Time)
+}
+
+// AddCandle merges a completed lower timeframe candle into its candle
+func (a *Aggregator) AddCandle(c indikators.Candle) {
+	a.add(c, c.Time.Add(a.cfg.Source))
+}
+
+// Advance tells the aggregator the stream reached now even if no trade
+// arrived, so quiet timeframes get closed (and filled when FillGaps)
+func (a *Aggregator) Advance(now time.Time) {
+	a.advance(now)
+	if !a.cfg.FillGaps || a.last == nil || len(a.pending) > 0 {
+		return
+	}
+	for !a.lastEnd.Add(a.cfg.Timeframe).After(a.watermark) {
+		a.emitFlat(a.lastEnd)
+	}
+}
+
+// Flush emits every pending candle including the current incomplete one
+func (a *Aggregator) Flush() {
+	for len(a.pending) > 0 {
+		a.emit(a.pending[0])
+		a.pending = a.pending[1:]
+	}
+}
+
+// Number of ticks dropped because their candle was already emitted
+func (a *Aggregator) Late() int64 {
+	return a.late
+}
+
+// Start of the candle t belongs to
+func (a *Aggregator) BucketStart(t time.Time) time.Time {
+	t = t.In(a.cfg.Location)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, a.cfg.Location)
+	if a.cfg.Timeframe == D1 {
+		return day
+	}
+	return day.Add(t.Sub(day) / a.cfg.Timeframe * a.cfg.Timeframe)
+}
+
+func (a *Aggregator) bucketEnd(start time.Time) time.Time {
+	if a.cfg.Timeframe == D1 {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.Add(a.cfg.Timeframe)
+}
+
+func (a *Aggregator) add(c indikators.Candle, seen time.Time) {
+	start := a.BucketStart(c.Time)
+	if a.last != nil && start.Before(a.lastEnd) {
+		a.late++
+		return
+	}
+
+	i := sort.Search(len(a.pending), func(i int) bool {
+		return !a.pending[i].c.Time.Before(start)
+	})
+	if i == len(a.pending) || !a.pending[i].c.Time.Equal(start) {
+		b := &bucket{
+			c:     c,
+			first: c.Time,
+			last:  c.Time,
+		}
+		b.c.Time = start
+		a.pending = append(a.pending, nil)
+		copy(a.pending[i+1:], a.pending[i:])
+		a.pending[i] = b
+	} else {
+		b := a.pending[i]
+		if c.Time.Before(b.first) {
+			b.first = c.Time
+			b.c.Open = c.Open
+		}
+		if !c.Time.Before(b.last) {
+			b.last = c.Time
+			b.c.Close = c.Close
+		}
+		if c.High > b.c.High {
+			b.c.High = c.High
+		}
+		if c.Low < b.c.Low {
+			b.c.Low = c.Low
+		}
+		b.c.Volume += c.Volume
+	}
+
+	a.advance(seen)
+}
+
+func (a *Aggregator) advance(seen time.Time) {
+	if wm := seen.Add(-a.cfg.Delay); wm.After(a.watermark) {
+		a.watermark = wm
+	}
+	for len(a.pending) > 0 {
+		b := a.pending[0]
+		if a.bucketEnd(b.c.Time).After(a.watermark) {
+			return
+		}
+		a.emit(b)
+		a.pending = a.pending[1:]
+	}
+}
+
+func (a *Aggregator) emit(b *bucket) {
+	if a.cfg.FillGaps && a.last != nil {
+		for a.lastEnd.Before(b.c.Time) {
+			a.emitFlat(a.lastEnd)
+		}
+	}
+	a.send(b.c)
+}
+
+func (a *Aggregator) emitFlat(start time.Time) {
+	p := a.last.Close
+	a.send(indikators.Candle{
+		Time:  start,
+		Open:  p,
+		High:  p,
+		Low:   p,
+		Close: p,
+	})
+}
+
+func (a *Aggregator) send(c indikators.Candle) {
+	a.last = &c
+	a.lastEnd = a.bucketEnd(c.Time)
+	if a.cfg.Emit != nil {
+		a.cfg.Emit(c)
+	} else {
+		a.cfg.Out <- c
+	}
+}
+
+// Resample rolls a complete series of candles up to a higher timeframe
+func Resample(in []indikators.Candle, tf time.Duration, loc *time.Location) ([]indikators.Candle, error) {
+	var out []indikators.Candle
+	a, err := NewAggregator(Config{
+		Timeframe: tf,
+		Location:  loc,
+		Emit: func(c indikators.Candle) {
+			out = append(out, c)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range in {
+		a.AddCandle(c)
+	}
+	a.Flush()
+	return out, nil
+}
@@ -0,0 +1,219 @@
+package aggregator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/aggregator"
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+var wib = indikators.Jakarta
+
+func at(hour, min, sec int) time.Time {
+	return time.Date(2024, 1, 1, hour, min, sec, 0, wib)
+}
+
+func collect(t *testing.T, cfg aggregator.Config) (*aggregator.Aggregator, *[]indikators.Candle) {
+	t.Helper()
+	var out []indikators.Candle
+	cfg.Emit = func(c indikators.Candle) {
+		out = append(out, c)
+	}
+	a, err := aggregator.NewAggregator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a, &out
+}
+
+func TestNewAggregator(t *testing.T) {
+	emit := func(indikators.Candle) {}
+
+	tests := []struct {
+		name string
+		cfg  aggregator.Config
+		err  string
+	}{
+		{name: "Valid", cfg: aggregator.Config{Timeframe: aggregator.M15, Emit: emit}},
+		{name: "Out channel", cfg: aggregator.Config{Timeframe: aggregator.H4, Out: make(chan indikators.Candle)}},
+		{name: "Timeframe not dividing a day", cfg: aggregator.Config{Timeframe: 7 * time.Minute, Emit: emit}, err: "aggregator: timeframe must divide a day evenly"},
+		{name: "Zero timeframe", cfg: aggregator.Config{Emit: emit}, err: "aggregator: timeframe must divide a day evenly"},
+		{name: "No Emit nor Out", cfg: aggregator.Config{Timeframe: aggregator.M1}, err: "aggregator: Emit or Out is required"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := aggregator.NewAggregator(test.cfg)
+			if test.err == "" {
+				assert.NoError(t, err, "they should be no error")
+			} else {
+				assert.EqualError(t, err, test.err, "they should be equal")
+			}
+		})
+	}
+}
+
+func TestBucketStart(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeframe time.Duration
+		in        time.Time
+		want      time.Time
+	}{
+		{name: "Minute", timeframe: aggregator.M1, in: at(10, 30, 59), want: at(10, 30, 0)},
+		{name: "Quarter", timeframe: aggregator.M15, in: at(10, 44, 59), want: at(10, 30, 0)},
+		{name: "Four hours from midnight WIB", timeframe: aggregator.H4, in: at(11, 59, 59), want: at(8, 0, 0)},
+		{name: "Four hours of a UTC time", timeframe: aggregator.H4, in: time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC), want: at(4, 0, 0)},
+		{name: "Day before midnight WIB", timeframe: aggregator.D1, in: time.Date(2024, 1, 1, 16, 59, 59, 0, time.UTC), want: at(0, 0, 0)},
+		{name: "Day at midnight WIB", timeframe: aggregator.D1, in: time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC), want: time.Date(2024, 1, 2, 0, 0, 0, 0, wib)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, _ := collect(t, aggregator.Config{Timeframe: test.timeframe})
+			got := a.BucketStart(test.in)
+			assert.True(t, got.Equal(test.want), "got %v, want %v", got, test.want)
+		})
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	tests := []struct {
+		name                string
+		funcUseCaseShouldBe func(t *testing.T)
+	}{
+		{
+			name: "Trades are merged and emitted past the candle end",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				a, out := collect(t, aggregator.Config{Timeframe: aggregator.H1})
+				a.AddTrade(aggregator.Trade{Price: 100, Qty: 1, Time: at(9, 0, 0)})
+				a.AddTrade(aggregator.Trade{Price: 104, Qty: 2, Time: at(9, 20, 0)})
+				a.AddTrade(aggregator.Trade{Price: 98, Qty: 1, Time: at(9, 40, 0)})
+				a.AddTrade(aggregator.Trade{Price: 101, Qty: 3, Time: at(9, 59, 59)})
+				assert.Empty(t, *out, "it should be open until 10:00")
+
+				a.AddTrade(aggregator.Trade{Price: 102, Qty: 1, Time: at(10, 0, 0)})
+				assert.Equal(t, []indikators.Candle{{Time: at(9, 0, 0), Open: 100, High: 104, Low: 98, Close: 101, Volume: 7}}, *out, "they should be equal")
+
+				a.Flush()
+				assert.Equal(t, indikators.Candle{Time: at(10, 0, 0), Open: 102, High: 102, Low: 102, Close: 102, Volume: 1}, (*out)[1], "they should be equal")
+			},
+		},
+		{
+			name: "Delay keeps a candle open for late ticks",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				a, out := collect(t, aggregator.Config{Timeframe: aggregator.M1, Delay: 10 * time.Second})
+				a.AddTrade(aggregator.Trade{Price: 100, Qty: 1, Time: at(9, 0, 10)})
+				a.AddTrade(aggregator.Trade{Price: 103, Qty: 1, Time: at(9, 0, 50)})
+				a.AddTrade(aggregator.Trade{Price: 105, Qty: 1, Time: at(9, 1, 5)})
+				assert.Empty(t, *out, "it should wait until 09:01:10")
+
+				// out of order but within the delay: merged, the close stays
+				// the one of the latest trade
+				a.AddTrade(aggregator.Trade{Price: 90, Qty: 2, Time: at(9, 0, 30)})
+				a.AddTrade(aggregator.Trade{Price: 106, Qty: 1, Time: at(9, 1, 10)})
+				assert.Equal(t, []indikators.Candle{{Time: at(9, 0, 0), Open: 100, High: 103, Low: 90, Close: 103, Volume: 4}}, *out, "they should be equal")
+				assert.Equal(t, int64(0), a.Late(), "they should be equal")
+
+				a.AddTrade(aggregator.Trade{Price: 80, Qty: 1, Time: at(9, 0, 59)})
+				a.AddTrade(aggregator.Trade{Price: 80, Qty: 1, Time: at(8, 59, 0)})
+				assert.Equal(t, int64(2), a.Late(), "they should be counted as late")
+				assert.Len(t, *out, 1, "they should be dropped")
+			},
+		},
+		{
+			name: "FillGaps emits flat candles for quiet timeframes",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				a, out := collect(t, aggregator.Config{Timeframe: aggregator.M1, FillGaps: true})
+				a.AddTrade(aggregator.Trade{Price: 100, Qty: 1, Time: at(9, 0, 10)})
+				a.AddTrade(aggregator.Trade{Price: 110, Qty: 2, Time: at(9, 3, 10)})
+				a.AddTrade(aggregator.Trade{Price: 111, Qty: 1, Time: at(9, 4, 0)})
+				assert.Equal(t, []indikators.Candle{
+					{Time: at(9, 0, 0), Open: 100, High: 100, Low: 100, Close: 100, Volume: 1},
+					{Time: at(9, 1, 0), Open: 100, High: 100, Low: 100, Close: 100},
+					{Time: at(9, 2, 0), Open: 100, High: 100, Low: 100, Close: 100},
+					{Time: at(9, 3, 0), Open: 110, High: 110, Low: 110, Close: 110, Volume: 2},
+				}, *out, "they should be equal")
+
+				a.Advance(at(9, 7, 0))
+				assert.Equal(t, []indikators.Candle{
+					{Time: at(9, 4, 0), Open: 111, High: 111, Low: 111, Close: 111, Volume: 1},
+					{Time: at(9, 5, 0), Open: 111, High: 111, Low: 111, Close: 111},
+					{Time: at(9, 6, 0), Open: 111, High: 111, Low: 111, Close: 111},
+				}, (*out)[4:], "they should be equal")
+			},
+		},
+		{
+			name: "Without FillGaps quiet timeframes are skipped",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				a, out := collect(t, aggregator.Config{Timeframe: aggregator.M1})
+				a.AddTrade(aggregator.Trade{Price: 100, Qty: 1, Time: at(9, 0, 10)})
+				a.AddTrade(aggregator.Trade{Price: 110, Qty: 2, Time: at(9, 3, 10)})
+				a.Advance(at(9, 7, 0))
+				assert.Len(t, *out, 2, "they should be equal")
+				assert.Equal(t, at(9, 3, 0), (*out)[1].Time, "they should be equal")
+			},
+		},
+		{
+			name: "1m candles roll up to 1h through AddCandle",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				h1, hours := collect(t, aggregator.Config{Timeframe: aggregator.H1, Source: aggregator.M1})
+				var minutes []indikators.Candle
+				m1, err := aggregator.NewAggregator(aggregator.Config{
+					Timeframe: aggregator.M1,
+					Emit: func(c indikators.Candle) {
+						minutes = append(minutes, c)
+						h1.AddCandle(c)
+					},
+				})
+				assert.NoError(t, err, "they should be no error")
+
+				for i := 0; i < 60; i++ {
+					m1.AddTrade(aggregator.Trade{Price: float64(100 + i), Qty: 1, Time: at(9, i, 0)})
+					m1.AddTrade(aggregator.Trade{Price: float64(99 + i), Qty: 1, Time: at(9, i, 30)})
+				}
+				assert.Len(t, minutes, 59, "the last minute should still be open")
+				assert.Empty(t, *hours, "the hour should wait for its last minute")
+
+				// the 09:59 candle is emitted by the first trade of 10:00 and
+				// completes the hour right away
+				m1.AddTrade(aggregator.Trade{Price: 200, Qty: 1, Time: at(10, 0, 0)})
+				assert.Equal(t, []indikators.Candle{{Time: at(9, 0, 0), Open: 100, High: 159, Low: 99, Close: 158, Volume: 120}}, *hours, "they should be equal")
+			},
+		},
+		{
+			name: "Out channel receives completed candles",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				ch := make(chan indikators.Candle, 1)
+				a, err := aggregator.NewAggregator(aggregator.Config{Timeframe: aggregator.M5, Out: ch})
+				assert.NoError(t, err, "they should be no error")
+				a.AddTrade(aggregator.Trade{Price: 100, Qty: 1, Time: at(9, 1, 0)})
+				a.AddTrade(aggregator.Trade{Price: 101, Qty: 1, Time: at(9, 5, 0)})
+				assert.Equal(t, indikators.Candle{Time: at(9, 0, 0), Open: 100, High: 100, Low: 100, Close: 100, Volume: 1}, <-ch, "they should be equal")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.funcUseCaseShouldBe(t)
+		})
+	}
+}
+
+func TestResample(t *testing.T) {
+	var in []indikators.Candle
+	for i := 0; i < 8; i++ {
+		p := float64(100 + i)
+		in = append(in, indikators.Candle{Time: at(i, 0, 0), Open: p, High: p + 1, Low: p - 1, Close: p + 0.5, Volume: 10})
+	}
+
+	out, err := aggregator.Resample(in, aggregator.H4, wib)
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, []indikators.Candle{
+		{Time: at(0, 0, 0), Open: 100, High: 104, Low: 99, Close: 103.5, Volume: 40},
+		{Time: at(4, 0, 0), Open: 104, High: 108, Low: 103, Close: 107.5, Volume: 40},
+	}, out, "they should be equal")
+}
@@ -0,0 +1,105 @@
+package backtest
+
+import (
+	"errors"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/metrics"
+)
+
+// Strategy is called once per candle after pending orders were matched
+// against it. Indicators are usually updated inside the callback.
+type Strategy func(b Broker, c indikators.Candle)
+
+// Report of a backtest run
+type Report struct {
+	Equity      []EquityPoint `json:"equity"`
+	Orders      []Order       `json:"orders"`
+	Fills       []Fill        `json:"fills"`
+	StartCash   float64       `json:"start_cash"`
+	FinalEquity float64       `json:"final_equity"`
+	// Total return as a fraction of the starting cash
+	Return float64 `json:"return"`
+	Fees   float64 `json:"fees"`
+	// Closing (sell) fills and the share of them with a positive Pnl
+	Trades  int     `json:"trades"`
+	WinRate float64 `json:"win_rate"`
+	// Largest peak to trough fall of the equity curve as a fraction and
+	// Sharpe ratio of the bar returns, see metrics.FromEquity
+	MaxDrawdown float64 `json:"max_drawdown"`
+	Sharpe      float64 `json:"sharpe"`
+}
+
+// Run replays candles through the strategy. Orders placed on bar i are
+// matched from bar i+1 on: market orders at the open (plus slippage),
+// limit orders once the bar trades through their price. The account is a
+// spot one, orders that need more cash or base currency than available
+// are rejected when they would be filled.
+func Run(cfg Config, candles []indikators.Candle, strategy Strategy) (*Report, error) {
+	if cfg.Cash <= 0 {
+		return nil, errors.New("backtest: starting cash must be positive")
+	}
+	if strategy == nil {
+		return nil, errors.New("backtest: strategy is required")
+	}
+
+	b := newBroker(cfg)
+	equity := make([]EquityPoint, 0, len(candles))
+	for i, c := range candles {
+		b.idx = i
+		b.match(c)
+		b.last = c
+
+		strategy(b, c)
+
+		equity = append(equity, EquityPoint{
+			Time:   c.Time,
+			Cash:   b.cash,
+			Qty:    b.qty,
+			Equity: b.Equity(),
+		})
+	}
+
+	return newReport(cfg, b, equity), nil
+}
+
+func newReport(cfg Config, b *broker, equity []EquityPoint) *Report {
+	r := &Report{
+		Equity:      equity,
+		Orders:      make([]Order, len(b.history)),
+		Fills:       b.fills,
+		StartCash:   cfg.Cash,
+		FinalEquity: cfg.Cash,
+	}
+	for i, o := range b.history {
+		r.Orders[i] = *o
+	}
+	if len(equity) > 0 {
+		r.FinalEquity = equity[len(equity)-1].Equity
+	}
+	r.Return = r.FinalEquity/cfg.Cash - 1
+
+	wins := 0
+	for _, f := range b.fills {
+		r.Fees += f.Fee
+		if f.Side == Sell {
+			r.Trades++
+			if f.Pnl > 0 {
+				wins++
+			}
+		}
+	}
+	if r.Trades > 0 {
+		r.WinRate = float64(wins) / float64(r.Trades)
+	}
+
+	curve := make([]float64, len(equity))
+	for i, p := range equity {
+		curve[i] = p.Equity
+	}
+	m := metrics.FromEquity(curve, metrics.Config{PeriodsPerYear: cfg.PeriodsPerYear})
+	r.MaxDrawdown = m.MaxDrawdown
+	r.Sharpe = m.Sharpe
+
+	return r
+}
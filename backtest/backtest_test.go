@@ -0,0 +1,180 @@
+package backtest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/backtest"
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+func candles(prices ...float64) []indikators.Candle {
+	t := time.Date(2023, 1, 1, 0, 0, 0, 0, indikators.Jakarta)
+	out := make([]indikators.Candle, len(prices))
+	for i, p := range prices {
+		out[i] = indikators.Candle{
+			Time:   t.AddDate(0, 0, i),
+			Open:   p,
+			High:   p + 1,
+			Low:    p - 1,
+			Close:  p,
+			Volume: 1,
+		}
+	}
+	return out
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name                string
+		cfg                 backtest.Config
+		candles             []indikators.Candle
+		strategy            backtest.Strategy
+		funcUseCaseShouldBe func(t *testing.T, r *backtest.Report, err error)
+	}{
+		{
+			name:    "Market orders are filled at the next open with fee and slippage",
+			cfg:     backtest.Config{Cash: 1000, TakerFee: 0.01, Slippage: 0.1},
+			candles: candles(10, 10, 20, 20),
+			strategy: func(b backtest.Broker, c indikators.Candle) {
+				switch b.Index() {
+				case 0:
+					b.Buy(10)
+				case 2:
+					b.Sell(10)
+				}
+			},
+			funcUseCaseShouldBe: func(t *testing.T, r *backtest.Report, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Len(t, r.Fills, 2, "they should be equal")
+				assert.InDelta(t, 11, r.Fills[0].Price, 1e-9, "they should be equal")
+				assert.InDelta(t, 18, r.Fills[1].Price, 1e-9, "they should be equal")
+				// bought 110 + 1.1 fee, sold 180 - 1.8 fee
+				assert.InDelta(t, 1067.1, r.FinalEquity, 1e-9, "they should be equal")
+				assert.InDelta(t, 67.1, r.Fills[1].Pnl, 1e-9, "they should be equal")
+				assert.Equal(t, 1, r.Trades, "they should be equal")
+				assert.Equal(t, 1.0, r.WinRate, "they should be equal")
+			},
+		},
+		{
+			name:    "Limit order waits until the price is reached",
+			cfg:     backtest.Config{Cash: 1000, MakerFee: 0},
+			candles: candles(10, 10, 8, 7, 12),
+			strategy: func(b backtest.Broker, c indikators.Candle) {
+				if b.Index() == 0 {
+					b.BuyLimit(1, 6.5)
+				}
+			},
+			funcUseCaseShouldBe: func(t *testing.T, r *backtest.Report, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Len(t, r.Fills, 1, "they should be equal")
+				assert.Equal(t, r.Equity[3].Time, r.Fills[0].Time, "they should be equal")
+				assert.InDelta(t, 6.5, r.Fills[0].Price, 1e-9, "they should be equal")
+				assert.InDelta(t, 1005.5, r.FinalEquity, 1e-9, "they should be equal")
+				assert.Zero(t, r.MaxDrawdown, "they should be zero")
+			},
+		},
+		{
+			name:    "Limit orders the open gaps through pay the taker fee",
+			cfg:     backtest.Config{Cash: 1000, MakerFee: 0.001, TakerFee: 0.01},
+			candles: candles(10, 10, 7, 8, 12, 9),
+			strategy: func(b backtest.Broker, c indikators.Candle) {
+				switch b.Index() {
+				case 0:
+					b.BuyLimit(10, 8)
+				case 3:
+					b.SellLimit(5, 8.5)
+					b.SellLimit(5, 12.5)
+				}
+			},
+			funcUseCaseShouldBe: func(t *testing.T, r *backtest.Report, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Len(t, r.Fills, 3, "they should be equal")
+				// the buy at 8 gapped to the open of 7
+				assert.InDelta(t, 7, r.Fills[0].Price, 1e-9, "they should be equal")
+				assert.InDelta(t, 0.7, r.Fills[0].Fee, 1e-9, "they should be equal")
+				// the sell at 8.5 gapped to the open of 12, the one at 12.5
+				// was reached later in the bar
+				assert.InDelta(t, 12, r.Fills[1].Price, 1e-9, "they should be equal")
+				assert.InDelta(t, 0.6, r.Fills[1].Fee, 1e-9, "they should be equal")
+				assert.InDelta(t, 12.5, r.Fills[2].Price, 1e-9, "they should be equal")
+				assert.InDelta(t, 0.0625, r.Fills[2].Fee, 1e-9, "they should be equal")
+			},
+		},
+		{
+			name:    "Sharpe of a single return is 0",
+			cfg:     backtest.Config{Cash: 1000, PeriodsPerYear: 365},
+			candles: candles(10, 12),
+			strategy: func(b backtest.Broker, c indikators.Candle) {
+				if b.Index() == 0 {
+					b.Buy(10)
+				}
+			},
+			funcUseCaseShouldBe: func(t *testing.T, r *backtest.Report, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Len(t, r.Equity, 2, "they should be equal")
+				assert.Zero(t, r.Sharpe, "they should be zero")
+			},
+		},
+		{
+			name:    "Position closed in several sells leaves no dust",
+			cfg:     backtest.Config{Cash: 100},
+			candles: candles(10, 10, 10, 10, 10, 10),
+			strategy: func(b backtest.Broker, c indikators.Candle) {
+				switch b.Index() {
+				case 0:
+					b.Buy(0.3)
+				case 1, 2, 3:
+					b.Sell(0.1)
+				case 5:
+					assert.Zero(t, b.Position(), "they should be no position left")
+				}
+			},
+			funcUseCaseShouldBe: func(t *testing.T, r *backtest.Report, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Len(t, r.Fills, 4, "they should be equal")
+				for _, o := range r.Orders {
+					assert.Equal(t, backtest.Filled, o.Status, "they should be equal")
+				}
+				assert.InDelta(t, 0.1, r.Fills[3].Qty, 1e-9, "they should be equal")
+				assert.InDelta(t, 100, r.FinalEquity, 1e-9, "they should be equal")
+			},
+		},
+		{
+			name:    "Orders which cannot be covered are rejected",
+			cfg:     backtest.Config{Cash: 100},
+			candles: candles(10, 10, 10),
+			strategy: func(b backtest.Broker, c indikators.Candle) {
+				if b.Index() == 0 {
+					b.Buy(20)
+					b.Sell(1)
+				}
+			},
+			funcUseCaseShouldBe: func(t *testing.T, r *backtest.Report, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Empty(t, r.Fills, "they should be empty")
+				assert.Equal(t, backtest.Rejected, r.Orders[0].Status, "they should be equal")
+				assert.Equal(t, backtest.Rejected, r.Orders[1].Status, "they should be equal")
+				assert.Equal(t, 100.0, r.FinalEquity, "they should be equal")
+			},
+		},
+		{
+			name:    "Failed without starting cash",
+			cfg:     backtest.Config{},
+			candles: candles(10),
+			strategy: func(b backtest.Broker, c indikators.Candle) {
+			},
+			funcUseCaseShouldBe: func(t *testing.T, r *backtest.Report, err error) {
+				assert.Error(t, err, "they should be error")
+				assert.Nil(t, r, "they should be nil")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := backtest.Run(tt.cfg, tt.candles, tt.strategy)
+			tt.funcUseCaseShouldBe(t, r, err)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package backtest
+
+import (
+	"math"
+
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// Broker is handed to the strategy on every bar. Orders are queued and
+// executed from the next bar on, so a strategy never trades on the bar
+// it has just seen.
+type Broker interface {
+	// Market orders, filled at the open of the next bar
+	Buy(qty float64) int64
+	Sell(qty float64) int64
+	// Limit orders, filled once a later bar trades through the price
+	BuyLimit(qty, price float64) int64
+	SellLimit(qty, price float64) int64
+	Cancel(id int64) bool
+	OpenOrders() []Order
+	// Base currency held
+	Position() float64
+	Cash() float64
+	// Cash plus position valued at the last close
+	Equity() float64
+	// Index of the current bar
+	Index() int
+}
+
+type broker struct {
+	cfg      Config
+	cash     float64
+	qty      float64
+	avgPrice float64
+	// entry fees of the open position, released pro rata on sells
+	entryFee float64
+	last     indikators.Candle
+	idx      int
+	nextID   int64
+	orders   []*Order
+	history  []*Order
+	fills    []Fill
+}
+
+func newBroker(cfg Config) *broker {
+	return &broker{
+		cfg:  cfg,
+		cash: cfg.Cash,
+		idx:  -1,
+	}
+}
+
+func (b *broker) Buy(qty float64) int64 {
+	return b.place(Buy, Market, qty, 0)
+}
+
+func (b *broker) Sell(qty float64) int64 {
+	return b.place(Sell, Market, qty, 0)
+}
+
+func (b *broker) BuyLimit(qty, price float64) int64 {
+	return b.place(Buy, Limit, qty, price)
+}
+
+func (b *broker) SellLimit(qty, price float64) int64 {
+	return b.place(Sell, Limit, qty, price)
+}
+
+func (b *broker) Cancel(id int64) bool {
+	for _, o := range b.orders {
+		if o.ID == id && o.Status == Open {
+			o.Status = Cancelled
+			return true
+		}
+	}
+	return false
+}
+
+func (b *broker) OpenOrders() []Order {
+	var out []Order
+	for _, o := range b.orders {
+		if o.Status == Open {
+			out = append(out, *o)
+		}
+	}
+	return out
+}
+
+func (b *broker) Position() float64 {
+	return b.qty
+}
+
+func (b *broker) Cash() float64 {
+	return b.cash
+}
+
+func (b *broker) Equity() float64 {
+	return b.cash + b.qty*b.last.Close
+}
+
+func (b *broker) Index() int {
+	return b.idx
+}
+
+func (b *broker) place(side Side, t OrderType, qty, price float64) int64 {
+	b.nextID++
+	o := &Order{
+		ID:     b.nextID,
+		Side:   side,
+		Type:   t,
+		Qty:    qty,
+		Price:  price,
+		Status: Open,
+		Time:   b.last.Time,
+	}
+	if qty <= 0 || (t == Limit && price <= 0) {
+		o.Status = Rejected
+	}
+	b.orders = append(b.orders, o)
+	b.history = append(b.history, o)
+	return o.ID
+}
+
+// execute open orders against the new bar
+func (b *broker) match(c indikators.Candle) {
+	open := b.orders[:0]
+	for _, o := range b.orders {
+		if o.Status != Open {
+			continue
+		}
+
+		price, fee, ok := b.fillPrice(o, c)
+		if ok {
+			b.fill(o, price, fee, c)
+		}
+		if o.Status == Open {
+			open = append(open, o)
+		}
+	}
+	b.orders = open
+}
+
+func (b *broker) fillPrice(o *Order, c indikators.Candle) (float64, float64, bool) {
+	if o.Type == Market {
+		if o.Side == Buy {
+			return c.Open * (1 + b.cfg.Slippage), b.cfg.TakerFee, true
+		}
+		return c.Open * (1 - b.cfg.Slippage), b.cfg.TakerFee, true
+	}
+
+	// a limit order is filled at its price, or at the open when the bar
+	// gaps through it. The gap fill crosses the book at the open and pays
+	// the taker fee.
+	if o.Side == Buy && c.Low <= o.Price {
+		if c.Open < o.Price {
+			return c.Open, b.cfg.TakerFee, true
+		}
+		return o.Price, b.cfg.MakerFee, true
+	}
+	if o.Side == Sell && c.High >= o.Price {
+		if c.Open > o.Price {
+			return c.Open, b.cfg.TakerFee, true
+		}
+		return o.Price, b.cfg.MakerFee, true
+	}
+	return 0, 0, false
+}
+
+func (b *broker) fill(o *Order, price, feeRate float64, c indikators.Candle) {
+	// a sell of the whole position in parts leaves float dust, it closes
+	// what is left
+	if o.Side == Sell && o.Qty > b.qty && o.Qty <= b.qty+epsilon(b.qty) {
+		o.Qty = b.qty
+	}
+	value := o.Qty * price
+	fee := value * feeRate
+
+	f := Fill{
+		OrderID: o.ID,
+		Side:    o.Side,
+		Qty:     o.Qty,
+		Price:   price,
+		Fee:     fee,
+		Time:    c.Time,
+	}
+
+	if o.Side == Buy {
+		// spot account, no margin
+		if value+fee > b.cash {
+			o.Status = Rejected
+			return
+		}
+		b.cash -= value + fee
+		b.avgPrice = (b.avgPrice*b.qty + value) / (b.qty + o.Qty)
+		b.qty += o.Qty
+		b.entryFee += fee
+	} else {
+		// no short selling
+		if o.Qty > b.qty {
+			o.Status = Rejected
+			return
+		}
+		entryFee := b.entryFee * o.Qty / b.qty
+		b.entryFee -= entryFee
+		b.cash += value - fee
+		f.Pnl = (price-b.avgPrice)*o.Qty - entryFee - fee
+		if b.qty-o.Qty <= epsilon(b.qty) {
+			b.qty, b.avgPrice, b.entryFee = 0, 0, 0
+		} else {
+			b.qty -= o.Qty
+		}
+	}
+
+	o.Status = Filled
+	b.fills = append(b.fills, f)
+}
+
+// epsilon absorbs float rounding when comparing quantities to v
+func epsilon(v float64) float64 {
+	return math.Abs(v) * 1e-9
+}
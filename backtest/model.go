@@ -0,0 +1,102 @@
+package backtest
+
+import (
+	"time"
+)
+
+type Side int
+
+const (
+	Buy Side = iota
+	Sell
+)
+
+func (s Side) String() string {
+	if s == Sell {
+		return "sell"
+	}
+	return "buy"
+}
+
+type OrderType int
+
+const (
+	Market OrderType = iota
+	Limit
+)
+
+func (t OrderType) String() string {
+	if t == Limit {
+		return "limit"
+	}
+	return "market"
+}
+
+type OrderStatus int
+
+const (
+	Open OrderStatus = iota
+	Filled
+	Cancelled
+	Rejected
+)
+
+func (s OrderStatus) String() string {
+	switch s {
+	case Filled:
+		return "filled"
+	case Cancelled:
+		return "cancelled"
+	case Rejected:
+		return "rejected"
+	default:
+		return "open"
+	}
+}
+
+// Order placed by a strategy. Price is only used by limit orders.
+type Order struct {
+	ID     int64       `json:"id"`
+	Side   Side        `json:"side"`
+	Type   OrderType   `json:"type"`
+	Qty    float64     `json:"qty"`
+	Price  float64     `json:"price"`
+	Status OrderStatus `json:"status"`
+	Time   time.Time   `json:"time"`
+}
+
+// Fill is a single execution, Pnl is the realised profit of a sell
+// (after both entry and exit fees) and zero for buys.
+type Fill struct {
+	OrderID int64     `json:"order_id"`
+	Side    Side      `json:"side"`
+	Qty     float64   `json:"qty"`
+	Price   float64   `json:"price"`
+	Fee     float64   `json:"fee"`
+	Pnl     float64   `json:"pnl"`
+	Time    time.Time `json:"time"`
+}
+
+type EquityPoint struct {
+	Time   time.Time `json:"time"`
+	Cash   float64   `json:"cash"`
+	Qty    float64   `json:"qty"`
+	Equity float64   `json:"equity"`
+}
+
+// Config of a backtest run. Fees and slippage are fractions of the traded
+// value, e.g. 0.003 for 0.3%.
+type Config struct {
+	// Starting cash in quote currency
+	Cash float64
+	// Fee of orders taking liquidity (market orders and limit orders the
+	// open gaps through)
+	TakerFee float64
+	// Fee of orders adding liquidity (limit orders filled at their price)
+	MakerFee float64
+	// Price penalty applied to market orders
+	Slippage float64
+	// Bars per year used to annualise the Sharpe ratio, e.g. 365 for daily
+	// candles of a 24/7 crypto market. Zero leaves Sharpe per bar.
+	PeriodsPerYear float64
+}
@@ -0,0 +1,90 @@
+package chart_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/chart"
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+// candles with open, high, low, close given per bar
+func ohlc(bars ...[4]float64) []indikators.Candle {
+	t := time.Date(2023, 1, 1, 0, 0, 0, 0, indikators.Jakarta)
+	out := make([]indikators.Candle, len(bars))
+	for i, b := range bars {
+		out[i] = indikators.Candle{
+			Time:   t.AddDate(0, 0, i),
+			Open:   b[0],
+			High:   b[1],
+			Low:    b[2],
+			Close:  b[3],
+			Volume: 1,
+		}
+	}
+	return out
+}
+
+func closes(prices ...float64) []indikators.Candle {
+	bars := make([][4]float64, len(prices))
+	for i, p := range prices {
+		bars[i] = [4]float64{p, p + 0.5, p - 0.5, p}
+	}
+	return ohlc(bars...)
+}
+
+func TestHeikinAshi(t *testing.T) {
+	out := chart.HeikinAshiArr(ohlc([4]float64{10, 12, 9, 11}, [4]float64{11, 13, 10, 12}))
+
+	assert.Equal(t, [4]float64{10.5, 12, 9, 10.5}, [4]float64{out[0].Open, out[0].High, out[0].Low, out[0].Close}, "they should be equal")
+	assert.Equal(t, [4]float64{10.5, 13, 10, 11.5}, [4]float64{out[1].Open, out[1].High, out[1].Low, out[1].Close}, "they should be equal")
+}
+
+func TestRenko(t *testing.T) {
+	in := closes(10, 11.5, 12.2, 10.5, 9.9, 12.1)
+	out := chart.RenkoArr(in, 1)
+
+	var got [][2]float64
+	var vols []float64
+	for _, b := range out {
+		got = append(got, [2]float64{b.Open, b.Close})
+		vols = append(vols, b.Volume)
+	}
+	assert.Equal(t, [][2]float64{{10, 11}, {11, 12}, {11, 10}, {11, 12}}, got, "they should be equal")
+	assert.Equal(t, []float64{2, 1, 2, 1}, vols, "they should be equal")
+	assert.Equal(t, in[5].Time, out[3].Time, "they should be equal")
+
+	assert.Empty(t, chart.AtrRenkoArr(in, 14), "it should be empty")
+}
+
+func TestPointAndFigure(t *testing.T) {
+	out := chart.PointAndFigureArr(ohlc(
+		[4]float64{10, 10, 10, 10},
+		[4]float64{10, 12.3, 10, 12},
+		[4]float64{12, 13.5, 11, 13},
+		[4]float64{13, 12, 10.5, 11},
+		[4]float64{11, 11, 9.2, 10},
+		[4]float64{10, 12, 8, 9},
+	), 1, 3)
+
+	assert.Len(t, out, 2, "they should be equal")
+	assert.True(t, out[0].Up, "it should be true")
+	assert.Equal(t, [3]float64{10, 13, 3}, [3]float64{out[0].Open, out[0].Close, float64(out[0].Boxes)}, "they should be equal")
+	assert.Equal(t, 3.0, out[0].Volume, "they should be equal")
+	assert.False(t, out[1].Up, "it should be false")
+	assert.Equal(t, [3]float64{13, 8, 5}, [3]float64{out[1].Open, out[1].Close, float64(out[1].Boxes)}, "they should be equal")
+}
+
+func TestKagi(t *testing.T) {
+	out := chart.KagiArr(closes(10, 11, 13, 12, 10.5, 14, 9), 2)
+
+	var got [][2]float64
+	var yang []bool
+	for _, l := range out {
+		got = append(got, [2]float64{l.Open, l.Close})
+		yang = append(yang, l.Yang)
+	}
+	assert.Equal(t, [][2]float64{{10, 13}, {13, 10.5}, {10.5, 14}, {14, 9}}, got, "they should be equal")
+	assert.Equal(t, []bool{true, true, true, false}, yang, "they should be equal")
+}
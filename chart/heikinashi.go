@@ -0,0 +1,54 @@
+// Package chart transforms candle streams into noise filtered charts:
+// Heikin-Ashi candles, Renko bricks, Point-and-Figure columns and Kagi
+// lines. Every transform is fed one candle at a time and returns what it
+// completed, so the output can be fed straight back into indikators.
+package chart
+
+import (
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// Heikin-Ashi ("average bar") candles smooth the price action: the close
+// is the average of the bar's OHLC and the open the midpoint of the
+// previous Heikin-Ashi body, trends show as long runs of one colour.
+//  https://www.investopedia.com/trading/heikin-ashi-better-candlestick/
+//  https://school.stockcharts.com/doku.php?id=chart_analysis:heikin_ashi
+type HeikinAshi struct {
+	prev indikators.Candle
+	sz   int64
+}
+
+func NewHeikinAshi() *HeikinAshi {
+	return &HeikinAshi{}
+}
+
+func (h *HeikinAshi) Update(c indikators.Candle) indikators.Candle {
+	h.sz++
+
+	close := (c.Open + c.High + c.Low + c.Close) / 4.0
+	open := (c.Open + c.Close) / 2.0
+	if h.sz > 1 {
+		open = (h.prev.Open + h.prev.Close) / 2.0
+	}
+
+	h.prev = indikators.Candle{
+		Time:   c.Time,
+		Open:   open,
+		High:   max(c.High, max(open, close)),
+		Low:    min(c.Low, min(open, close)),
+		Close:  close,
+		Volume: c.Volume,
+	}
+	return h.prev
+}
+
+func HeikinAshiArr(in []indikators.Candle) []indikators.Candle {
+	out := make([]indikators.Candle, len(in))
+
+	h := NewHeikinAshi()
+	for i, c := range in {
+		out[i] = h.Update(c)
+	}
+
+	return out
+}
@@ -0,0 +1,151 @@
+package chart
+
+import (
+	"math"
+
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// KagiLine is a vertical Kagi line from Open to Close. Time is the candle
+// it started on.
+type KagiLine struct {
+	indikators.Candle
+	// thick line, price broke above the previous shoulder and has not
+	// fallen below the previous waist since
+	Yang bool
+}
+
+// Kagi charts follow the close in one line until it reverses by the
+// reversal amount, then a new line is drawn the other way. The line turns
+// thick (yang) when it rises above the previous high (shoulder) and thin
+// (yin) when it falls below the previous low (waist).
+//  https://www.investopedia.com/terms/k/kagichart.asp
+//  https://school.stockcharts.com/doku.php?id=chart_analysis:kagi
+type Kagi struct {
+	amount  float64
+	pct     bool
+	line    KagiLine
+	dir     int
+	started bool
+	// last high and low the line turned at, NaN until known
+	shoulder float64
+	waist    float64
+}
+
+// NewKagi reverses on a move of amount price units
+func NewKagi(amount float64) *Kagi {
+	return newKagi(amount, false)
+}
+
+// NewKagiPercent reverses on a move of pct percent of the turning price
+func NewKagiPercent(pct float64) *Kagi {
+	return newKagi(pct, true)
+}
+
+func newKagi(amount float64, pct bool) *Kagi {
+	return &Kagi{
+		amount:   amount,
+		pct:      pct,
+		shoulder: math.NaN(),
+		waist:    math.NaN(),
+	}
+}
+
+// Update returns the line completed by c, if any
+func (k *Kagi) Update(c indikators.Candle) (KagiLine, bool) {
+	v := c.Close
+
+	if !k.started {
+		k.started = true
+		k.start(c, v)
+		return KagiLine{}, false
+	}
+
+	end := k.line.Close
+	switch {
+	case k.dir == 0:
+		if math.Abs(v-end) >= k.reversal(end) {
+			k.dir = 1
+			if v < end {
+				k.dir = -1
+			}
+			k.line.Yang = k.dir > 0
+			k.extend(v)
+		}
+	case k.dir > 0 && v > end, k.dir < 0 && v < end:
+		k.extend(v)
+	case math.Abs(v-end) >= k.reversal(end):
+		done := k.line
+		if k.dir > 0 {
+			k.shoulder = end
+		} else {
+			k.waist = end
+		}
+		k.dir = -k.dir
+		yang := done.Yang
+		k.start(c, end)
+		k.line.Yang = yang
+		k.extend(v)
+		return done, true
+	}
+	k.line.Volume += c.Volume
+	return KagiLine{}, false
+}
+
+// Current is the line still in progress, false before the first one
+func (k *Kagi) Current() (KagiLine, bool) {
+	return k.line, k.dir != 0
+}
+
+func (k *Kagi) reversal(from float64) float64 {
+	if k.pct {
+		return math.Abs(from) * k.amount / 100.0
+	}
+	return k.amount
+}
+
+func (k *Kagi) start(c indikators.Candle, from float64) {
+	k.line = KagiLine{
+		Candle: indikators.Candle{
+			Time:   c.Time,
+			Open:   from,
+			High:   from,
+			Low:    from,
+			Close:  from,
+			Volume: c.Volume,
+		},
+	}
+}
+
+func (k *Kagi) extend(to float64) {
+	k.line.Close = to
+	k.line.High = max(k.line.Open, to)
+	k.line.Low = min(k.line.Open, to)
+	if to > k.shoulder {
+		k.line.Yang = true
+	} else if to < k.waist {
+		k.line.Yang = false
+	}
+}
+
+// KagiArr returns the completed lines followed by the one in progress
+func KagiArr(in []indikators.Candle, amount float64) []KagiLine {
+	return kagiArr(NewKagi(amount), in)
+}
+
+func KagiPercentArr(in []indikators.Candle, pct float64) []KagiLine {
+	return kagiArr(NewKagiPercent(pct), in)
+}
+
+func kagiArr(k *Kagi, in []indikators.Candle) []KagiLine {
+	var out []KagiLine
+	for _, c := range in {
+		if line, ok := k.Update(c); ok {
+			out = append(out, line)
+		}
+	}
+	if line, ok := k.Current(); ok {
+		out = append(out, line)
+	}
+	return out
+}
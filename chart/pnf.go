@@ -0,0 +1,132 @@
+package chart
+
+import (
+	"math"
+
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// PnfColumn is a Point-and-Figure column. Open and Close are the box
+// levels the column starts and ends at, Time is the candle it started on
+// and Volume the volume traded while it was the current column.
+type PnfColumn struct {
+	indikators.Candle
+	// column of Xs (rising) or Os (falling)
+	Up    bool
+	Boxes int
+}
+
+// Point-and-Figure charts stack Xs while the high keeps reaching a new
+// box and Os while the low does, a new column starts once price reverses
+// by reversal boxes. This is the high/low method, prices are snapped to
+// multiples of the box size.
+//  https://school.stockcharts.com/doku.php?id=chart_analysis:pnf_charts
+//  https://www.investopedia.com/terms/p/pointandfigurechart.asp
+type PointAndFigure struct {
+	box      float64
+	reversal int
+	// first close, snapped, until the first column starts
+	ref     float64
+	col     PnfColumn
+	started bool
+}
+
+func NewPointAndFigure(box float64, reversal int) *PointAndFigure {
+	return &PointAndFigure{
+		box:      box,
+		reversal: reversal,
+	}
+}
+
+// Update returns the column completed by c, if any
+func (p *PointAndFigure) Update(c indikators.Candle) (PnfColumn, bool) {
+	if !p.started {
+		p.started = true
+		p.ref = p.floor(c.Close)
+		return PnfColumn{}, false
+	}
+
+	if p.col.Boxes == 0 {
+		switch {
+		case c.High >= p.ref+p.box:
+			p.column(c, p.ref, p.floor(c.High))
+		case c.Low <= p.ref-p.box:
+			p.column(c, p.ref, p.ceil(c.Low))
+		}
+		return PnfColumn{}, false
+	}
+
+	end := p.col.Close
+	rev := float64(p.reversal) * p.box
+	if p.col.Up {
+		if c.High >= end+p.box {
+			p.extend(p.floor(c.High))
+		} else if c.Low <= end-rev {
+			done := p.col
+			p.column(c, end, p.ceil(c.Low))
+			return done, true
+		}
+	} else {
+		if c.Low <= end-p.box {
+			p.extend(p.ceil(c.Low))
+		} else if c.High >= end+rev {
+			done := p.col
+			p.column(c, end, p.floor(c.High))
+			return done, true
+		}
+	}
+	p.col.Volume += c.Volume
+	return PnfColumn{}, false
+}
+
+// Current is the column still in progress, false before the first one
+func (p *PointAndFigure) Current() (PnfColumn, bool) {
+	return p.col, p.col.Boxes > 0
+}
+
+// column starts a new column on c going from one box level to another
+func (p *PointAndFigure) column(c indikators.Candle, from, to float64) {
+	p.col = PnfColumn{
+		Candle: indikators.Candle{
+			Time:   c.Time,
+			Open:   from,
+			Volume: c.Volume,
+		},
+		Up: to > from,
+	}
+	p.extend(to)
+}
+
+func (p *PointAndFigure) extend(to float64) {
+	p.col.Close = to
+	p.col.High = max(p.col.Open, to)
+	p.col.Low = min(p.col.Open, to)
+	p.col.Boxes = int(math.Round(math.Abs(to-p.col.Open) / p.box))
+}
+
+// snap to the box grid, tolerating float error on exact multiples
+func (p *PointAndFigure) floor(v float64) float64 {
+	return math.Floor(v/p.box+1e-9) * p.box
+}
+
+func (p *PointAndFigure) ceil(v float64) float64 {
+	return math.Ceil(v/p.box-1e-9) * p.box
+}
+
+// PointAndFigureArr returns the completed columns followed by the one in
+// progress
+func PointAndFigureArr(in []indikators.Candle, box float64, reversal int) []PnfColumn {
+	var out []PnfColumn
+
+	p := NewPointAndFigure(box, reversal)
+	for _, c := range in {
+		if col, ok := p.Update(c); ok {
+			out = append(out, col)
+		}
+	}
+	if col, ok := p.Current(); ok {
+		out = append(out, col)
+	}
+
+	return out
+}
@@ -0,0 +1,107 @@
+package chart
+
+import (
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// Renko charts ignore time and only draw a brick when the close moved a
+// full box size past the previous brick, a reversal needs two boxes.
+// Bricks are candles with Open and Close at the brick bounds, timed at
+// the candle that completed them. The volume traded since the previous
+// brick is carried by the first brick of a batch.
+//  https://www.investopedia.com/terms/r/renkochart.asp
+//  https://school.stockcharts.com/doku.php?id=chart_analysis:renko
+type Renko struct {
+	box float64
+	atr *indikators.Atr
+	// close of the last brick, or the first close before any brick
+	last float64
+	// 1 after an up brick, -1 after a down brick
+	dir     int
+	started bool
+	vol     float64
+}
+
+// NewRenko draws bricks of a fixed box size
+func NewRenko(box float64) *Renko {
+	return &Renko{
+		box: box,
+	}
+}
+
+// NewAtrRenko sizes every brick by the ATR(n) of the candle completing
+// it, nothing is drawn until the ATR is valid
+func NewAtrRenko(n int64) *Renko {
+	return &Renko{
+		atr: indikators.NewAtr(n),
+	}
+}
+
+// Update returns the bricks completed by c, usually none
+func (r *Renko) Update(c indikators.Candle) []indikators.Candle {
+	r.vol += c.Volume
+
+	box := r.box
+	if r.atr != nil {
+		box = r.atr.Update(c)
+		if !r.atr.Valid() {
+			return nil
+		}
+	}
+	if box <= 0 {
+		return nil
+	}
+
+	if !r.started {
+		r.started = true
+		r.last = c.Close
+		return nil
+	}
+
+	var out []indikators.Candle
+	for {
+		var open, close float64
+		switch {
+		case r.dir >= 0 && c.Close >= r.last+box:
+			open, close = r.last, r.last+box
+			r.dir = 1
+		case r.dir <= 0 && c.Close <= r.last-box:
+			open, close = r.last, r.last-box
+			r.dir = -1
+		case r.dir > 0 && c.Close <= r.last-2*box:
+			open, close = r.last-box, r.last-2*box
+			r.dir = -1
+		case r.dir < 0 && c.Close >= r.last+2*box:
+			open, close = r.last+box, r.last+2*box
+			r.dir = 1
+		default:
+			return out
+		}
+		r.last = close
+		out = append(out, indikators.Candle{
+			Time:   c.Time,
+			Open:   open,
+			High:   max(open, close),
+			Low:    min(open, close),
+			Close:  close,
+			Volume: r.vol,
+		})
+		r.vol = 0
+	}
+}
+
+func RenkoArr(in []indikators.Candle, box float64) []indikators.Candle {
+	return renkoArr(NewRenko(box), in)
+}
+
+func AtrRenkoArr(in []indikators.Candle, n int64) []indikators.Candle {
+	return renkoArr(NewAtrRenko(n), in)
+}
+
+func renkoArr(r *Renko, in []indikators.Candle) []indikators.Candle {
+	var out []indikators.Candle
+	for _, c := range in {
+		out = append(out, r.Update(c)...)
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fatiri/areuy/csvreader"
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/xuri/excelize/v2"
+)
+
+// header names accepted for every candle field
+var columnNames = map[string][]string{
+	"time":   {"time", "date", "datetime", "timestamp"},
+	"open":   {"open", "o"},
+	"high":   {"high", "h"},
+	"low":    {"low", "l"},
+	"close":  {"close", "c", "price"},
+	"volume": {"volume", "vol", "v"},
+}
+
+// time layouts tried when -time-format is not given
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func readCandles(path, sheet string, stdin io.Reader, timeFormat string, loc *time.Location) ([]indikators.Candle, error) {
+	var rows [][]string
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".xlsx") {
+		rows, err = readXLSX(path, sheet)
+	} else {
+		rows, err = readCSV(path, stdin)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseCandles(rows, timeFormat, loc)
+}
+
+func readCSV(path string, stdin io.Reader) ([][]string, error) {
+	var buf bytes.Buffer
+	if path == "-" {
+		if _, err := buf.ReadFrom(stdin); err != nil {
+			return nil, err
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+
+	r := csvreader.NewCsvReader()
+	r.Open(&buf)
+	return r.ReadLine()
+}
+
+func readXLSX(path, sheet string) ([][]string, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if sheet == "" {
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("workbook has no sheet")
+		}
+		sheet = sheets[0]
+	}
+	return f.GetRows(sheet)
+}
+
+func parseCandles(rows [][]string, timeFormat string, loc *time.Location) ([]indikators.Candle, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("input is empty")
+	}
+
+	idx := map[string]int{}
+	for i, h := range rows[0] {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for field, names := range columnNames {
+			for _, name := range names {
+				if _, ok := idx[field]; !ok && h == name {
+					idx[field] = i
+				}
+			}
+		}
+	}
+	for _, field := range []string{"time", "open", "high", "low", "close"} {
+		if _, ok := idx[field]; !ok {
+			return nil, fmt.Errorf("input has no %s column", field)
+		}
+	}
+
+	out := make([]indikators.Candle, 0, len(rows)-1)
+	for n, row := range rows[1:] {
+		line := n + 2
+		if len(row) == 0 || len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		cell := func(field string) string {
+			i, ok := idx[field]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		var c indikators.Candle
+		var err error
+		if c.Time, err = parseTime(cell("time"), timeFormat, loc); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		for _, f := range []struct {
+			field string
+			dst   *float64
+		}{{"open", &c.Open}, {"high", &c.High}, {"low", &c.Low}, {"close", &c.Close}, {"volume", &c.Volume}} {
+			s := cell(f.field)
+			if s == "" && f.field == "volume" {
+				continue
+			}
+			if *f.dst, err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s %q", line, f.field, s)
+			}
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// parseTime reads s with layout, or detects the format when layout is
+// empty. Numbers are unix seconds, or milliseconds when too large to be
+// seconds.
+func parseTime(s, layout string, loc *time.Location) (time.Time, error) {
+	if layout != "" {
+		return time.ParseInLocation(layout, s, loc)
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n > 1e11 {
+			return time.UnixMilli(n).In(loc), nil
+		}
+		return time.Unix(n, 0).In(loc), nil
+	}
+	for _, l := range timeLayouts {
+		if t, err := time.ParseInLocation(l, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, set -time-format", s)
+}
@@ -0,0 +1,211 @@
+// Command areuy-ta computes indicators over an OHLCV price file.
+//
+//	areuy-ta -in btcidr.csv -i rsi -i "bbands:n=20,up=2,dn=2" -i "ema:n=12,name=ema12" -out out.xlsx
+//
+// The input is a CSV or XLSX file with a header row naming the time,
+// open, high, low, close and (optional) volume columns. Indicators are
+// given as type[:key=value,...] with the parameters of indikators.Build,
+// or as a JSON object. The output is CSV, JSON lines or an XLSX workbook,
+// chosen by -format or the extension of -out.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/json"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "areuy-ta:", err)
+		os.Exit(1)
+	}
+}
+
+// specs collects the repeated -i flags
+type specs []json.Object
+
+func (s *specs) String() string {
+	return fmt.Sprint(*s)
+}
+
+func (s *specs) Set(v string) error {
+	p, err := parseSpec(v)
+	if err != nil {
+		return err
+	}
+	*s = append(*s, p)
+	return nil
+}
+
+type options struct {
+	in         string
+	sheet      string
+	out        string
+	format     string
+	timeFormat string
+	tz         string
+	nan        bool
+	indicators specs
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	var o options
+	fs := flag.NewFlagSet("areuy-ta", flag.ContinueOnError)
+	fs.StringVar(&o.in, "in", "-", "input CSV or XLSX file, - reads CSV from stdin")
+	fs.StringVar(&o.sheet, "sheet", "", "XLSX input sheet, the first one by default")
+	fs.StringVar(&o.out, "out", "-", "output file, - writes to stdout")
+	fs.StringVar(&o.format, "format", "", "output format: csv, jsonl or xlsx, from the -out extension by default")
+	fs.StringVar(&o.timeFormat, "time-format", "", "Go layout of the time column, RFC 3339, \"2006-01-02 15:04:05\" and unix seconds or milliseconds are detected by default")
+	fs.StringVar(&o.tz, "tz", "Asia/Jakarta", "timezone of times without an offset")
+	fs.BoolVar(&o.nan, "nan", false, "leave values of indicators still in their warm-up empty")
+	fs.Var(&o.indicators, "i", "indicator as type[:key=value,...] or a JSON object, repeatable")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(o.indicators) == 0 {
+		return errors.New("at least one indicator (-i) is required")
+	}
+
+	format := o.format
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(o.out)), ".")
+		if format != "jsonl" && format != "xlsx" {
+			format = "csv"
+		}
+	}
+	if format != "csv" && format != "jsonl" && format != "xlsx" {
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	loc := indikators.Jakarta
+	if o.tz != "Asia/Jakarta" {
+		var err error
+		if loc, err = time.LoadLocation(o.tz); err != nil {
+			return err
+		}
+	}
+
+	inds, columns, err := build(o.indicators)
+	if err != nil {
+		return err
+	}
+
+	candles, err := readCandles(o.in, o.sheet, stdin, o.timeFormat, loc)
+	if err != nil {
+		return err
+	}
+
+	rows := compute(candles, inds, o.nan)
+
+	if o.out == "-" {
+		return write(stdout, format, columns, candles, rows)
+	}
+	return writeFile(o.out, format, columns, candles, rows)
+}
+
+// writeFile writes aside and renames, a failed write never leaves a
+// truncated file in place of the previous output
+func writeFile(name, format string, columns []string, candles []indikators.Candle, rows [][]float64) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+"-*")
+	if err != nil {
+		return err
+	}
+	if err := write(tmp, format, columns, candles, rows); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// parseSpec reads type[:key=value,...] or a JSON object. Values that
+// parse as numbers are numbers, anything else a string.
+func parseSpec(v string) (json.Object, error) {
+	v = strings.TrimSpace(v)
+	if strings.HasPrefix(v, "{") {
+		return json.Parse([]byte(v))
+	}
+
+	name, params, _ := strings.Cut(v, ":")
+	p := json.Object{"type": strings.TrimSpace(name)}
+	if params == "" {
+		return p, nil
+	}
+	for _, kv := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid indicator parameter %q in %q, want key=value", kv, v)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			p[key] = f
+		} else {
+			p[key] = value
+		}
+	}
+	return p, nil
+}
+
+// build creates the indicators and names their output columns, "name"
+// for single output indicators and "name.output" otherwise
+func build(specs []json.Object) ([]indikators.Indicator, []string, error) {
+	var inds []indikators.Indicator
+	var columns []string
+	seen := map[string]bool{}
+	for _, p := range specs {
+		ind, err := indikators.Build(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		name := p.GetStringOr("name", p.GetString("type"))
+		if seen[name] {
+			return nil, nil, fmt.Errorf("duplicate indicator name %q, set name= to tell them apart", name)
+		}
+		seen[name] = true
+
+		outs := ind.Outputs()
+		for _, out := range outs {
+			if len(outs) == 1 {
+				columns = append(columns, name)
+			} else {
+				columns = append(columns, name+"."+out)
+			}
+		}
+		inds = append(inds, ind)
+	}
+	return inds, columns, nil
+}
+
+// compute feeds every candle to every indicator. Values of indicators in
+// their warm-up are NaN when nan is set.
+func compute(candles []indikators.Candle, inds []indikators.Indicator, nan bool) [][]float64 {
+	rows := make([][]float64, len(candles))
+	for i, c := range candles {
+		var row []float64
+		for _, ind := range inds {
+			vals := ind.Update(c)
+			if nan && !ind.Valid() {
+				for j := range vals {
+					vals[j] = nanValue
+				}
+			}
+			row = append(row, vals...)
+		}
+		rows[i] = row
+	}
+	return rows
+}
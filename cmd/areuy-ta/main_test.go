@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fatiri/areuy/json"
+	"github.com/stretchr/testify/assert"
+)
+
+const prices = `Date,Open,High,Low,Close,Volume
+2024-01-01,1,2,0.5,1.5,10
+2024-01-02,1.5,2.5,1,2,11
+2024-01-03,2,3,1.5,2.5,12
+`
+
+func TestParseSpec(t *testing.T) {
+	p, err := parseSpec("bbands: n=20, ma=ema")
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, json.Object{"type": "bbands", "n": 20.0, "ma": "ema"}, p, "they should be equal")
+
+	p, err = parseSpec(`{"type":"rsi","n":14}`)
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, "rsi", p.GetString("type"), "they should be equal")
+
+	_, err = parseSpec("rsi:14")
+	assert.Error(t, err, "they should be error")
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name                string
+		args                []string
+		funcUseCaseShouldBe func(t *testing.T, out string, err error)
+	}{
+		{
+			name: "CSV with warm-up values left empty",
+			args: []string{"-i", "sma:n=2", "-i", "sma:n=3,name=slow", "-nan"},
+			funcUseCaseShouldBe: func(t *testing.T, out string, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, `time,open,high,low,close,volume,sma,slow
+2024-01-01T00:00:00+07:00,1,2,0.5,1.5,10,,
+2024-01-02T00:00:00+07:00,1.5,2.5,1,2,11,1.75,
+2024-01-03T00:00:00+07:00,2,3,1.5,2.5,12,2.25,2
+`, out, "they should be equal")
+			},
+		},
+		{
+			name: "JSON lines",
+			args: []string{"-i", "sma:n=2", "-format", "jsonl", "-nan"},
+			funcUseCaseShouldBe: func(t *testing.T, out string, err error) {
+				assert.NoError(t, err, "they should be no error")
+				lines := strings.Split(strings.TrimSpace(out), "\n")
+				assert.Len(t, lines, 3, "they should be equal")
+				assert.Contains(t, lines[0], `"sma":null`, "they should contain")
+				assert.Contains(t, lines[2], `"sma":2.25`, "they should contain")
+			},
+		},
+		{
+			name: "Duplicate indicator names",
+			args: []string{"-i", "sma", "-i", "sma:n=5"},
+			funcUseCaseShouldBe: func(t *testing.T, out string, err error) {
+				assert.EqualError(t, err, `duplicate indicator name "sma", set name= to tell them apart`, "they should be equal")
+			},
+		},
+		{
+			name: "No indicator",
+			args: []string{},
+			funcUseCaseShouldBe: func(t *testing.T, out string, err error) {
+				assert.EqualError(t, err, "at least one indicator (-i) is required", "they should be equal")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run(test.args, strings.NewReader(prices), &out)
+			test.funcUseCaseShouldBe(t, out.String(), err)
+		})
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.csv")
+
+	err := run([]string{"-i", "sma:n=2", "-out", out}, strings.NewReader(prices), nil)
+	assert.NoError(t, err, "they should be no error")
+	data, err := os.ReadFile(out)
+	assert.NoError(t, err, "they should be no error")
+	assert.True(t, strings.HasPrefix(string(data), "time,open,high,low,close,volume,sma\n"), "it should be the CSV output")
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err, "they should be no error")
+	assert.Len(t, entries, 1, "the temp file should be renamed")
+
+	err = run([]string{"-i", "sma", "-out", filepath.Join(dir, "missing", "out.csv")}, strings.NewReader(prices), nil)
+	assert.Error(t, err, "they should be error")
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Fatiri/areuy/excel"
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/json"
+)
+
+var nanValue = math.NaN()
+
+var candleColumns = []string{"time", "open", "high", "low", "close", "volume"}
+
+func write(w io.Writer, format string, columns []string, candles []indikators.Candle, rows [][]float64) error {
+	switch format {
+	case "jsonl":
+		return writeJSONL(w, columns, candles, rows)
+	case "xlsx":
+		return writeXLSX(w, columns, candles, rows)
+	default:
+		return writeCSV(w, columns, candles, rows)
+	}
+}
+
+func writeCSV(w io.Writer, columns []string, candles []indikators.Candle, rows [][]float64) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(append([]string{}, candleColumns...), columns...)); err != nil {
+		return err
+	}
+	for i, c := range candles {
+		record := []string{
+			c.Time.Format(time.RFC3339),
+			formatFloat(c.Open),
+			formatFloat(c.High),
+			formatFloat(c.Low),
+			formatFloat(c.Close),
+			formatFloat(c.Volume),
+		}
+		for _, v := range rows[i] {
+			record = append(record, formatFloat(v))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJSONL(w io.Writer, columns []string, candles []indikators.Candle, rows [][]float64) error {
+	bw := bufio.NewWriter(w)
+	for i, c := range candles {
+		obj := json.Object{
+			"time":   c.Time.Format(time.RFC3339),
+			"open":   c.Open,
+			"high":   c.High,
+			"low":    c.Low,
+			"close":  c.Close,
+			"volume": c.Volume,
+		}
+		for j, v := range rows[i] {
+			// JSON has no NaN
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				obj[columns[j]] = nil
+			} else {
+				obj[columns[j]] = v
+			}
+		}
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		bw.Write(data)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func writeXLSX(w io.Writer, columns []string, candles []indikators.Candle, rows [][]float64) error {
+	data := make([]interface{}, len(candles))
+	for i, c := range candles {
+		row := []interface{}{c.Time.Format("2006-01-02 15:04:05"), c.Open, c.High, c.Low, c.Close, c.Volume}
+		for _, v := range rows[i] {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				row = append(row, nil)
+			} else {
+				row = append(row, v)
+			}
+		}
+		data[i] = row
+	}
+
+	f, err := excel.GenerateXLSX("indicators", append(append([]string{}, candleColumns...), columns...), data)
+	if err != nil {
+		return err
+	}
+	if err := f.Write(w); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// formatFloat writes NaN as an empty cell
+func formatFloat(v float64) string {
+	if math.IsNaN(v) {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
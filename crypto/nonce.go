@@ -0,0 +1,90 @@
+package crypto
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NonceSource issues the timestamp or nonce of a signed request
+type NonceSource interface {
+	Next() (int64, error)
+}
+
+// NonceStore shares the last nonce between processes, e.g.
+// storage.RedisNonce
+type NonceStore interface {
+	// Reserve atomically stores and returns min, or the last reserved
+	// nonce plus one when that is larger
+	Reserve(ctx context.Context, min int64) (int64, error)
+}
+
+// NonceConfig of a Nonce
+type NonceConfig struct {
+	// Store makes nonces increase across processes sharing it, nil keeps
+	// them increasing within this process only
+	Store NonceStore
+	// Now is time.Now when nil
+	Now func() time.Time
+}
+
+// Nonce issues strictly increasing millisecond timestamps, corrected by
+// the offset to the exchange clock found by Sync. It is safe for
+// concurrent use.
+type Nonce struct {
+	mu     sync.Mutex
+	cfg    NonceConfig
+	last   int64
+	offset time.Duration
+}
+
+// NewNonce returns a nonce source
+func NewNonce(cfg NonceConfig) *Nonce {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Nonce{cfg: cfg}
+}
+
+// Next returns the exchange time in milliseconds, or the previous nonce
+// plus one when the clock did not move forward
+func (n *Nonce) Next() (int64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	next := n.cfg.Now().Add(n.offset).UnixMilli()
+	if next <= n.last {
+		next = n.last + 1
+	}
+	if n.cfg.Store != nil {
+		var err error
+		if next, err = n.cfg.Store.Reserve(context.Background(), next); err != nil {
+			return 0, err
+		}
+	}
+	n.last = next
+	return next, nil
+}
+
+// Sync sets the clock offset from the exchange server time returned by
+// fetch, assuming the server read its clock halfway through the call
+func (n *Nonce) Sync(fetch func() (time.Time, error)) error {
+	start := n.cfg.Now()
+	server, err := fetch()
+	if err != nil {
+		return err
+	}
+	end := n.cfg.Now()
+
+	n.mu.Lock()
+	n.offset = server.Sub(start.Add(end.Sub(start) / 2))
+	n.mu.Unlock()
+	return nil
+}
+
+// Offset is the exchange clock minus the local clock
+func (n *Nonce) Offset() time.Duration {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.offset
+}
@@ -0,0 +1,84 @@
+package crypto_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// memStore stands in for storage.RedisNonce
+type memStore struct {
+	mu   sync.Mutex
+	last int64
+}
+
+func (s *memStore) Reserve(_ context.Context, min int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if min <= s.last {
+		min = s.last + 1
+	}
+	s.last = min
+	return min, nil
+}
+
+func TestNonce(t *testing.T) {
+	clock := time.UnixMilli(1000)
+	now := func() time.Time { return clock }
+
+	n := crypto.NewNonce(crypto.NonceConfig{Now: now})
+	a, _ := n.Next()
+	b, _ := n.Next()
+	assert.Equal(t, []int64{1000, 1001}, []int64{a, b}, "they should increase on a still clock")
+
+	clock = time.UnixMilli(900)
+	c, _ := n.Next()
+	assert.Equal(t, int64(1002), c, "they should increase when the clock goes back")
+
+	err := n.Sync(func() (time.Time, error) { return time.UnixMilli(5900), nil })
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, 5*time.Second, n.Offset(), "they should be equal")
+	d, _ := n.Next()
+	assert.Equal(t, int64(5900), d, "they should follow the server clock")
+
+	err = n.Sync(func() (time.Time, error) { return time.Time{}, errors.New("timeout") })
+	assert.EqualError(t, err, "timeout", "they should be equal")
+	assert.Equal(t, 5*time.Second, n.Offset(), "they should keep the last offset")
+}
+
+func TestNonceConcurrent(t *testing.T) {
+	store := &memStore{}
+	// two processes with the same frozen clock sharing a store
+	now := func() time.Time { return time.UnixMilli(1000) }
+	sources := []*crypto.Nonce{
+		crypto.NewNonce(crypto.NonceConfig{Store: store, Now: now}),
+		crypto.NewNonce(crypto.NonceConfig{Store: store, Now: now}),
+	}
+
+	var mu sync.Mutex
+	seen := map[int64]bool{}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(n *crypto.Nonce) {
+			defer wg.Done()
+			var prev int64
+			for j := 0; j < 100; j++ {
+				v, err := n.Next()
+				assert.NoError(t, err, "they should be no error")
+				assert.Greater(t, v, prev, "they should increase")
+				prev = v
+				mu.Lock()
+				seen[v] = true
+				mu.Unlock()
+			}
+		}(sources[i%2])
+	}
+	wg.Wait()
+	assert.Len(t, seen, 800, "they should never repeat")
+}
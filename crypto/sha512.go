@@ -1,12 +1,10 @@
 package crypto
 
 import (
-	"crypto/hmac"
-	"crypto/sha512"
-	"encoding/hex"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type PayloadSHA512 struct {
@@ -24,30 +22,58 @@ type PayloadSHA512 struct {
 }
 
 func (p *PayloadSHA512) GenerateHMACSHA512() string {
-	// Create a new HMAC by defining the hash type and the key (as byte array)
-	h := hmac.New(sha512.New, []byte(p.PrivateKey))
-	h.Write([]byte(p.Data))
-	// Get result and encode as hexadecimal string
-	sha := hex.EncodeToString(h.Sum(nil))
+	sha, _ := NewHMACSHA512(p.PrivateKey).Sign([]byte(p.Data))
 
 	return sha
 }
 
+// GenerateIndodax signs any Indodax private API request. The caller sets
+// method, timestamp or nonce and the method parameters in data.
+//
+// Deprecated: use NewIndodaxAuth(NewHMACSHA512(secret), recvWindow).Sign,
+// it stamps the timestamp and recvWindow itself
+func (p *PayloadSHA512) GenerateIndodax(data url.Values) (string, string) {
+	p.Data = data.Encode()
+	sha := p.GenerateHMACSHA512()
+
+	return sha, p.Data
+}
+
+// sign signs params with auth, stamped with Timestamp (the current time
+// when 0) and RecvWindow in milliseconds
+func (p *PayloadSHA512) sign(auth Auth, params url.Values) SignedRequest {
+	if p.Timestamp != 0 {
+		auth.Timestamp = func() int64 { return p.Timestamp }
+	}
+	if ms, err := strconv.ParseInt(p.RecvWindow, 10, 64); err == nil {
+		auth.RecvWindow = time.Duration(ms) * time.Millisecond
+	}
+	// the HMAC signers never fail
+	req, _ := auth.Sign(params)
+	p.Data = req.Payload
+
+	return req
+}
+
+// indodax signs params with NewIndodaxAuth
+func (p *PayloadSHA512) indodax(params url.Values) (string, string) {
+	req := p.sign(NewIndodaxAuth(NewHMACSHA512(p.PrivateKey), 0), params)
+
+	return req.Signature, req.Payload
+}
+
+// Deprecated: use NewIndodaxAuth(NewHMACSHA512(secret), recvWindow).Sign
 func (p *PayloadSHA512) GenerateGetInfoIndodax() (string, string) {
 	data := url.Values{}
 	data.Set("method", p.Method)
-	data.Set("timestamp", strconv.Itoa(int(p.Timestamp)))
-	data.Set("recWindow", p.RecvWindow)
-	p.Data = data.Encode()
-	sha := p.GenerateHMACSHA512()
 
-	return sha, data.Encode()
+	return p.indodax(data)
 }
 
+// Deprecated: use NewIndodaxAuth(NewHMACSHA512(secret), recvWindow).Sign
 func (p *PayloadSHA512) GenerateOrderIndodax() (string, string) {
 	data := url.Values{}
 	data.Set("method", p.Method)
-	data.Set("timestamp", strconv.Itoa(int(p.Timestamp)))
 	data.Set("pair", p.Pair)
 	data.Set("type", p.Type)
 	data.Set("price", p.Price)
@@ -59,47 +85,40 @@ func (p *PayloadSHA512) GenerateOrderIndodax() (string, string) {
 		data.Set("btc", p.BTC)
 	}
 
-	p.Data = data.Encode()
-	sha := p.GenerateHMACSHA512()
-
-	return sha, data.Encode()
+	return p.indodax(data)
 }
 
+// Deprecated: use NewIndodaxAuth(NewHMACSHA512(secret), recvWindow).Sign
 func (p *PayloadSHA512) GenerateGetOrderIndodax() (string, string) {
 	data := url.Values{}
 	data.Set("method", p.Method)
-	data.Set("timestamp", strconv.Itoa(int(p.Timestamp)))
 	data.Set("pair", p.Pair)
-	data.Set("order_id", strconv.Itoa(int(p.OrderID)))
+	data.Set("order_id", strconv.FormatInt(p.OrderID, 10))
 
-	p.Data = data.Encode()
-	sha := p.GenerateHMACSHA512()
-
-	return sha, data.Encode()
+	return p.indodax(data)
 }
 
+// Deprecated: use NewIndodaxAuth(NewHMACSHA512(secret), recvWindow).Sign
 func (p *PayloadSHA512) GenerateGetOrderHistoryIndodax() (string, string) {
 	data := url.Values{}
 	data.Set("method", p.Method)
 	data.Set("pair", p.Pair)
-	data.Set("nonce", strconv.Itoa(int(p.Timestamp)))
-
-	p.Data = data.Encode()
-	sha := p.GenerateHMACSHA512()
 
-	return sha, data.Encode()
+	return p.indodax(data)
 }
 
+// GenerateMarketDataTokoCrypto returns the params signed with
+// NewBinanceAuth, the HMAC-SHA256 of the query in the signature param.
+//
+// Deprecated: use NewBinanceAuth(NewHMACSHA256(secret), recvWindow).Sign
 func (p *PayloadSHA512) GenerateMarketDataTokoCrypto() url.Values {
 	data := url.Values{}
 	data.Set("method", p.Method)
-	data.Set("timestamp", strconv.Itoa(int(p.Timestamp)))
 	data.Set("pair", p.Pair)
-	data.Set("order_id", strconv.Itoa(int(p.OrderID)))
+	data.Set("order_id", strconv.FormatInt(p.OrderID, 10))
 
-	p.Data = data.Encode()
-	sha := p.GenerateHMACSHA512()
-	data.Set("siganture", sha)
+	req := p.sign(NewBinanceAuth(NewHMACSHA256(p.PrivateKey), 0), data)
+	req.Params.Set("signature", req.Signature)
 
-	return data
+	return req.Params
 }
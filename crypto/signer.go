@@ -0,0 +1,199 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"hash"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Signer signs the canonical payload of an exchange request
+type Signer interface {
+	Sign(payload []byte) (string, error)
+}
+
+type hmacSigner struct {
+	hash   func() hash.Hash
+	secret []byte
+}
+
+// NewHMACSHA256 signs with HMAC-SHA256 as hex, the Binance and Tokocrypto
+// scheme
+func NewHMACSHA256(secret string) Signer {
+	return hmacSigner{hash: sha256.New, secret: []byte(secret)}
+}
+
+// NewHMACSHA512 signs with HMAC-SHA512 as hex, the Indodax scheme
+func NewHMACSHA512(secret string) Signer {
+	return hmacSigner{hash: sha512.New, secret: []byte(secret)}
+}
+
+func (s hmacSigner) Sign(payload []byte) (string, error) {
+	h := hmac.New(s.hash, s.secret)
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewEd25519 signs with an Ed25519 key as standard base64, the scheme of
+// Binance Ed25519 API keys
+func NewEd25519(key ed25519.PrivateKey) (Signer, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("crypto: invalid ed25519 private key size")
+	}
+	return ed25519Signer{key: key}, nil
+}
+
+// ParseEd25519PEM reads a PKCS #8 "PRIVATE KEY" PEM block, the format
+// exchanges ask to generate keys in
+func ParseEd25519PEM(data []byte) (Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("crypto: no PEM block found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ed, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("crypto: PEM key is not an ed25519 key")
+	}
+	return NewEd25519(ed)
+}
+
+func (s ed25519Signer) Sign(payload []byte) (string, error) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, payload)), nil
+}
+
+// CanonicalQuery encodes params sorted by key, so the signed payload is
+// the same whatever order the params were set in
+func CanonicalQuery(params url.Values) string {
+	return params.Encode()
+}
+
+// CanonicalJSON encodes v with object keys sorted and no insignificant
+// whitespace. Numbers are kept as written.
+func CanonicalJSON(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	// maps are encoded sorted by key
+	return json.Marshal(generic)
+}
+
+// Auth adds the timestamp and recvWindow params to a request and signs
+// its canonical query
+type Auth struct {
+	Signer        Signer
+	TimestampKey  string
+	RecvWindowKey string
+	// RecvWindow is sent in milliseconds, zero leaves it out
+	RecvWindow time.Duration
+	// SignatureKey appends the signature to the encoded params, empty
+	// leaves it to the caller, e.g. for a header
+	SignatureKey string
+	// Nonce issues the timestamp param, Timestamp is used when nil
+	Nonce NonceSource
+	// Timestamp returns the request time in milliseconds, time.Now when nil
+	Timestamp func() int64
+}
+
+// NewBinanceAuth signs like Binance and Tokocrypto: timestamp and
+// recvWindow params and a signature param at the end of the query
+func NewBinanceAuth(s Signer, recvWindow time.Duration) Auth {
+	return Auth{
+		Signer:        s,
+		TimestampKey:  "timestamp",
+		RecvWindowKey: "recvWindow",
+		RecvWindow:    recvWindow,
+		SignatureKey:  "signature",
+	}
+}
+
+// NewIndodaxAuth signs like the Indodax private API: timestamp and
+// recvWindow params, the signature is sent in the Sign header
+func NewIndodaxAuth(s Signer, recvWindow time.Duration) Auth {
+	return Auth{
+		Signer:        s,
+		TimestampKey:  "timestamp",
+		RecvWindowKey: "recvWindow",
+		RecvWindow:    recvWindow,
+	}
+}
+
+// SignedRequest is the result of Auth.Sign
+type SignedRequest struct {
+	// Params with the timestamp and recvWindow added
+	Params url.Values
+	// Payload is the canonical query that was signed
+	Payload   string
+	Signature string
+	// Encoded is the query or form body to send, the payload followed by
+	// the signature param when Auth.SignatureKey is set
+	Encoded string
+}
+
+// Sign stamps and signs params. params is not modified.
+func (a Auth) Sign(params url.Values) (SignedRequest, error) {
+	if a.Signer == nil {
+		return SignedRequest{}, errors.New("crypto: auth has no signer")
+	}
+
+	out := url.Values{}
+	for k, v := range params {
+		out[k] = append([]string(nil), v...)
+	}
+	if a.TimestampKey != "" {
+		ts := time.Now().UnixMilli()
+		switch {
+		case a.Nonce != nil:
+			var err error
+			if ts, err = a.Nonce.Next(); err != nil {
+				return SignedRequest{}, err
+			}
+		case a.Timestamp != nil:
+			ts = a.Timestamp()
+		}
+		out.Set(a.TimestampKey, strconv.FormatInt(ts, 10))
+	}
+	if a.RecvWindowKey != "" && a.RecvWindow > 0 {
+		out.Set(a.RecvWindowKey, strconv.FormatInt(a.RecvWindow.Milliseconds(), 10))
+	}
+
+	payload := CanonicalQuery(out)
+	sign, err := a.Signer.Sign([]byte(payload))
+	if err != nil {
+		return SignedRequest{}, err
+	}
+
+	encoded := payload
+	if a.SignatureKey != "" {
+		if encoded != "" {
+			encoded += "&"
+		}
+		encoded += url.QueryEscape(a.SignatureKey) + "=" + url.QueryEscape(sign)
+	}
+	return SignedRequest{Params: out, Payload: payload, Signature: sign, Encoded: encoded}, nil
+}
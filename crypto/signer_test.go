@@ -0,0 +1,179 @@
+package crypto_test
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	// example of the Binance API docs
+	payload := []byte("symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559")
+	secret := "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j"
+
+	sign, err := crypto.NewHMACSHA256(secret).Sign(payload)
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, "c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71", sign, "they should be equal")
+
+	p := crypto.PayloadSHA512{Data: string(payload), PrivateKey: secret}
+	sign, err = crypto.NewHMACSHA512(secret).Sign(payload)
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, p.GenerateHMACSHA512(), sign, "they should be equal")
+	assert.Len(t, sign, 128, "they should be equal")
+
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	signer, err := crypto.ParseEd25519PEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	assert.NoError(t, err, "they should be no error")
+	sign, err = signer.Sign(payload)
+	assert.NoError(t, err, "they should be no error")
+	raw, _ := base64.StdEncoding.DecodeString(sign)
+	assert.True(t, ed25519.Verify(key.Public().(ed25519.PublicKey), payload, raw), "it should verify")
+
+	_, err = crypto.ParseEd25519PEM([]byte("no key"))
+	assert.EqualError(t, err, "crypto: no PEM block found", "they should be equal")
+}
+
+func TestAuth(t *testing.T) {
+	ts := func() int64 { return 1499827319559 }
+
+	tests := []struct {
+		name                string
+		auth                crypto.Auth
+		funcUseCaseShouldBe func(t *testing.T, req crypto.SignedRequest, err error)
+	}{
+		{
+			name: "Binance appends the signature param",
+			auth: crypto.NewBinanceAuth(crypto.NewHMACSHA256("secret"), 5*time.Second),
+			funcUseCaseShouldBe: func(t *testing.T, req crypto.SignedRequest, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, "quantity=1&recvWindow=5000&symbol=LTCBTC&timestamp=1499827319559", req.Payload, "they should be equal")
+				assert.Equal(t, req.Payload+"&signature="+req.Signature, req.Encoded, "they should be equal")
+			},
+		},
+		{
+			name: "Indodax leaves the signature to the header",
+			auth: crypto.NewIndodaxAuth(crypto.NewHMACSHA512("secret"), 0),
+			funcUseCaseShouldBe: func(t *testing.T, req crypto.SignedRequest, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, "quantity=1&symbol=LTCBTC&timestamp=1499827319559", req.Encoded, "they should be equal")
+				p := crypto.PayloadSHA512{Data: req.Payload, PrivateKey: "secret"}
+				assert.Equal(t, p.GenerateHMACSHA512(), req.Signature, "they should be equal")
+			},
+		},
+		{
+			name: "No signer",
+			auth: crypto.Auth{},
+			funcUseCaseShouldBe: func(t *testing.T, req crypto.SignedRequest, err error) {
+				assert.EqualError(t, err, "crypto: auth has no signer", "they should be equal")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := url.Values{"symbol": {"LTCBTC"}, "quantity": {"1"}}
+			test.auth.Timestamp = ts
+			req, err := test.auth.Sign(params)
+			test.funcUseCaseShouldBe(t, req, err)
+			assert.Len(t, params, 2, "they should not modify the params")
+		})
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	b, err := crypto.CanonicalJSON(struct {
+		Symbol   string  `json:"symbol"`
+		Quantity string  `json:"quantity"`
+		Price    float64 `json:"price"`
+	}{"LTCBTC", "1", 0.1})
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, `{"price":0.1,"quantity":"1","symbol":"LTCBTC"}`, string(b), "they should be equal")
+}
+
+func TestPayloadSHA512Indodax(t *testing.T) {
+	auth := crypto.NewIndodaxAuth(crypto.NewHMACSHA512("secret"), 5*time.Second)
+	auth.Timestamp = func() int64 { return 1578304294000 }
+
+	tests := []struct {
+		name     string
+		generate func(p *crypto.PayloadSHA512) (string, string)
+		params   url.Values
+	}{
+		{
+			name:     "getInfo",
+			generate: (*crypto.PayloadSHA512).GenerateGetInfoIndodax,
+			params:   url.Values{"method": {"getInfo"}},
+		},
+		{
+			name:     "trade",
+			generate: (*crypto.PayloadSHA512).GenerateOrderIndodax,
+			params:   url.Values{"method": {"trade"}, "pair": {"btc_idr"}, "type": {"buy"}, "price": {"900000000"}, "idr": {"100000"}},
+		},
+		{
+			name:     "getOrder",
+			generate: (*crypto.PayloadSHA512).GenerateGetOrderIndodax,
+			params:   url.Values{"method": {"getOrder"}, "pair": {"btc_idr"}, "order_id": {"59639504"}},
+		},
+		{
+			name:     "orderHistory",
+			generate: (*crypto.PayloadSHA512).GenerateGetOrderHistoryIndodax,
+			params:   url.Values{"method": {"orderHistory"}, "pair": {"btc_idr"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &crypto.PayloadSHA512{
+				Method:     test.params.Get("method"),
+				Timestamp:  1578304294000,
+				RecvWindow: "5000",
+				Type:       test.params.Get("type"),
+				Price:      test.params.Get("price"),
+				IDR:        test.params.Get("idr"),
+				Pair:       test.params.Get("pair"),
+				OrderID:    59639504,
+				PrivateKey: "secret",
+			}
+			sign, data := test.generate(p)
+
+			want, err := auth.Sign(test.params)
+			assert.NoError(t, err, "they should be no error")
+			assert.Equal(t, want.Payload, data, "they should be equal")
+			assert.Equal(t, want.Signature, sign, "they should be equal")
+			assert.Contains(t, data, "recvWindow=5000&timestamp=1578304294000", "they should contain")
+		})
+	}
+}
+
+func TestPayloadSHA512TokoCrypto(t *testing.T) {
+	auth := crypto.NewBinanceAuth(crypto.NewHMACSHA256("secret"), 5*time.Second)
+	auth.Timestamp = func() int64 { return 1578304294000 }
+	want, err := auth.Sign(url.Values{"method": {"order"}, "pair": {"BTC_USDT"}, "order_id": {"59639504"}})
+	assert.NoError(t, err, "they should be no error")
+
+	p := &crypto.PayloadSHA512{
+		Method:     "order",
+		Timestamp:  1578304294000,
+		RecvWindow: "5000",
+		Pair:       "BTC_USDT",
+		OrderID:    59639504,
+		PrivateKey: "secret",
+	}
+	data := p.GenerateMarketDataTokoCrypto()
+
+	assert.Equal(t, want.Signature, data.Get("signature"), "they should be equal")
+	assert.Len(t, want.Signature, 64, "it should be a hex HMAC-SHA256")
+	assert.NotContains(t, data, "siganture", "they should not contain")
+	data.Del("signature")
+	assert.Equal(t, want.Params, data, "they should be equal")
+	assert.Equal(t, "1578304294000", data.Get("timestamp"), "they should be equal")
+	assert.Equal(t, "5000", data.Get("recvWindow"), "they should be equal")
+}
@@ -0,0 +1,314 @@
+// Package indodax is a client of the Indodax private trade API.
+//
+// Every call is a form POST to the tapi endpoint signed with
+// crypto.NewIndodaxAuth, the Key and Sign headers carry the API key and
+// the HMAC-SHA512 of the body.
+//
+//	https://github.com/btcid/indodax-official-api-docs/blob/master/Private-RestAPI.md
+package indodax
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fatiri/areuy/crypto"
+	"github.com/Fatiri/areuy/net"
+)
+
+const (
+	// DefaultBaseURL is the endpoint of the private API
+	DefaultBaseURL = "https://indodax.com/tapi"
+	// DefaultPublicURL is the root of the public API
+	DefaultPublicURL = "https://indodax.com/api"
+)
+
+// Config of a Client
+type Config struct {
+	BaseURL   string
+	PublicURL string
+	Key       string
+	Secret    string
+	// RecvWindow is how long after its timestamp a request is still
+	// accepted, zero leaves the exchange default (5s)
+	RecvWindow time.Duration
+	// Nonce issues the request timestamps. Share a crypto.Nonce between
+	// the clients of an API key so they never reuse one.
+	Nonce crypto.NonceSource
+	// Timestamp returns the request timestamp in milliseconds when Nonce
+	// is nil, time.Now by default
+	Timestamp func() int64
+}
+
+// DefaultConfig returns a Config for the given API key and secret
+func DefaultConfig(key, secret string) Config {
+	return Config{
+		BaseURL:   DefaultBaseURL,
+		PublicURL: DefaultPublicURL,
+		Key:       key,
+		Secret:    secret,
+	}
+}
+
+// API is the method set of the private API. It is implemented by Client
+// and by the paper trading simulator.
+type API interface {
+	GetInfo() (*Info, error)
+	TransHistory() (*TransHistory, error)
+	Trade(o TradeRequest) (*TradeResult, error)
+	TradeHistory(q TradeHistoryQuery) ([]Trade, error)
+	OpenOrders(pair string) (map[string][]Order, error)
+	OrderHistory(pair string, count int) ([]Order, error)
+	GetOrder(pair string, orderID int64) (*Order, error)
+	CancelOrder(pair string, orderID int64, side Side) (*CancelResult, error)
+	Withdraw(w WithdrawRequest) (*WithdrawResult, error)
+}
+
+var _ API = (*Client)(nil)
+
+// Client of the private API
+type Client struct {
+	cfg  Config
+	auth crypto.Auth
+	http net.IHTTPClient
+}
+
+// NewClient returns a client sending requests with hc
+func NewClient(cfg Config, hc net.IHTTPClient) *Client {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = DefaultPublicURL
+	}
+	auth := crypto.NewIndodaxAuth(crypto.NewHMACSHA512(cfg.Secret), cfg.RecvWindow)
+	auth.Nonce = cfg.Nonce
+	auth.Timestamp = cfg.Timestamp
+	return &Client{cfg: cfg, auth: auth, http: hc}
+}
+
+// ServerTime returns the exchange clock from the public API, pass it to
+// crypto.Nonce.Sync to correct the skew of the local clock
+func (c *Client) ServerTime() (time.Time, error) {
+	response, err := c.http.Invoke(&net.ParamaterHttpClient{
+		URL:    strings.TrimSuffix(c.cfg.PublicURL, "/") + "/server_time",
+		Method: http.MethodGet,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer response.Body.Close()
+
+	raw, err := c.http.ReadHttpResponse(response)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return time.Time{}, &APIError{Method: "server_time", StatusCode: response.StatusCode, Message: strings.TrimSpace(string(raw))}
+	}
+	var out struct {
+		ServerTime Int `json:"server_time"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return time.Time{}, decodeError("server_time", err)
+	}
+	return time.UnixMilli(int64(out.ServerTime)), nil
+}
+
+// GetInfo returns the balances and the profile of the account
+func (c *Client) GetInfo() (*Info, error) {
+	var out Info
+	if err := c.call("getInfo", url.Values{}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TransHistory returns the deposits and withdrawals of the account
+func (c *Client) TransHistory() (*TransHistory, error) {
+	var out TransHistory
+	if err := c.call("transHistory", url.Values{}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Trade places an order
+func (c *Client) Trade(o TradeRequest) (*TradeResult, error) {
+	data, err := o.values()
+	if err != nil {
+		return nil, err
+	}
+	var out TradeResult
+	if err := c.call("trade", data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TradeHistory returns the fills of a pair, newest first
+func (c *Client) TradeHistory(q TradeHistoryQuery) ([]Trade, error) {
+	if q.Pair == "" {
+		return nil, errors.New("indodax: pair is required")
+	}
+	var out struct {
+		Trades []Trade `json:"trades"`
+	}
+	if err := c.call("tradeHistory", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return out.Trades, nil
+}
+
+// OpenOrders returns the open orders by pair. An empty pair returns the
+// open orders of every pair.
+func (c *Client) OpenOrders(pair string) (map[string][]Order, error) {
+	data := url.Values{}
+	if pair != "" {
+		data.Set("pair", pair)
+	}
+	var out struct {
+		Orders json.RawMessage `json:"orders"`
+	}
+	if err := c.call("openOrders", data, &out); err != nil {
+		return nil, err
+	}
+
+	orders := map[string][]Order{}
+	if len(out.Orders) == 0 || string(out.Orders) == "null" {
+		return orders, nil
+	}
+	// a single pair is a list, every pair an object of lists
+	if pair != "" {
+		var list []Order
+		if err := json.Unmarshal(out.Orders, &list); err != nil {
+			return nil, decodeError("openOrders", err)
+		}
+		orders[pair] = list
+		return orders, nil
+	}
+	if err := json.Unmarshal(out.Orders, &orders); err != nil {
+		return nil, decodeError("openOrders", err)
+	}
+	return orders, nil
+}
+
+// OrderHistory returns the last count orders of a pair, 100 when count
+// is zero
+func (c *Client) OrderHistory(pair string, count int) ([]Order, error) {
+	if pair == "" {
+		return nil, errors.New("indodax: pair is required")
+	}
+	data := url.Values{}
+	data.Set("pair", pair)
+	if count > 0 {
+		data.Set("count", strconv.Itoa(count))
+	}
+	var out struct {
+		Orders []Order `json:"orders"`
+	}
+	if err := c.call("orderHistory", data, &out); err != nil {
+		return nil, err
+	}
+	return out.Orders, nil
+}
+
+// GetOrder returns an order of a pair
+func (c *Client) GetOrder(pair string, orderID int64) (*Order, error) {
+	data := url.Values{}
+	data.Set("pair", pair)
+	data.Set("order_id", strconv.FormatInt(orderID, 10))
+	var out struct {
+		Order Order `json:"order"`
+	}
+	if err := c.call("getOrder", data, &out); err != nil {
+		return nil, err
+	}
+	return &out.Order, nil
+}
+
+// CancelOrder cancels an open order, side is buy or sell
+func (c *Client) CancelOrder(pair string, orderID int64, side Side) (*CancelResult, error) {
+	data := url.Values{}
+	data.Set("pair", pair)
+	data.Set("order_id", strconv.FormatInt(orderID, 10))
+	data.Set("type", string(side))
+	var out CancelResult
+	if err := c.call("cancelOrder", data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Withdraw sends coins to an address. The API key needs the withdraw
+// permission and the account a callback URL confirming request IDs.
+func (c *Client) Withdraw(w WithdrawRequest) (*WithdrawResult, error) {
+	data, err := w.values()
+	if err != nil {
+		return nil, err
+	}
+	var out WithdrawResult
+	if err := c.call("withdrawCoin", data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// envelope of every response
+type envelope struct {
+	Success   int             `json:"success"`
+	Return    json.RawMessage `json:"return"`
+	Error     string          `json:"error"`
+	ErrorCode string          `json:"error_code"`
+}
+
+// call signs and sends method with data and decodes its return into out
+func (c *Client) call(method string, data url.Values, out interface{}) error {
+	data.Set("method", method)
+	req, err := c.auth.Sign(data)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.http.Invoke(&net.ParamaterHttpClient{
+		URL:         c.cfg.BaseURL,
+		Method:      http.MethodPost,
+		ContentType: "application/x-www-form-urlencoded",
+		BodyRequest: []byte(req.Encoded),
+		Headers: []net.RequestHttpClient{
+			{Key: "Key", Value: c.cfg.Key},
+			{Key: "Sign", Value: req.Signature},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	raw, err := c.http.ReadHttpResponse(response)
+	if err != nil {
+		return err
+	}
+
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		if response.StatusCode != http.StatusOK {
+			return &APIError{Method: method, StatusCode: response.StatusCode, Message: strings.TrimSpace(string(raw))}
+		}
+		return decodeError(method, err)
+	}
+	if env.Success != 1 {
+		return &APIError{Method: method, StatusCode: response.StatusCode, Code: env.ErrorCode, Message: env.Error}
+	}
+	if out == nil || len(env.Return) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Return, out); err != nil {
+		return decodeError(method, err)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package indodax_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/crypto"
+	"github.com/Fatiri/areuy/exchange/indodax"
+	"github.com/Fatiri/areuy/net"
+	"github.com/stretchr/testify/assert"
+)
+
+// server answers every method with its canned response after checking the
+// signature, the request form is passed to check
+func server(responses map[string]string, check func(form url.Values)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		h := hmac.New(sha512.New, []byte("secret"))
+		h.Write(body)
+		if r.Header.Get("Key") != "key" || r.Header.Get("Sign") != hex.EncodeToString(h.Sum(nil)) {
+			w.Write([]byte(`{"success":0,"error":"Invalid credentials. API not found or session has expired.","error_code":"invalid_credentials"}`))
+			return
+		}
+
+		form, _ := url.ParseQuery(string(body))
+		if check != nil {
+			check(form)
+		}
+		resp, ok := responses[form.Get("method")]
+		if !ok {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("<html>bad gateway</html>"))
+			return
+		}
+		w.Write([]byte(resp))
+	}))
+}
+
+func client(url, secret string) *indodax.Client {
+	cfg := indodax.DefaultConfig("key", secret)
+	cfg.BaseURL = url
+	cfg.Timestamp = func() int64 { return 1578304294000 }
+	return indodax.NewClient(cfg, net.ProvideIHTTPClient())
+}
+
+func TestClient(t *testing.T) {
+	responses := map[string]string{
+		"getInfo":      `{"success":1,"return":{"server_time":1578304294,"balance":{"idr":1000000,"btc":"0.01500000"},"balance_hold":{"idr":0,"btc":"0.00000000"},"user_id":"123","name":"Jhon"}}`,
+		"trade":        `{"success":1,"return":{"receive_btc":"0.00000000","spend_rp":100000,"fee":0,"remain_rp":"900000","order_id":11560}}`,
+		"tradeHistory": `{"success":1,"return":{"trades":[{"trade_id":"3","order_id":"11560","type":"sell","btc":"0.00100000","price":"98000000","fee":"0","trade_time":"1578304294"}]}}`,
+		"getOrder":     `{"success":1,"return":{"order":{"order_id":"11560","price":"99000000","type":"buy","order_idr":"100000","remain_idr":"50000","submit_time":"1578304294","finish_time":"0","status":"open"}}}`,
+		"orderHistory": `{"success":1,"return":{"orders":[{"order_id":"11561","type":"sell","price":"98000000","submit_time":"1578304294","finish_time":"1578304300","status":"filled","order_btc":"0.00100000","remain_btc":"0.00000000"}]}}`,
+	}
+
+	var form url.Values
+	srv := server(responses, func(f url.Values) { form = f })
+	defer srv.Close()
+	c := client(srv.URL, "secret")
+
+	tests := []struct {
+		name                string
+		funcUseCaseShouldBe func(t *testing.T)
+	}{
+		{
+			name: "getInfo decodes string and number balances",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				info, err := c.GetInfo()
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, indodax.Number(1000000), info.Balance["idr"], "they should be equal")
+				assert.Equal(t, indodax.Number(0.015), info.Balance["btc"], "they should be equal")
+				assert.Equal(t, int64(1578304294), info.ServerTime.Time().Unix(), "they should be equal")
+				assert.Equal(t, "1578304294000", form.Get("timestamp"), "they should be equal")
+			},
+		},
+		{
+			name: "Buy sends the amount in the quote currency",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				res, err := c.Trade(indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Buy, Price: 99000000, Amount: 100000})
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, "100000", form.Get("idr"), "they should be equal")
+				assert.Equal(t, "99000000", form.Get("price"), "they should be equal")
+				assert.Equal(t, indodax.Int(11560), res.OrderID, "they should be equal")
+				assert.Equal(t, indodax.Number(900000), res.Amounts["remain_rp"], "they should be equal")
+			},
+		},
+		{
+			name: "Sell sends the amount in the base coin",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				_, err := c.Trade(indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Sell, Type: indodax.Market, Amount: 0.001})
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, "0.001", form.Get("btc"), "they should be equal")
+				assert.Equal(t, "market", form.Get("order_type"), "they should be equal")
+			},
+		},
+		{
+			name: "Invalid trade is not sent",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				_, err := c.Trade(indodax.TradeRequest{Pair: "btcidr", Side: indodax.Buy, Price: 1, Amount: 1})
+				assert.EqualError(t, err, "indodax: pair must be base_quote, e.g. btc_idr", "they should be equal")
+			},
+		},
+		{
+			name: "tradeHistory keeps the coin amount",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				trades, err := c.TradeHistory(indodax.TradeHistoryQuery{Pair: "btc_idr", Count: 10})
+				assert.NoError(t, err, "they should be no error")
+				assert.Len(t, trades, 1, "they should be equal")
+				assert.Equal(t, indodax.Number(0.001), trades[0].Amounts["btc"], "they should be equal")
+				assert.Equal(t, indodax.Sell, trades[0].Type, "they should be equal")
+				assert.Equal(t, "10", form.Get("count"), "they should be equal")
+			},
+		},
+		{
+			name: "getOrder",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				o, err := c.GetOrder("btc_idr", 11560)
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, "open", o.Status, "they should be equal")
+				assert.Equal(t, map[string]indodax.Number{"order_idr": 100000, "remain_idr": 50000}, o.Amounts, "they should be equal")
+				assert.Equal(t, "11560", form.Get("order_id"), "they should be equal")
+			},
+		},
+		{
+			name: "orderHistory",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				orders, err := c.OrderHistory("btc_idr", 5)
+				assert.NoError(t, err, "they should be no error")
+				assert.Len(t, orders, 1, "they should be equal")
+				assert.Equal(t, "filled", orders[0].Status, "they should be equal")
+				assert.Equal(t, "5", form.Get("count"), "they should be equal")
+			},
+		},
+		{
+			name: "Failed calls return no result",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				failing := client(srv.URL, "wrong")
+				info, err := failing.GetInfo()
+				assert.Error(t, err, "they should be error")
+				assert.Nil(t, info, "they should be nil")
+				trades, err := failing.TradeHistory(indodax.TradeHistoryQuery{Pair: "btc_idr"})
+				assert.Error(t, err, "they should be error")
+				assert.Nil(t, trades, "they should be nil")
+				orders, err := failing.OrderHistory("btc_idr", 0)
+				assert.Error(t, err, "they should be error")
+				assert.Nil(t, orders, "they should be nil")
+			},
+		},
+		{
+			name: "Non JSON error page",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				_, err := c.CancelOrder("btc_idr", 1, indodax.Buy)
+				var apiErr *indodax.APIError
+				assert.True(t, errors.As(err, &apiErr), "it should be an APIError")
+				assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode, "they should be equal")
+				assert.EqualError(t, err, "indodax: cancelOrder: <html>bad gateway</html> (status 502)", "they should be equal")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.funcUseCaseShouldBe(t)
+		})
+	}
+}
+
+func TestInvalidCredentials(t *testing.T) {
+	srv := server(nil, nil)
+	defer srv.Close()
+
+	_, err := client(srv.URL, "wrong").GetInfo()
+	assert.True(t, indodax.IsCode(err, indodax.CodeInvalidCredentials), "it should be invalid credentials")
+	assert.EqualError(t, err, "indodax: getInfo: Invalid credentials. API not found or session has expired. (invalid_credentials)", "they should be equal")
+}
+
+func TestOpenOrders(t *testing.T) {
+	single := server(map[string]string{
+		"openOrders": `{"success":1,"return":{"orders":[{"order_id":"1","type":"buy","price":"100","order_idr":"5000","remain_idr":"5000"}]}}`,
+	}, nil)
+	defer single.Close()
+	orders, err := client(single.URL, "secret").OpenOrders("btc_idr")
+	assert.NoError(t, err, "they should be no error")
+	assert.Len(t, orders["btc_idr"], 1, "they should be equal")
+
+	all := server(map[string]string{
+		"openOrders": `{"success":1,"return":{"orders":{"btc_idr":[{"order_id":"1","type":"buy"}],"eth_idr":[{"order_id":"2","type":"sell"},{"order_id":"3","type":"sell"}]}}}`,
+	}, nil)
+	defer all.Close()
+	orders, err = client(all.URL, "secret").OpenOrders("")
+	assert.NoError(t, err, "they should be no error")
+	assert.Len(t, orders["eth_idr"], 2, "they should be equal")
+	assert.Equal(t, indodax.Int(3), orders["eth_idr"][1].OrderID, "they should be equal")
+}
+
+func TestServerTime(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/server_time", r.URL.Path, "they should be equal")
+		w.Write([]byte(`{"timezone":"UTC","server_time":1571205969552}`))
+	}))
+	defer srv.Close()
+
+	cfg := indodax.DefaultConfig("key", "secret")
+	cfg.PublicURL = srv.URL + "/api"
+	nonce := crypto.NewNonce(crypto.NonceConfig{})
+	cfg.Nonce = nonce
+	c := indodax.NewClient(cfg, net.ProvideIHTTPClient())
+
+	st, err := c.ServerTime()
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, int64(1571205969552), st.UnixMilli(), "they should be equal")
+
+	assert.NoError(t, nonce.Sync(c.ServerTime), "they should be no error")
+	assert.Less(t, nonce.Offset(), time.Duration(0), "they should be behind the local clock")
+}
@@ -0,0 +1,51 @@
+package indodax
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CodeInvalidCredentials is returned for an unknown key or a bad sign
+const CodeInvalidCredentials = "invalid_credentials"
+
+// APIError is a request the exchange refused, either with success 0 or a
+// non JSON error page
+type APIError struct {
+	Method     string
+	StatusCode int
+	// Code is the error_code of the response, empty for old style errors
+	// that only have a message
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("indodax: %s: %s (%s)", e.Method, e.Message, e.Code)
+	}
+	return fmt.Sprintf("indodax: %s: %s (status %d)", e.Method, e.Message, e.StatusCode)
+}
+
+// IsCode reports whether err is an APIError with the given code
+func IsCode(err error, code string) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.Code == code
+}
+
+// DecodeError is a response that does not match the expected shape
+type DecodeError struct {
+	Method string
+	Err    error
+}
+
+func decodeError(method string, err error) error {
+	return &DecodeError{Method: method, Err: err}
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("indodax: %s: decode response: %v", e.Method, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,367 @@
+package indodax
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Number is a decimal the API sends either as a JSON number or a string
+type Number float64
+
+func (n *Number) UnmarshalJSON(b []byte) error {
+	s := string(bytes.Trim(b, `"`))
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*n = Number(f)
+	return nil
+}
+
+// Int is an integer (IDs, unix times) sent as a number or a string
+type Int int64
+
+func (n *Int) UnmarshalJSON(b []byte) error {
+	s := string(bytes.Trim(b, `"`))
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		// some times are sent as decimals
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil {
+			return err
+		}
+		i = int64(f)
+	}
+	*n = Int(i)
+	return nil
+}
+
+// Time reads n as unix seconds, zero stays the zero time
+func (n Int) Time() time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(n), 0)
+}
+
+// Side of an order
+type Side string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+// OrderType of a trade request
+type OrderType string
+
+const (
+	Limit  OrderType = "limit"
+	Market OrderType = "market"
+)
+
+// Info of the account returned by getInfo
+type Info struct {
+	ServerTime         Int               `json:"server_time"`
+	Balance            map[string]Number `json:"balance"`
+	BalanceHold        map[string]Number `json:"balance_hold"`
+	Address            map[string]string `json:"address"`
+	UserID             string            `json:"user_id"`
+	Name               string            `json:"name"`
+	Email              string            `json:"email"`
+	VerificationStatus string            `json:"verification_status"`
+}
+
+// TransHistory groups deposits and withdrawals by currency
+type TransHistory struct {
+	Withdraw map[string][]Transaction `json:"withdraw"`
+	Deposit  map[string][]Transaction `json:"deposit"`
+}
+
+// Transaction is a deposit or a withdrawal
+type Transaction struct {
+	Status      string `json:"status"`
+	Type        string `json:"type"`
+	Amount      Number `json:"amount"`
+	Fee         Number `json:"fee"`
+	SubmitTime  Int    `json:"submit_time"`
+	SuccessTime Int    `json:"success_time"`
+	WithdrawID  string `json:"withdraw_id"`
+	DepositID   string `json:"deposit_id"`
+	Tx          string `json:"tx"`
+}
+
+// TradeRequest places an order. Amount of a buy is in the quote currency
+// (the idr parameter of btc_idr), of a sell in the base coin. Type is
+// limit when empty.
+type TradeRequest struct {
+	Pair          string
+	Side          Side
+	Type          OrderType
+	Price         float64
+	Amount        float64
+	ClientOrderID string
+}
+
+func (o TradeRequest) values() (url.Values, error) {
+	base, quote, ok := strings.Cut(o.Pair, "_")
+	if !ok {
+		return nil, errors.New("indodax: pair must be base_quote, e.g. btc_idr")
+	}
+	if o.Side != Buy && o.Side != Sell {
+		return nil, errors.New("indodax: side must be buy or sell")
+	}
+	if o.Amount <= 0 {
+		return nil, errors.New("indodax: amount must be positive")
+	}
+	if o.Type != Market && o.Price <= 0 {
+		return nil, errors.New("indodax: price must be positive for a limit order")
+	}
+
+	data := url.Values{
+		"pair": {o.Pair},
+		"type": {string(o.Side)},
+	}
+	if o.Type != "" {
+		data.Set("order_type", string(o.Type))
+	}
+	if o.Price > 0 {
+		data.Set("price", formatFloat(o.Price))
+	}
+	if o.Side == Buy {
+		data.Set(quote, formatFloat(o.Amount))
+	} else {
+		data.Set(base, formatFloat(o.Amount))
+	}
+	if o.ClientOrderID != "" {
+		data.Set("client_order_id", o.ClientOrderID)
+	}
+	return data, nil
+}
+
+// TradeResult of a placed order. Amounts are keyed by the field name the
+// API uses, e.g. receive_btc, spend_rp or remain_rp.
+type TradeResult struct {
+	OrderID       Int               `json:"order_id"`
+	ClientOrderID string            `json:"client_order_id"`
+	Fee           Number            `json:"fee"`
+	Amounts       map[string]Number `json:"-"`
+}
+
+func (r *TradeResult) UnmarshalJSON(b []byte) error {
+	type plain TradeResult
+	if err := json.Unmarshal(b, (*plain)(r)); err != nil {
+		return err
+	}
+	r.Amounts = amounts(b, "order_id", "client_order_id", "fee")
+	return nil
+}
+
+func (r TradeResult) MarshalJSON() ([]byte, error) {
+	type plain TradeResult
+	return withAmounts(plain(r), r.Amounts)
+}
+
+// TradeHistoryQuery filters tradeHistory, only Pair is required
+type TradeHistoryQuery struct {
+	Pair   string
+	Count  int
+	FromID int64
+	EndID  int64
+	// Order is asc or desc
+	Order string
+	Since time.Time
+	End   time.Time
+}
+
+func (q TradeHistoryQuery) values() url.Values {
+	data := url.Values{"pair": {q.Pair}}
+	if q.Count > 0 {
+		data.Set("count", strconv.Itoa(q.Count))
+	}
+	if q.FromID > 0 {
+		data.Set("from_id", strconv.FormatInt(q.FromID, 10))
+	}
+	if q.EndID > 0 {
+		data.Set("end_id", strconv.FormatInt(q.EndID, 10))
+	}
+	if q.Order != "" {
+		data.Set("order", q.Order)
+	}
+	if !q.Since.IsZero() {
+		data.Set("since", strconv.FormatInt(q.Since.Unix(), 10))
+	}
+	if !q.End.IsZero() {
+		data.Set("end", strconv.FormatInt(q.End.Unix(), 10))
+	}
+	return data
+}
+
+// Trade is a fill of an order. The filled amount is keyed by coin in
+// Amounts, e.g. btc.
+type Trade struct {
+	TradeID       Int               `json:"trade_id"`
+	OrderID       Int               `json:"order_id"`
+	ClientOrderID string            `json:"client_order_id"`
+	Type          Side              `json:"type"`
+	Price         Number            `json:"price"`
+	Fee           Number            `json:"fee"`
+	TradeTime     Int               `json:"trade_time"`
+	Amounts       map[string]Number `json:"-"`
+}
+
+func (t *Trade) UnmarshalJSON(b []byte) error {
+	type plain Trade
+	if err := json.Unmarshal(b, (*plain)(t)); err != nil {
+		return err
+	}
+	t.Amounts = amounts(b, "trade_id", "order_id", "client_order_id", "type", "price", "fee", "trade_time")
+	return nil
+}
+
+func (t Trade) MarshalJSON() ([]byte, error) {
+	type plain Trade
+	return withAmounts(plain(t), t.Amounts)
+}
+
+// Order as returned by openOrders, orderHistory and getOrder. The ordered
+// and remaining amounts are keyed by field name in Amounts, e.g.
+// order_idr, order_btc or remain_btc.
+type Order struct {
+	OrderID       Int               `json:"order_id"`
+	ClientOrderID string            `json:"client_order_id"`
+	Type          Side              `json:"type"`
+	OrderType     OrderType         `json:"order_type"`
+	Price         Number            `json:"price"`
+	Status        string            `json:"status"`
+	SubmitTime    Int               `json:"submit_time"`
+	FinishTime    Int               `json:"finish_time"`
+	Amounts       map[string]Number `json:"-"`
+}
+
+func (o *Order) UnmarshalJSON(b []byte) error {
+	type plain Order
+	if err := json.Unmarshal(b, (*plain)(o)); err != nil {
+		return err
+	}
+	o.Amounts = amounts(b, "order_id", "client_order_id", "type", "order_type", "price", "status", "submit_time", "finish_time")
+	return nil
+}
+
+func (o Order) MarshalJSON() ([]byte, error) {
+	type plain Order
+	return withAmounts(plain(o), o.Amounts)
+}
+
+// CancelResult of cancelOrder with the balances after the cancel
+type CancelResult struct {
+	OrderID       Int               `json:"order_id"`
+	ClientOrderID string            `json:"client_order_id"`
+	Type          Side              `json:"type"`
+	Pair          string            `json:"pair"`
+	Balance       map[string]Number `json:"balance"`
+}
+
+// WithdrawRequest sends Amount of Currency to Address
+type WithdrawRequest struct {
+	Currency string
+	Address  string
+	Amount   float64
+	// Memo is the tag or memo some networks need
+	Memo string
+	// RequestID is confirmed by the account callback URL
+	RequestID string
+	Network   string
+}
+
+func (w WithdrawRequest) values() (url.Values, error) {
+	if w.Currency == "" || w.Address == "" {
+		return nil, errors.New("indodax: currency and address are required")
+	}
+	if w.Amount <= 0 {
+		return nil, errors.New("indodax: amount must be positive")
+	}
+	if w.RequestID == "" {
+		return nil, errors.New("indodax: request id is required")
+	}
+
+	data := url.Values{
+		"currency":         {w.Currency},
+		"withdraw_address": {w.Address},
+		"withdraw_amount":  {formatFloat(w.Amount)},
+		"request_id":       {w.RequestID},
+	}
+	if w.Memo != "" {
+		data.Set("withdraw_memo", w.Memo)
+	}
+	if w.Network != "" {
+		data.Set("network", w.Network)
+	}
+	return data, nil
+}
+
+// WithdrawResult of withdrawCoin
+type WithdrawResult struct {
+	Status         string `json:"status"`
+	Currency       string `json:"withdraw_currency"`
+	Address        string `json:"withdraw_address"`
+	Amount         Number `json:"withdraw_amount"`
+	Fee            Number `json:"fee"`
+	AmountAfterFee Number `json:"amount_after_fee"`
+	SubmitTime     Int    `json:"submit_time"`
+	WithdrawID     string `json:"withdraw_id"`
+	TxID           string `json:"txid"`
+}
+
+// amounts collects the numeric fields of b other than known, the API
+// names them after the coin of the pair
+func amounts(b []byte, known ...string) map[string]Number {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil
+	}
+	for _, k := range known {
+		delete(raw, k)
+	}
+	out := map[string]Number{}
+	for k, v := range raw {
+		var n Number
+		if err := n.UnmarshalJSON(v); err == nil {
+			out[k] = n
+		}
+	}
+	return out
+}
+
+// withAmounts encodes v with the amounts as extra fields
+func withAmounts(v interface{}, amounts map[string]Number) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(amounts) == 0 {
+		return b, err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	for k, v := range amounts {
+		raw[k] = float64(v)
+	}
+	return json.Marshal(raw)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,479 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Fatiri/areuy/exchange/indodax"
+)
+
+// Config of a Manager
+type Config struct {
+	// Prefix of the generated client order IDs
+	Prefix string
+	// OnChange is called after every saved state change, from is the
+	// state the order left. It must not call back into the Manager.
+	OnChange func(o Order, from State)
+	// Now is time.Now when nil
+	Now func() time.Time
+}
+
+// DefaultConfig prefixes client order IDs with areuy
+func DefaultConfig() Config {
+	return Config{Prefix: "areuy"}
+}
+
+// Manager places orders and follows them until they reach a final state.
+// It is safe for concurrent use, state changes are serialised.
+type Manager struct {
+	// mu guards seq, inflight and every read-modify-write of the store
+	mu    sync.Mutex
+	api   indodax.API
+	store Store
+	cfg   Config
+	seq   int64
+	// inflight are the IDs of orders Submit is sending, Reconcile leaves
+	// them alone
+	inflight map[string]bool
+}
+
+// NewManager tracks the orders of api in store
+func NewManager(api indodax.API, store Store, cfg Config) *Manager {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Manager{api: api, store: store, cfg: cfg, inflight: map[string]bool{}}
+}
+
+// Submit saves the order as new before sending it, so an order the
+// process crashed on is found again by Reconcile. An order the exchange
+// refused is saved as rejected and returned with the error. Other errors
+// leave it new, Reconcile finds out whether it reached the exchange.
+func (m *Manager) Submit(ctx context.Context, req indodax.TradeRequest) (*Order, error) {
+	m.mu.Lock()
+	m.seq++
+	now := m.cfg.Now()
+	o := &Order{
+		ID:        fmt.Sprintf("%s-%d-%d", m.cfg.Prefix, now.UnixNano(), m.seq),
+		Pair:      req.Pair,
+		Side:      string(req.Side),
+		Type:      string(req.Type),
+		Price:     req.Price,
+		Amount:    req.Amount,
+		Remaining: req.Amount,
+		State:     New,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if o.Type == "" {
+		o.Type = string(indodax.Limit)
+	}
+	err := m.store.Save(ctx, o)
+	if err == nil {
+		m.inflight[o.ID] = true
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	req.ClientOrderID = o.ID
+	res, err := m.api.Trade(req)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.inflight, o.ID)
+	// a push feed or Reconcile may have moved the order while it was in
+	// flight, the result is applied on top of the stored state
+	o, lerr := m.store.Load(ctx, o.ID)
+	if lerr != nil {
+		return nil, lerr
+	}
+	if err != nil {
+		if !uncertain(err) && o.State == New {
+			o.Error = err.Error()
+			if serr := m.change(ctx, o, Rejected); serr != nil {
+				return o, serr
+			}
+		}
+		return o, err
+	}
+
+	// the Reconcile of another process sharing the store may have marked
+	// it lost while it was in flight, the exchange has it
+	if lost(o) {
+		if err := m.revive(ctx, o); err != nil {
+			return o, err
+		}
+	}
+	if o.ExchangeID == 0 {
+		o.ExchangeID = int64(res.OrderID)
+	}
+	remain, hasRemain := prefixed(res.Amounts, "remain_")
+	received, _ := prefixed(res.Amounts, "receive_")
+	next := New
+	switch {
+	case hasRemain && remain <= 0:
+		next, remain = Filled, 0
+	case hasRemain && received > 0:
+		next = PartiallyFilled
+	}
+	if progress[next] <= progress[o.State] {
+		// nothing newer than what is stored
+		return o, m.save(ctx, o)
+	}
+	o.Remaining = remain
+	return o, m.change(ctx, o, next)
+}
+
+// progress orders the states a trade result can report
+var progress = map[State]int{New: 1, PartiallyFilled: 2, Filled: 3, Cancelled: 3, Rejected: 3}
+
+// Cancel cancels an open order on the exchange
+func (m *Manager) Cancel(ctx context.Context, id string) (*Order, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o, err := m.store.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if o.State.Final() {
+		return o, fmt.Errorf("%w: %s is %s", ErrInvalidTransition, o.ID, o.State)
+	}
+	if o.ExchangeID == 0 {
+		return o, fmt.Errorf("order: %s was not acknowledged by the exchange, reconcile first", o.ID)
+	}
+	if _, err := m.api.CancelOrder(o.Pair, o.ExchangeID, indodax.Side(o.Side)); err != nil {
+		return o, err
+	}
+	return o, m.change(ctx, o, Cancelled)
+}
+
+// Observe applies a status of an exchange order to the tracked order with
+// the same client order ID or exchange ID. It is called by Sync and
+// Reconcile and can be fed by a push subscription. Unknown orders are
+// ignored and reported with false.
+func (m *Manager) Observe(ctx context.Context, pair string, x indodax.Order) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	active, err := m.store.Active(ctx)
+	if err != nil {
+		return false, err
+	}
+	for i := range active {
+		o := &active[i]
+		if o.Pair == pair && (x.ClientOrderID != "" && x.ClientOrderID == o.ID || o.ExchangeID != 0 && o.ExchangeID == int64(x.OrderID)) {
+			return true, m.apply(ctx, o, x)
+		}
+	}
+	return false, nil
+}
+
+// Sync polls the status of every active order
+func (m *Manager) Sync(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	active, err := m.store.Active(ctx)
+	if err != nil {
+		return err
+	}
+	var first error
+	failed := 0
+	for i := range active {
+		o := &active[i]
+		if o.ExchangeID == 0 {
+			continue
+		}
+		x, err := m.api.GetOrder(o.Pair, o.ExchangeID)
+		if err == nil {
+			err = m.apply(ctx, o, *x)
+		}
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("order: %d of %d orders failed to sync: %w", failed, len(active), first)
+	}
+	return nil
+}
+
+// Poll calls Sync every interval until ctx is done. Sync errors are passed
+// to onError, nil ignores them.
+func (m *Manager) Poll(ctx context.Context, every time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := m.Sync(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Report of a Reconcile run, by order ID
+type Report struct {
+	// Updated orders changed state
+	Updated []string
+	// Found orders were sent before a crash and found on the exchange
+	Found []string
+	// Lost orders were sent before a crash and are not on the exchange,
+	// they are marked rejected and back to new if the exchange shows them
+	// later
+	Lost []string
+	// Adopted are open exchange orders that were not tracked
+	Adopted []string
+}
+
+// Reconcile brings the store in line with the exchange after a restart:
+// active orders are updated from the exchange, orders that were saved but
+// never acknowledged are looked up by client order ID, and open exchange
+// orders nobody tracked are adopted.
+func (m *Manager) Reconcile(ctx context.Context) (*Report, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	open, err := m.api.OpenOrders("")
+	if err != nil {
+		return nil, err
+	}
+	active, err := m.store.Active(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	known := map[int64]bool{}
+	histories := map[string][]indodax.Order{}
+	for i := range active {
+		o := &active[i]
+		if m.inflight[o.ID] {
+			// Submit applies the trade result
+			continue
+		}
+		from := o.State
+
+		x, ok := find(open[o.Pair], o)
+		if !ok && o.ExchangeID == 0 {
+			history, seen := histories[o.Pair]
+			if !seen {
+				if history, err = m.api.OrderHistory(o.Pair, 0); err != nil {
+					return report, err
+				}
+				histories[o.Pair] = history
+			}
+			x, ok = find(history, o)
+		}
+		if !ok && o.ExchangeID != 0 {
+			got, err := m.api.GetOrder(o.Pair, o.ExchangeID)
+			if err != nil {
+				return report, err
+			}
+			x, ok = *got, true
+		}
+
+		if !ok {
+			o.Error = errLost
+			if err := m.change(ctx, o, Rejected); err != nil {
+				return report, err
+			}
+			report.Lost = append(report.Lost, o.ID)
+			continue
+		}
+		if o.ExchangeID == 0 {
+			o.ExchangeID = int64(x.OrderID)
+			report.Found = append(report.Found, o.ID)
+		}
+		known[o.ExchangeID] = true
+		if err := m.apply(ctx, o, x); err != nil {
+			return report, err
+		}
+		if o.State != from {
+			report.Updated = append(report.Updated, o.ID)
+		}
+	}
+
+	pairs := make([]string, 0, len(open))
+	for pair := range open {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+	for _, pair := range pairs {
+		for _, x := range open[pair] {
+			if known[int64(x.OrderID)] || m.inflight[x.ClientOrderID] {
+				continue
+			}
+			o, err := m.adopt(ctx, pair, x)
+			if err != nil {
+				return report, err
+			}
+			report.Adopted = append(report.Adopted, o.ID)
+		}
+	}
+	return report, nil
+}
+
+// adopt starts tracking an exchange order placed outside the manager
+func (m *Manager) adopt(ctx context.Context, pair string, x indodax.Order) (*Order, error) {
+	id := x.ClientOrderID
+	if id == "" {
+		id = fmt.Sprintf("indodax-%d", x.OrderID)
+	}
+	if o, err := m.store.Load(ctx, id); err == nil {
+		if lost(o) {
+			if err := m.revive(ctx, o); err != nil {
+				return o, err
+			}
+		}
+		if o.ExchangeID == 0 {
+			o.ExchangeID = int64(x.OrderID)
+		}
+		return o, m.apply(ctx, o, x)
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	ordered, _ := prefixed(x.Amounts, "order_")
+	remain, _ := prefixed(x.Amounts, "remain_")
+	now := m.cfg.Now()
+	o := &Order{
+		ID:         id,
+		ExchangeID: int64(x.OrderID),
+		Pair:       pair,
+		Side:       string(x.Type),
+		Type:       string(x.OrderType),
+		Price:      float64(x.Price),
+		Amount:     ordered,
+		Remaining:  remain,
+		State:      New,
+		CreatedAt:  x.SubmitTime.Time(),
+		UpdatedAt:  now,
+	}
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = now
+	}
+	if err := m.store.Save(ctx, o); err != nil {
+		return nil, err
+	}
+	return o, m.apply(ctx, o, x)
+}
+
+// apply moves o to the state of the exchange order x
+func (m *Manager) apply(ctx context.Context, o *Order, x indodax.Order) error {
+	ordered, hasOrdered := prefixed(x.Amounts, "order_")
+	remain, hasRemain := prefixed(x.Amounts, "remain_")
+	if hasRemain {
+		o.Remaining = remain
+	}
+
+	next := o.State
+	switch strings.ToLower(x.Status) {
+	case "filled":
+		next, o.Remaining = Filled, 0
+	case "cancelled", "canceled":
+		next = Cancelled
+	case "open", "":
+		if hasOrdered && hasRemain && remain < ordered {
+			next = PartiallyFilled
+		}
+	default:
+		return fmt.Errorf("order: unknown exchange status %q of %s", x.Status, o.ID)
+	}
+	if next == o.State && next != PartiallyFilled {
+		return m.save(ctx, o)
+	}
+	return m.change(ctx, o, next)
+}
+
+// change moves o to next and saves it
+func (m *Manager) change(ctx context.Context, o *Order, next State) error {
+	from := o.State
+	if err := o.transition(next); err != nil {
+		return err
+	}
+	if err := m.save(ctx, o); err != nil {
+		return err
+	}
+	if m.cfg.OnChange != nil && (from != next || next == PartiallyFilled) {
+		m.cfg.OnChange(*o, from)
+	}
+	return nil
+}
+
+// errLost is the Error of the orders Reconcile did not find on the exchange
+const errLost = "order: not found on the exchange after restart"
+
+// lost reports whether Reconcile marked o rejected for not finding it
+func lost(o *Order) bool {
+	return o.State == Rejected && o.Error == errLost
+}
+
+// revive moves a lost order the exchange turned out to have back to new,
+// bypassing the transitions out of the final rejected state
+func (m *Manager) revive(ctx context.Context, o *Order) error {
+	o.State, o.Error = New, ""
+	if err := m.save(ctx, o); err != nil {
+		return err
+	}
+	if m.cfg.OnChange != nil {
+		m.cfg.OnChange(*o, Rejected)
+	}
+	return nil
+}
+
+func (m *Manager) save(ctx context.Context, o *Order) error {
+	o.UpdatedAt = m.cfg.Now()
+	return m.store.Save(ctx, o)
+}
+
+// find returns the exchange order of o by client order ID or exchange ID
+func find(orders []indodax.Order, o *Order) (indodax.Order, bool) {
+	for _, x := range orders {
+		if x.ClientOrderID == o.ID || o.ExchangeID != 0 && int64(x.OrderID) == o.ExchangeID {
+			return x, true
+		}
+	}
+	return indodax.Order{}, false
+}
+
+// prefixed returns the first amount named prefix+coin, e.g. remain_idr
+func prefixed(amounts map[string]indodax.Number, prefix string) (float64, bool) {
+	for k, v := range amounts {
+		if strings.HasPrefix(k, prefix) {
+			return float64(v), true
+		}
+	}
+	return 0, false
+}
+
+// uncertain reports whether the request may have reached the exchange
+// although it failed: timeouts, broken connections, gateway errors and
+// unreadable responses
+func uncertain(err error) bool {
+	var urlErr *url.Error
+	var netErr net.Error
+	var decodeErr *indodax.DecodeError
+	var apiErr *indodax.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == "" && apiErr.StatusCode >= 500
+	}
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.As(err, &decodeErr) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,299 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/exchange/indodax"
+	"github.com/Fatiri/areuy/exchange/order"
+	"github.com/Fatiri/areuy/exchange/paper"
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// flaky sends trades to the simulator but loses the response, or the
+// request itself when drop is set
+type flaky struct {
+	indodax.API
+	drop bool
+}
+
+func (f flaky) Trade(o indodax.TradeRequest) (*indodax.TradeResult, error) {
+	if !f.drop {
+		f.API.Trade(o)
+	}
+	return nil, &url.Error{Op: "Post", URL: indodax.DefaultBaseURL, Err: errors.New("connection reset by peer")}
+}
+
+// pushed reports the order as filled through Observe before the response
+// of Trade arrives, like a push feed racing the request
+type pushed struct {
+	indodax.API
+	ex *paper.Exchange
+	m  *order.Manager
+}
+
+func (p *pushed) Trade(o indodax.TradeRequest) (*indodax.TradeResult, error) {
+	res, err := p.API.Trade(o)
+	if err != nil {
+		return nil, err
+	}
+	p.ex.OnTrade(o.Pair, 800, 0, start.Add(time.Minute))
+	x, err := p.API.GetOrder(o.Pair, int64(res.OrderID))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.m.Observe(context.Background(), o.Pair, *x); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// reconciled runs Reconcile of m before the request reaches the
+// exchange, like a restart routine racing a Submit
+type reconciled struct {
+	indodax.API
+	m *order.Manager
+}
+
+func (r *reconciled) Trade(o indodax.TradeRequest) (*indodax.TradeResult, error) {
+	if _, err := r.m.Reconcile(context.Background()); err != nil {
+		return nil, err
+	}
+	return r.API.Trade(o)
+}
+
+func newExchange() *paper.Exchange {
+	cfg := paper.DefaultConfig()
+	cfg.Balances = map[string]float64{"idr": 1000000, "btc": 1}
+	cfg.Now = func() time.Time { return start }
+	ex := paper.New(cfg)
+	ex.OnTrade("btc_idr", 1000, 0, start)
+	return ex
+}
+
+func TestStateTransition(t *testing.T) {
+	assert.True(t, order.New.CanTransition(order.PartiallyFilled), "it should be true")
+	assert.True(t, order.PartiallyFilled.CanTransition(order.PartiallyFilled), "it should be true")
+	assert.True(t, order.PartiallyFilled.CanTransition(order.Cancelled), "it should be true")
+	assert.False(t, order.PartiallyFilled.CanTransition(order.Rejected), "it should be false")
+	assert.False(t, order.Filled.CanTransition(order.Cancelled), "it should be false")
+	assert.True(t, order.Rejected.Final(), "it should be true")
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	buy := indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Buy, Price: 900, Amount: 90000}
+
+	tests := []struct {
+		name                string
+		funcUseCaseShouldBe func(t *testing.T, ex *paper.Exchange, m *order.Manager, store order.Store, changes *[]string)
+	}{
+		{
+			name: "Resting order goes through partial fills to filled",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange, m *order.Manager, store order.Store, changes *[]string) {
+				o, err := m.Submit(ctx, buy)
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, order.New, o.State, "they should be equal")
+				assert.NotZero(t, o.ExchangeID, "it should be acknowledged")
+
+				ex.OnTrade("btc_idr", 890, 40, start.Add(time.Minute))
+				assert.NoError(t, m.Sync(ctx), "they should be no error")
+				ex.OnTrade("btc_idr", 880, 0, start.Add(2*time.Minute))
+				assert.NoError(t, m.Sync(ctx), "they should be no error")
+
+				assert.Equal(t, []string{"new->partially_filled", "partially_filled->filled"}, *changes, "they should be equal")
+				assert.Empty(t, activeIDs(t, store), "they should be no active order")
+			},
+		},
+		{
+			name: "Marketable order is filled on submit",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange, m *order.Manager, store order.Store, changes *[]string) {
+				o, err := m.Submit(ctx, indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Sell, Type: indodax.Market, Amount: 0.5})
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, order.Filled, o.State, "they should be equal")
+				assert.Equal(t, 0.0, o.Remaining, "they should be equal")
+			},
+		},
+		{
+			name: "Refused order is rejected",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange, m *order.Manager, store order.Store, changes *[]string) {
+				o, err := m.Submit(ctx, indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Sell, Price: 2000, Amount: 5})
+				assert.True(t, errors.Is(err, paper.ErrInsufficientBalance), "it should be insufficient balance")
+				assert.Equal(t, order.Rejected, o.State, "they should be equal")
+				assert.Contains(t, o.Error, "insufficient balance", "they should contain")
+			},
+		},
+		{
+			name: "Cancel",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange, m *order.Manager, store order.Store, changes *[]string) {
+				o, _ := m.Submit(ctx, buy)
+				o, err := m.Cancel(ctx, o.ID)
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, order.Cancelled, o.State, "they should be equal")
+				assert.Empty(t, ex.Orders("btc_idr", true), "it should be cancelled on the exchange")
+
+				_, err = m.Cancel(ctx, o.ID)
+				assert.True(t, errors.Is(err, order.ErrInvalidTransition), "it should be an invalid transition")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ex := newExchange()
+			var changes []string
+			cfg := order.DefaultConfig()
+			cfg.OnChange = func(o order.Order, from order.State) {
+				changes = append(changes, string(from)+"->"+string(o.State))
+			}
+			store := order.NewMemoryStore()
+			m := order.NewManager(ex.Indodax(), store, cfg)
+			test.funcUseCaseShouldBe(t, ex, m, store, &changes)
+		})
+	}
+}
+
+func TestSubmitRacingObserve(t *testing.T) {
+	ctx := context.Background()
+	ex := newExchange()
+	var changes []string
+	cfg := order.DefaultConfig()
+	cfg.OnChange = func(o order.Order, from order.State) {
+		changes = append(changes, string(from)+"->"+string(o.State))
+	}
+	store := order.NewMemoryStore()
+	api := &pushed{API: ex.Indodax(), ex: ex}
+	api.m = order.NewManager(api, store, cfg)
+
+	o, err := api.m.Submit(ctx, indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Buy, Price: 900, Amount: 90000})
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, order.Filled, o.State, "they should keep the pushed state")
+	assert.Equal(t, []string{"new->filled"}, changes, "they should be equal")
+
+	got, _ := store.Load(ctx, o.ID)
+	assert.Equal(t, order.Filled, got.State, "they should be equal")
+	assert.Equal(t, 0.0, got.Remaining, "they should be equal")
+	assert.NotZero(t, got.ExchangeID, "it should be acknowledged")
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	ex := newExchange()
+	store := order.NewMemoryStore()
+	buy := indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Buy, Price: 900, Amount: 9000}
+
+	// the process dies while these are in flight
+	sent, err := order.NewManager(flaky{API: ex.Indodax()}, store, order.DefaultConfig()).Submit(ctx, buy)
+	assert.Error(t, err, "they should be error")
+	assert.Equal(t, order.New, sent.State, "they should be left new")
+	lost, _ := order.NewManager(flaky{API: ex.Indodax(), drop: true}, store, order.DefaultConfig()).Submit(ctx, buy)
+
+	// tracked, then filled while the process was down
+	filled, _ := order.NewManager(ex.Indodax(), store, order.DefaultConfig()).Submit(ctx, indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Sell, Price: 1100, Amount: 0.1})
+	ex.OnTrade("btc_idr", 1200, 0, start.Add(time.Minute))
+
+	// placed by hand on the exchange
+	manual, _ := ex.Place(paper.Order{Pair: "btc_idr", Side: indodax.Buy, Price: 800, Amount: 1})
+
+	report, err := order.NewManager(ex.Indodax(), store, order.DefaultConfig()).Reconcile(ctx)
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, []string{sent.ID}, report.Found, "they should be equal")
+	assert.Equal(t, []string{lost.ID}, report.Lost, "they should be equal")
+	assert.Equal(t, []string{filled.ID}, report.Updated, "they should be equal")
+	assert.Equal(t, []string{"indodax-3"}, report.Adopted, "they should be equal")
+	assert.Equal(t, int64(3), manual.ID, "they should be equal")
+
+	got, _ := store.Load(ctx, filled.ID)
+	assert.Equal(t, order.Filled, got.State, "they should be equal")
+	got, _ = store.Load(ctx, sent.ID)
+	assert.NotZero(t, got.ExchangeID, "it should be acknowledged")
+	assert.ElementsMatch(t, []string{sent.ID, "indodax-3"}, activeIDs(t, store), "they should be equal")
+}
+
+func TestSubmitRacingReconcile(t *testing.T) {
+	ctx := context.Background()
+	buy := indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Buy, Price: 900, Amount: 90000}
+
+	tests := []struct {
+		name                string
+		funcUseCaseShouldBe func(t *testing.T, ex *paper.Exchange, store order.Store, changes *[]string, cfg order.Config)
+	}{
+		{
+			name: "Reconcile of the same manager skips the order in flight",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange, store order.Store, changes *[]string, cfg order.Config) {
+				api := &reconciled{API: ex.Indodax()}
+				api.m = order.NewManager(api, store, cfg)
+
+				o, err := api.m.Submit(ctx, buy)
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, order.New, o.State, "they should be equal")
+				assert.Empty(t, *changes, "they should be no change")
+				assert.Equal(t, []string{o.ID}, activeIDs(t, store), "they should be equal")
+			},
+		},
+		{
+			name: "Order another manager marked lost is revived",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange, store order.Store, changes *[]string, cfg order.Config) {
+				api := &reconciled{API: ex.Indodax(), m: order.NewManager(ex.Indodax(), store, cfg)}
+
+				o, err := order.NewManager(api, store, cfg).Submit(ctx, buy)
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, order.New, o.State, "they should be equal")
+				assert.Empty(t, o.Error, "it should be empty")
+				assert.Equal(t, []string{"new->rejected", "rejected->new"}, *changes, "they should be equal")
+				assert.Equal(t, []string{o.ID}, activeIDs(t, store), "they should be equal")
+
+				ex.OnTrade("btc_idr", 880, 0, start.Add(time.Minute))
+				report, err := order.NewManager(ex.Indodax(), store, cfg).Reconcile(ctx)
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, []string{o.ID}, report.Updated, "they should be equal")
+				assert.Empty(t, report.Lost, "they should be empty")
+			},
+		},
+		{
+			name: "Lost order still open on the exchange is revived by Reconcile",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange, store order.Store, changes *[]string, cfg order.Config) {
+				// a process died sending it, another marked it lost while
+				// the request was on its way
+				dead := flaky{API: &reconciled{API: ex.Indodax(), m: order.NewManager(ex.Indodax(), store, cfg)}}
+				o, err := order.NewManager(dead, store, cfg).Submit(ctx, buy)
+				assert.Error(t, err, "they should be error")
+				assert.Equal(t, order.Rejected, o.State, "it should be marked lost")
+
+				report, err := order.NewManager(ex.Indodax(), store, cfg).Reconcile(ctx)
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, []string{o.ID}, report.Adopted, "they should be equal")
+				got, _ := store.Load(ctx, o.ID)
+				assert.Equal(t, order.New, got.State, "they should be equal")
+				assert.NotZero(t, got.ExchangeID, "it should be acknowledged")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var changes []string
+			cfg := order.DefaultConfig()
+			cfg.OnChange = func(o order.Order, from order.State) {
+				changes = append(changes, string(from)+"->"+string(o.State))
+			}
+			test.funcUseCaseShouldBe(t, newExchange(), order.NewMemoryStore(), &changes, cfg)
+		})
+	}
+}
+
+func activeIDs(t *testing.T, store order.Store) []string {
+	t.Helper()
+	active, err := store.Active(context.Background())
+	assert.NoError(t, err, "they should be no error")
+	var ids []string
+	for _, o := range active {
+		ids = append(ids, o.ID)
+	}
+	return ids
+}
@@ -0,0 +1,93 @@
+// Package order tracks orders placed through an indodax.API from
+// submission to a final state and keeps them in a Store, so a restarted
+// bot picks its in-flight orders up again with Manager.Reconcile.
+//
+//	new ──> partially_filled ──> filled
+//	 │             │
+//	 │             └──> cancelled
+//	 ├──> filled / cancelled
+//	 └──> rejected
+package order
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// State of an order
+type State string
+
+const (
+	// New is persisted before the order is sent and kept until it fills
+	New             State = "new"
+	PartiallyFilled State = "partially_filled"
+	Filled          State = "filled"
+	Cancelled       State = "cancelled"
+	// Rejected orders were refused by the exchange or never reached it
+	Rejected State = "rejected"
+)
+
+// ErrInvalidTransition is returned when a state change is not allowed,
+// e.g. out of a final state
+var ErrInvalidTransition = errors.New("order: invalid state transition")
+
+var transitions = map[State][]State{
+	New:             {PartiallyFilled, Filled, Cancelled, Rejected},
+	PartiallyFilled: {PartiallyFilled, Filled, Cancelled},
+}
+
+// Final reports whether no more changes can happen to an order in s
+func (s State) Final() bool {
+	return s == Filled || s == Cancelled || s == Rejected
+}
+
+// CanTransition reports whether an order can go from s to next. Staying
+// partially filled is a valid transition, it records more fills.
+func (s State) CanTransition(next State) bool {
+	for _, t := range transitions[s] {
+		if t == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Order as tracked by the Manager. Amount and Remaining are in the
+// currency of indodax.TradeRequest.Amount, the quote currency for buys
+// and the base coin for sells.
+type Order struct {
+	// ID is the client order ID sent to the exchange
+	ID         string  `json:"id" gorm:"primaryKey;size:64"`
+	ExchangeID int64   `json:"exchange_id" gorm:"index"`
+	Pair       string  `json:"pair" gorm:"size:32;index"`
+	Side       string  `json:"side" gorm:"size:8"`
+	Type       string  `json:"type" gorm:"size:16"`
+	Price      float64 `json:"price"`
+	Amount     float64 `json:"amount"`
+	Remaining  float64 `json:"remaining"`
+	State      State   `json:"state" gorm:"size:16;index"`
+	// Error of a rejected order
+	Error string `json:"error"`
+	// set by the Manager clock, not by GORM
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime:false"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime:false"`
+}
+
+// TableName keeps the GORM table away from the reserved word order
+func (Order) TableName() string {
+	return "exchange_order"
+}
+
+// transition moves o to next, moving to the state it is in is a no-op
+// unless it records more fills
+func (o *Order) transition(next State) error {
+	if o.State == next && next != PartiallyFilled {
+		return nil
+	}
+	if !o.State.CanTransition(next) {
+		return fmt.Errorf("%w: %s %s -> %s", ErrInvalidTransition, o.ID, o.State, next)
+	}
+	o.State = next
+	return nil
+}
@@ -0,0 +1,65 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrNotFound is returned by Store.Load for unknown IDs
+var ErrNotFound = errors.New("order: not found")
+
+// Store persists orders, see MemoryStore and storage.GormOrderStore
+type Store interface {
+	// Save inserts or updates o
+	Save(ctx context.Context, o *Order) error
+	Load(ctx context.Context, id string) (*Order, error)
+	// Active returns the orders not in a final state, oldest first
+	Active(ctx context.Context) ([]Order, error)
+}
+
+// MemoryStore keeps orders in memory, for paper trading and tests
+type MemoryStore struct {
+	mu     sync.Mutex
+	orders map[string]Order
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{orders: map[string]Order{}}
+}
+
+func (s *MemoryStore) Save(_ context.Context, o *Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders[o.ID] = *o
+	return nil
+}
+
+func (s *MemoryStore) Load(_ context.Context, id string) (*Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &o, nil
+}
+
+func (s *MemoryStore) Active(_ context.Context) ([]Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Order
+	for _, o := range s.orders {
+		if !o.State.Final() {
+			out = append(out, o)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
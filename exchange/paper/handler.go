@@ -0,0 +1,237 @@
+package paper
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fatiri/areuy/crypto"
+	"github.com/Fatiri/areuy/exchange/indodax"
+)
+
+// IndodaxHandler serves api over the Indodax HTTP API: the private methods
+// as signed form POSTs on /tapi and the server time on /api/server_time.
+// The Sign header is checked when secret is not empty.
+//
+//	srv := httptest.NewServer(paper.IndodaxHandler(ex.Indodax(), "secret"))
+//	cfg.BaseURL, cfg.PublicURL = srv.URL+"/tapi", srv.URL+"/api"
+func IndodaxHandler(api indodax.API, secret string) http.Handler {
+	h := handler{api: api, secret: secret}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tapi", h.tapi)
+	mux.HandleFunc("/api/server_time", h.serverTime)
+	return mux
+}
+
+type handler struct {
+	api    indodax.API
+	secret string
+}
+
+func (h handler) serverTime(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	if clock, ok := h.api.(interface{ ServerTime() (time.Time, error) }); ok {
+		var err error
+		if now, err = clock.ServerTime(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	writeJSON(w, map[string]interface{}{"timezone": "UTC", "server_time": now.UnixMilli()})
+}
+
+func (h handler) tapi(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, "bad_request", err.Error())
+		return
+	}
+	if h.secret != "" {
+		sign, _ := crypto.NewHMACSHA512(h.secret).Sign(body)
+		if !hmac.Equal([]byte(sign), []byte(r.Header.Get("Sign"))) {
+			writeError(w, indodax.CodeInvalidCredentials, "Invalid credentials. Bad sign.")
+			return
+		}
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeError(w, "bad_request", err.Error())
+		return
+	}
+
+	out, err := h.call(form)
+	if err != nil {
+		var apiErr *indodax.APIError
+		switch {
+		case errors.As(err, &apiErr):
+			writeError(w, apiErr.Code, apiErr.Message)
+		case errors.Is(err, ErrInsufficientBalance):
+			writeError(w, "insufficient_balance", err.Error())
+		case errors.Is(err, ErrUnknownOrder):
+			writeError(w, "order_not_found", err.Error())
+		case errors.Is(err, ErrOrderClosed):
+			writeError(w, "order_not_open", err.Error())
+		default:
+			writeError(w, "bad_request", err.Error())
+		}
+		return
+	}
+	writeJSON(w, map[string]interface{}{"success": 1, "return": out})
+}
+
+// call runs the method of form and returns the value of "return"
+func (h handler) call(form url.Values) (interface{}, error) {
+	p := params(form)
+	pair := form.Get("pair")
+
+	switch method := form.Get("method"); method {
+	case "getInfo":
+		return h.api.GetInfo()
+	case "transHistory":
+		return h.api.TransHistory()
+	case "trade":
+		base, quote, err := splitPair(pair)
+		if err != nil {
+			return nil, err
+		}
+		side := indodax.Side(form.Get("type"))
+		coin := base
+		if side == indodax.Buy {
+			coin = quote
+		}
+		req := indodax.TradeRequest{
+			Pair:          pair,
+			Side:          side,
+			Type:          indodax.OrderType(form.Get("order_type")),
+			Price:         p.float("price"),
+			Amount:        p.float(coin),
+			ClientOrderID: form.Get("client_order_id"),
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+		return h.api.Trade(req)
+	case "tradeHistory":
+		q := indodax.TradeHistoryQuery{
+			Pair:   pair,
+			Count:  int(p.int("count")),
+			FromID: p.int("from_id"),
+			EndID:  p.int("end_id"),
+			Order:  form.Get("order"),
+		}
+		if since := p.int("since"); since > 0 {
+			q.Since = time.Unix(since, 0)
+		}
+		if end := p.int("end"); end > 0 {
+			q.End = time.Unix(end, 0)
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+		trades, err := h.api.TradeHistory(q)
+		return map[string]interface{}{"trades": nonNil(trades)}, err
+	case "openOrders":
+		orders, err := h.api.OpenOrders(pair)
+		if err != nil || pair == "" {
+			return map[string]interface{}{"orders": orders}, err
+		}
+		return map[string]interface{}{"orders": nonNil(orders[pair])}, nil
+	case "orderHistory":
+		count := p.int("count")
+		if p.err != nil {
+			return nil, p.err
+		}
+		orders, err := h.api.OrderHistory(pair, int(count))
+		return map[string]interface{}{"orders": nonNil(orders)}, err
+	case "getOrder":
+		id := p.int("order_id")
+		if p.err != nil {
+			return nil, p.err
+		}
+		o, err := h.api.GetOrder(pair, id)
+		return map[string]interface{}{"order": o}, err
+	case "cancelOrder":
+		id := p.int("order_id")
+		if p.err != nil {
+			return nil, p.err
+		}
+		return h.api.CancelOrder(pair, id, indodax.Side(form.Get("type")))
+	case "withdrawCoin":
+		req := indodax.WithdrawRequest{
+			Currency:  form.Get("currency"),
+			Address:   form.Get("withdraw_address"),
+			Amount:    p.float("withdraw_amount"),
+			Memo:      form.Get("withdraw_memo"),
+			RequestID: form.Get("request_id"),
+			Network:   form.Get("network"),
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+		return h.api.Withdraw(req)
+	default:
+		return nil, &indodax.APIError{Code: "invalid_method", Message: fmt.Sprintf("Method %q not found", method)}
+	}
+}
+
+// formParams reads numbers of a form and keeps the first error
+type formParams struct {
+	form url.Values
+	err  error
+}
+
+func params(form url.Values) *formParams {
+	return &formParams{form: form}
+}
+
+func (p *formParams) float(key string) float64 {
+	s := strings.TrimSpace(p.form.Get(key))
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("paper: invalid %s %q", key, s)
+	}
+	return v
+}
+
+func (p *formParams) int(key string) int64 {
+	s := strings.TrimSpace(p.form.Get(key))
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("paper: invalid %s %q", key, s)
+	}
+	return v
+}
+
+// nonNil keeps empty lists as [] rather than null
+func nonNil[T any](v []T) []T {
+	if v == nil {
+		return []T{}
+	}
+	return v
+}
+
+func writeError(w http.ResponseWriter, code, message string) {
+	writeJSON(w, map[string]interface{}{"success": 0, "error": message, "error_code": code})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,243 @@
+package paper
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Fatiri/areuy/exchange/indodax"
+)
+
+// Indodax returns the exchange as an indodax.API, so code written against
+// indodax.Client runs on the simulator unchanged
+func (e *Exchange) Indodax() indodax.API {
+	return indodaxAPI{e: e}
+}
+
+type indodaxAPI struct {
+	e *Exchange
+}
+
+// ServerTime is the simulator clock, it is served on /api/server_time
+func (a indodaxAPI) ServerTime() (time.Time, error) {
+	return a.e.Now(), nil
+}
+
+func (a indodaxAPI) GetInfo() (*indodax.Info, error) {
+	info := &indodax.Info{
+		ServerTime:  indodax.Int(a.e.Now().Unix()),
+		Balance:     map[string]indodax.Number{},
+		BalanceHold: map[string]indodax.Number{},
+		UserID:      "paper",
+		Name:        "paper",
+	}
+	for asset, b := range a.e.Balances() {
+		info.Balance[asset] = indodax.Number(b.Free)
+		info.BalanceHold[asset] = indodax.Number(b.Hold)
+	}
+	return info, nil
+}
+
+func (a indodaxAPI) TransHistory() (*indodax.TransHistory, error) {
+	return &indodax.TransHistory{
+		Withdraw: map[string][]indodax.Transaction{},
+		Deposit:  map[string][]indodax.Transaction{},
+	}, nil
+}
+
+func (a indodaxAPI) Trade(req indodax.TradeRequest) (*indodax.TradeResult, error) {
+	o := Order{Pair: req.Pair, Side: req.Side, ClientOrderID: req.ClientOrderID}
+	switch req.Type {
+	case indodax.Limit, "":
+		o.Type, o.Price, o.Amount = Limit, req.Price, req.Amount
+		// a buy gives the quote currency to spend
+		if req.Side == indodax.Buy && req.Price > 0 {
+			o.Amount = req.Amount / req.Price
+		}
+	case indodax.Market:
+		o.Type = Market
+		if req.Side == indodax.Buy {
+			o.Total = req.Amount
+		} else {
+			o.Amount = req.Amount
+		}
+	default:
+		return nil, fmt.Errorf("paper: unsupported order type %q", req.Type)
+	}
+
+	placed, err := a.e.Place(o)
+	if err != nil {
+		return nil, err
+	}
+
+	base, quote, _ := splitPair(placed.Pair)
+	res := &indodax.TradeResult{
+		OrderID:       indodax.Int(placed.ID),
+		ClientOrderID: placed.ClientOrderID,
+		Fee:           indodax.Number(placed.Fee),
+		Amounts:       map[string]indodax.Number{},
+	}
+	if placed.Side == indodax.Buy {
+		res.Amounts["receive_"+base] = indodax.Number(placed.Filled - placed.Fee)
+		res.Amounts["spend_"+tradeCoin(quote)] = indodax.Number(placed.Cost)
+		res.Amounts["remain_"+tradeCoin(quote)] = indodax.Number(quoteRemaining(placed))
+	} else {
+		res.Amounts["receive_"+tradeCoin(quote)] = indodax.Number(placed.Cost - placed.Fee)
+		res.Amounts["sold_"+base] = indodax.Number(placed.Filled)
+		res.Amounts["remain_"+base] = indodax.Number(placed.Amount - placed.Filled)
+	}
+	return res, nil
+}
+
+func (a indodaxAPI) TradeHistory(q indodax.TradeHistoryQuery) ([]indodax.Trade, error) {
+	if q.Pair == "" {
+		return nil, errors.New("paper: pair is required")
+	}
+	base, _, err := splitPair(q.Pair)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []indodax.Trade
+	for _, f := range a.e.Fills(q.Pair) {
+		if q.FromID > 0 && f.ID < q.FromID || q.EndID > 0 && f.ID > q.EndID {
+			continue
+		}
+		if !q.Since.IsZero() && f.Time.Before(q.Since) || !q.End.IsZero() && f.Time.After(q.End) {
+			continue
+		}
+		o, _ := a.e.Order(f.OrderID)
+		out = append(out, indodax.Trade{
+			TradeID:       indodax.Int(f.ID),
+			OrderID:       indodax.Int(f.OrderID),
+			ClientOrderID: o.ClientOrderID,
+			Type:          f.Side,
+			Price:         indodax.Number(f.Price),
+			Fee:           indodax.Number(f.Fee),
+			TradeTime:     indodax.Int(f.Time.Unix()),
+			Amounts:       map[string]indodax.Number{base: indodax.Number(f.Amount)},
+		})
+	}
+	if q.Order != "asc" {
+		sort.SliceStable(out, func(i, j int) bool { return out[i].TradeID > out[j].TradeID })
+	}
+	if q.Count > 0 && len(out) > q.Count {
+		out = out[:q.Count]
+	}
+	return out, nil
+}
+
+func (a indodaxAPI) OpenOrders(pair string) (map[string][]indodax.Order, error) {
+	out := map[string][]indodax.Order{}
+	for _, o := range a.e.Orders(pair, true) {
+		out[o.Pair] = append(out[o.Pair], toIndodax(o))
+	}
+	return out, nil
+}
+
+func (a indodaxAPI) OrderHistory(pair string, count int) ([]indodax.Order, error) {
+	if pair == "" {
+		return nil, errors.New("paper: pair is required")
+	}
+	if count <= 0 {
+		count = 100
+	}
+	orders := a.e.Orders(pair, false)
+	var out []indodax.Order
+	for i := len(orders) - 1; i >= 0 && len(out) < count; i-- {
+		out = append(out, toIndodax(orders[i]))
+	}
+	return out, nil
+}
+
+func (a indodaxAPI) GetOrder(pair string, orderID int64) (*indodax.Order, error) {
+	o, err := a.e.Order(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.Pair != pair {
+		return nil, ErrUnknownOrder
+	}
+	out := toIndodax(o)
+	return &out, nil
+}
+
+func (a indodaxAPI) CancelOrder(pair string, orderID int64, side indodax.Side) (*indodax.CancelResult, error) {
+	o, err := a.e.Order(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.Pair != pair || o.Side != side {
+		return nil, ErrUnknownOrder
+	}
+	if o, err = a.e.Cancel(orderID); err != nil {
+		return nil, err
+	}
+
+	res := &indodax.CancelResult{
+		OrderID:       indodax.Int(o.ID),
+		ClientOrderID: o.ClientOrderID,
+		Type:          o.Side,
+		Pair:          o.Pair,
+		Balance:       map[string]indodax.Number{},
+	}
+	for asset, b := range a.e.Balances() {
+		res.Balance[asset] = indodax.Number(b.Free)
+	}
+	return res, nil
+}
+
+func (a indodaxAPI) Withdraw(indodax.WithdrawRequest) (*indodax.WithdrawResult, error) {
+	return nil, errors.New("paper: withdrawals are not simulated")
+}
+
+// toIndodax converts an order, amounts are named like the API does:
+// order_idr and remain_idr for buys, order_btc and remain_btc for sells
+func toIndodax(o Order) indodax.Order {
+	base, quote, _ := splitPair(o.Pair)
+	out := indodax.Order{
+		OrderID:       indodax.Int(o.ID),
+		ClientOrderID: o.ClientOrderID,
+		Type:          o.Side,
+		OrderType:     indodax.OrderType(o.Type),
+		Price:         indodax.Number(o.Price),
+		Status:        string(o.Status),
+		SubmitTime:    indodax.Int(o.Created.Unix()),
+		Amounts:       map[string]indodax.Number{},
+	}
+	if o.Status != Open {
+		out.FinishTime = indodax.Int(o.Updated.Unix())
+	}
+	if o.Side == indodax.Buy {
+		ordered := o.Total
+		if ordered == 0 {
+			ordered = o.Amount * o.Price
+		}
+		out.Amounts["order_"+quote] = indodax.Number(ordered)
+		out.Amounts["remain_"+quote] = indodax.Number(quoteRemaining(o))
+	} else {
+		out.Amounts["order_"+base] = indodax.Number(o.Amount)
+		out.Amounts["remain_"+base] = indodax.Number(o.Amount - o.Filled)
+	}
+	return out
+}
+
+// quoteRemaining is what a buy has left to spend
+func quoteRemaining(o Order) float64 {
+	if o.Status != Open {
+		return 0
+	}
+	if o.Total > 0 {
+		return o.Total - o.Cost
+	}
+	return (o.Amount - o.Filled) * o.Price
+}
+
+// tradeCoin is the name trade results give a currency, rp for idr
+func tradeCoin(asset string) string {
+	if asset == "idr" {
+		return "rp"
+	}
+	return asset
+}
@@ -0,0 +1,193 @@
+package paper
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Fatiri/areuy/exchange/indodax"
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// OnTrade matches the open orders of pair against a market trade. amount
+// caps what the trade can fill in the base coin, zero or less fills any
+// amount.
+func (e *Exchange) OnTrade(pair string, price, amount float64, t time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if amount <= 0 {
+		amount = math.Inf(1)
+	}
+	e.tick(pair, price, amount, t)
+}
+
+// OnCandle matches the open orders of pair along the path of a candle:
+// open, low, high, close for an up candle and open, high, low, close for
+// a down one, with no cap on the amount filled
+func (e *Exchange) OnCandle(pair string, c indikators.Candle) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	path := []float64{c.Open, c.Low, c.High, c.Close}
+	if c.Close < c.Open {
+		path = []float64{c.Open, c.High, c.Low, c.Close}
+	}
+	for _, p := range path {
+		e.tick(pair, p, math.Inf(1), c.Time)
+	}
+}
+
+func (e *Exchange) tick(pair string, price, liquidity float64, t time.Time) {
+	e.last[pair] = price
+	if t.After(e.clock) {
+		e.clock = t
+	}
+	t = e.now()
+
+	var open []*Order
+	for _, o := range e.sorted(pair) {
+		if o.Status == Open {
+			e.trigger(o, price)
+			open = append(open, o)
+		}
+	}
+
+	// market orders take the trade first, in placement order
+	for _, o := range open {
+		if liquidity <= 0 {
+			return
+		}
+		if o.market() {
+			liquidity -= e.fill(o, price, liquidity, false, t)
+		}
+	}
+
+	// then the limit orders it traded through, best price first
+	var limits []*Order
+	for _, o := range open {
+		if o.Status == Open && o.limit() && marketable(o, price) {
+			limits = append(limits, o)
+		}
+	}
+	sort.SliceStable(limits, func(i, j int) bool {
+		a, b := limits[i], limits[j]
+		if a.Side != b.Side {
+			return a.Side == indodax.Buy
+		}
+		if a.Side == indodax.Buy {
+			return a.Price > b.Price
+		}
+		return a.Price < b.Price
+	})
+	for _, o := range limits {
+		if liquidity <= 0 {
+			return
+		}
+		liquidity -= e.fill(o, o.Price, liquidity, true, t)
+	}
+}
+
+// trigger turns a stop order into a market or limit one once the market
+// trades at its stop price
+func (e *Exchange) trigger(o *Order, price float64) {
+	if o.Triggered || o.Type != Stop && o.Type != StopLimit {
+		return
+	}
+	if o.Side == indodax.Buy && price >= o.StopPrice || o.Side == indodax.Sell && price <= o.StopPrice {
+		o.Triggered = true
+	}
+}
+
+func marketable(o *Order, price float64) bool {
+	if o.Side == indodax.Buy {
+		return o.Price >= price
+	}
+	return o.Price <= price
+}
+
+// fill executes o at price for at most liquidity of the base coin and
+// returns the amount filled
+func (e *Exchange) fill(o *Order, price, liquidity float64, maker bool, t time.Time) float64 {
+	base, quote, _ := splitPair(o.Pair)
+	rate := e.cfg.TakerFee
+	if maker {
+		rate = e.cfg.MakerFee
+	}
+
+	amount := o.Remaining()
+	if o.Total > 0 {
+		amount /= price
+	}
+	amount = math.Min(amount, liquidity)
+	if amount <= 0 {
+		return 0
+	}
+	cost := amount * price
+
+	var fee float64
+	var feeAsset string
+	if o.Side == indodax.Buy {
+		// a limit buy held its own price, the difference is refunded
+		release := cost
+		if o.limit() {
+			release = amount * o.Price
+		}
+		release = math.Min(release, o.held)
+		q := e.balance(quote)
+		q.Hold -= release
+		q.Free += release - cost
+		o.held -= release
+
+		fee, feeAsset = amount*rate, base
+		e.balance(base).Free += amount - fee
+	} else {
+		release := math.Min(amount, o.held)
+		b := e.balance(base)
+		b.Hold -= release
+		o.held -= release
+
+		fee, feeAsset = cost*rate, quote
+		e.balance(quote).Free += cost - fee
+	}
+
+	o.Filled += amount
+	o.Cost += cost
+	o.Fee += fee
+	o.Updated = t
+	e.fillID++
+	e.fills = append(e.fills, Fill{
+		ID:       e.fillID,
+		OrderID:  o.ID,
+		Pair:     o.Pair,
+		Side:     o.Side,
+		Price:    price,
+		Amount:   amount,
+		Fee:      fee,
+		FeeAsset: feeAsset,
+		Maker:    maker,
+		Time:     t,
+	})
+
+	size := o.Amount
+	if o.Total > 0 {
+		size = o.Total
+	}
+	if o.Remaining() <= epsilon(size) {
+		o.Status = Filled
+		e.release(o)
+	}
+	return amount
+}
+
+// release returns what o still holds to the free balance
+func (e *Exchange) release(o *Order) {
+	base, quote, _ := splitPair(o.Pair)
+	asset := base
+	if o.Side == indodax.Buy {
+		asset = quote
+	}
+	b := e.balance(asset)
+	b.Hold -= o.held
+	b.Free += o.held
+	o.held = 0
+}
@@ -0,0 +1,424 @@
+// Package paper is an in-process exchange for paper trading and tests.
+//
+// Orders rest in a local book per pair and are matched against replayed
+// market data, trades with OnTrade or candles with OnCandle. Market
+// orders and limit orders through the last price fill at once as taker,
+// resting limit orders fill at their own price as maker once the market
+// trades through them. Fees are taken from what the order receives.
+//
+// Exchange.Indodax exposes the simulator as an indodax.API and
+// IndodaxHandler serves it over the Indodax HTTP API.
+package paper
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Fatiri/areuy/exchange/indodax"
+)
+
+var (
+	ErrUnknownOrder        = errors.New("paper: unknown order")
+	ErrOrderClosed         = errors.New("paper: order is not open")
+	ErrInsufficientBalance = errors.New("paper: insufficient balance")
+)
+
+type OrderType string
+
+const (
+	Market OrderType = "market"
+	Limit  OrderType = "limit"
+	// Stop becomes a market order once the market trades at StopPrice
+	Stop OrderType = "stop"
+	// StopLimit becomes a limit order at Price once the market trades at
+	// StopPrice
+	StopLimit OrderType = "stop_limit"
+)
+
+type Status string
+
+const (
+	Open      Status = "open"
+	Filled    Status = "filled"
+	Cancelled Status = "cancelled"
+)
+
+// Order of the simulator. Amount is in the base coin, market and stop
+// buys give the quote currency to spend in Total instead.
+type Order struct {
+	ID            int64        `json:"id"`
+	ClientOrderID string       `json:"client_order_id"`
+	Pair          string       `json:"pair"`
+	Side          indodax.Side `json:"side"`
+	Type          OrderType    `json:"type"`
+	Price         float64      `json:"price"`
+	StopPrice     float64      `json:"stop_price"`
+	Amount        float64      `json:"amount"`
+	Total         float64      `json:"total"`
+	// Filled in the base coin, Cost in the quote currency and Fee in the
+	// currency received
+	Filled    float64   `json:"filled"`
+	Cost      float64   `json:"cost"`
+	Fee       float64   `json:"fee"`
+	Status    Status    `json:"status"`
+	Triggered bool      `json:"triggered"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+
+	// balance still on hold for the order
+	held float64
+}
+
+// Remaining is the unfilled amount in the base coin, or in the quote
+// currency for orders given by Total
+func (o Order) Remaining() float64 {
+	if o.Total > 0 {
+		return o.Total - o.Cost
+	}
+	return o.Amount - o.Filled
+}
+
+// limit reports whether the order rests in the book at Price
+func (o *Order) limit() bool {
+	return o.Type == Limit || o.Type == StopLimit && o.Triggered
+}
+
+// market reports whether the order fills at any price
+func (o *Order) market() bool {
+	return o.Type == Market || o.Type == Stop && o.Triggered
+}
+
+// Fill is an execution of an order
+type Fill struct {
+	ID       int64        `json:"id"`
+	OrderID  int64        `json:"order_id"`
+	Pair     string       `json:"pair"`
+	Side     indodax.Side `json:"side"`
+	Price    float64      `json:"price"`
+	Amount   float64      `json:"amount"`
+	Fee      float64      `json:"fee"`
+	FeeAsset string       `json:"fee_asset"`
+	Maker    bool         `json:"maker"`
+	Time     time.Time    `json:"time"`
+}
+
+// Balance of an asset, Hold is reserved by open orders
+type Balance struct {
+	Free float64 `json:"free"`
+	Hold float64 `json:"hold"`
+}
+
+// Level of the book, Amount is in the base coin
+type Level struct {
+	Price  float64 `json:"price"`
+	Amount float64 `json:"amount"`
+}
+
+// Book of the resting limit orders of a pair, best prices first
+type Book struct {
+	Bids []Level `json:"bids"`
+	Asks []Level `json:"asks"`
+}
+
+// Config of an Exchange
+type Config struct {
+	MakerFee float64
+	TakerFee float64
+	// Balances to start with by asset, e.g. idr
+	Balances map[string]float64
+	// Now stamps orders placed before any market data, time.Now when nil.
+	// Replayed data moves the clock afterwards.
+	Now func() time.Time
+}
+
+// DefaultConfig charges 0.1% to makers and 0.3% to takers
+func DefaultConfig() Config {
+	return Config{
+		MakerFee: 0.001,
+		TakerFee: 0.003,
+	}
+}
+
+// Exchange is the simulator, it is safe for concurrent use
+type Exchange struct {
+	mu       sync.Mutex
+	cfg      Config
+	balances map[string]*Balance
+	orders   map[int64]*Order
+	// ids of the orders of a pair in placement order
+	byPair  map[string][]int64
+	fills   []Fill
+	last    map[string]float64
+	clock   time.Time
+	orderID int64
+	fillID  int64
+}
+
+// New returns an exchange with the balances of cfg
+func New(cfg Config) *Exchange {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	e := &Exchange{
+		cfg:      cfg,
+		balances: map[string]*Balance{},
+		orders:   map[int64]*Order{},
+		byPair:   map[string][]int64{},
+		last:     map[string]float64{},
+	}
+	for asset, v := range cfg.Balances {
+		e.balance(asset).Free = v
+	}
+	return e
+}
+
+// Deposit adds amount to the free balance of asset
+func (e *Exchange) Deposit(asset string, amount float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.balance(asset).Free += amount
+}
+
+// Balances returns a copy of every balance
+func (e *Exchange) Balances() map[string]Balance {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make(map[string]Balance, len(e.balances))
+	for k, v := range e.balances {
+		out[k] = *v
+	}
+	return out
+}
+
+// Now is the time of the last market data, or Config.Now before any
+func (e *Exchange) Now() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.now()
+}
+
+// LastPrice of a pair, false before any market data
+func (e *Exchange) LastPrice(pair string) (float64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	p, ok := e.last[pair]
+	return p, ok
+}
+
+// Place validates o, holds the balance it needs and fills it at once when
+// it is marketable. The placed order is returned.
+func (e *Exchange) Place(o Order) (Order, error) {
+	if o.Type == "" {
+		o.Type = Limit
+	}
+	base, quote, err := splitPair(o.Pair)
+	if err != nil {
+		return Order{}, err
+	}
+	if err := validate(&o); err != nil {
+		return Order{}, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	asset, hold := quote, o.Total
+	switch {
+	case o.Side == indodax.Sell:
+		asset, hold = base, o.Amount
+	case o.Type == Limit || o.Type == StopLimit:
+		hold = o.Amount * o.Price
+	}
+	b := e.balance(asset)
+	if b.Free < hold-epsilon(hold) {
+		return Order{}, fmt.Errorf("%w: %s %v needed, %v free", ErrInsufficientBalance, asset, hold, b.Free)
+	}
+	b.Free -= hold
+	b.Hold += hold
+
+	e.orderID++
+	o.ID = e.orderID
+	o.Status = Open
+	o.Triggered = false
+	o.Filled, o.Cost, o.Fee = 0, 0, 0
+	o.Created = e.now()
+	o.Updated = o.Created
+	o.held = hold
+	e.orders[o.ID] = &o
+	e.byPair[o.Pair] = append(e.byPair[o.Pair], o.ID)
+
+	if p, ok := e.last[o.Pair]; ok {
+		e.trigger(&o, p)
+		if o.market() || o.limit() && marketable(&o, p) {
+			e.fill(&o, p, math.Inf(1), false, o.Created)
+		}
+	}
+	return o, nil
+}
+
+// Cancel cancels an open order and releases its hold
+func (e *Exchange) Cancel(id int64) (Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	o, ok := e.orders[id]
+	if !ok {
+		return Order{}, ErrUnknownOrder
+	}
+	if o.Status != Open {
+		return Order{}, ErrOrderClosed
+	}
+	e.release(o)
+	o.Status = Cancelled
+	o.Updated = e.now()
+	return *o, nil
+}
+
+// Order returns an order by ID
+func (e *Exchange) Order(id int64) (Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	o, ok := e.orders[id]
+	if !ok {
+		return Order{}, ErrUnknownOrder
+	}
+	return *o, nil
+}
+
+// Orders returns the orders of a pair, or of every pair when pair is
+// empty, in placement order. open keeps the open ones only.
+func (e *Exchange) Orders(pair string, open bool) []Order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var out []Order
+	for _, o := range e.sorted(pair) {
+		if !open || o.Status == Open {
+			out = append(out, *o)
+		}
+	}
+	return out
+}
+
+// Fills returns the executions of a pair, or of every pair when pair is
+// empty, oldest first
+func (e *Exchange) Fills(pair string) []Fill {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var out []Fill
+	for _, f := range e.fills {
+		if pair == "" || f.Pair == pair {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Book returns the resting limit orders of a pair by price level
+func (e *Exchange) Book(pair string) Book {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	bids, asks := map[float64]float64{}, map[float64]float64{}
+	for _, o := range e.sorted(pair) {
+		if o.Status != Open || !o.limit() {
+			continue
+		}
+		if o.Side == indodax.Buy {
+			bids[o.Price] += o.Amount - o.Filled
+		} else {
+			asks[o.Price] += o.Amount - o.Filled
+		}
+	}
+
+	var book Book
+	for p, a := range bids {
+		book.Bids = append(book.Bids, Level{Price: p, Amount: a})
+	}
+	for p, a := range asks {
+		book.Asks = append(book.Asks, Level{Price: p, Amount: a})
+	}
+	sort.Slice(book.Bids, func(i, j int) bool { return book.Bids[i].Price > book.Bids[j].Price })
+	sort.Slice(book.Asks, func(i, j int) bool { return book.Asks[i].Price < book.Asks[j].Price })
+	return book
+}
+
+func validate(o *Order) error {
+	if o.Side != indodax.Buy && o.Side != indodax.Sell {
+		return errors.New("paper: side must be buy or sell")
+	}
+	switch o.Type {
+	case Market, Stop:
+		if o.Side == indodax.Buy && o.Total <= 0 {
+			return errors.New("paper: market buys need a positive total")
+		}
+		if o.Side == indodax.Sell && o.Amount <= 0 {
+			return errors.New("paper: amount must be positive")
+		}
+	case Limit, StopLimit:
+		if o.Price <= 0 || o.Amount <= 0 {
+			return errors.New("paper: price and amount must be positive")
+		}
+		o.Total = 0
+	default:
+		return fmt.Errorf("paper: unknown order type %q", o.Type)
+	}
+	if (o.Type == Stop || o.Type == StopLimit) && o.StopPrice <= 0 {
+		return errors.New("paper: stop price must be positive")
+	}
+	if o.Side == indodax.Sell {
+		o.Total = 0
+	}
+	return nil
+}
+
+func splitPair(pair string) (string, string, error) {
+	base, quote, ok := strings.Cut(pair, "_")
+	if !ok || base == "" || quote == "" {
+		return "", "", fmt.Errorf("paper: pair must be base_quote, got %q", pair)
+	}
+	return base, quote, nil
+}
+
+func (e *Exchange) balance(asset string) *Balance {
+	b, ok := e.balances[asset]
+	if !ok {
+		b = &Balance{}
+		e.balances[asset] = b
+	}
+	return b
+}
+
+func (e *Exchange) now() time.Time {
+	if !e.clock.IsZero() {
+		return e.clock
+	}
+	return e.cfg.Now()
+}
+
+// sorted returns the orders of pair, or every pair, by ID
+func (e *Exchange) sorted(pair string) []*Order {
+	var out []*Order
+	if pair != "" {
+		for _, id := range e.byPair[pair] {
+			out = append(out, e.orders[id])
+		}
+		return out
+	}
+	for _, o := range e.orders {
+		out = append(out, o)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// epsilon absorbs float rounding when comparing amounts to v
+func epsilon(v float64) float64 {
+	return math.Abs(v) * 1e-9
+}
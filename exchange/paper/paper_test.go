@@ -0,0 +1,169 @@
+package paper_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/exchange/indodax"
+	"github.com/Fatiri/areuy/exchange/paper"
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/net"
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newExchange() *paper.Exchange {
+	cfg := paper.DefaultConfig()
+	cfg.Balances = map[string]float64{"idr": 1000000, "btc": 1}
+	cfg.Now = func() time.Time { return start }
+	return paper.New(cfg)
+}
+
+func TestExchange(t *testing.T) {
+	tests := []struct {
+		name                string
+		funcUseCaseShouldBe func(t *testing.T, ex *paper.Exchange)
+	}{
+		{
+			name: "Resting limit buy fills at its price as maker",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange) {
+				ex.OnTrade("btc_idr", 1000, 0, start)
+				o, err := ex.Place(paper.Order{Pair: "btc_idr", Side: indodax.Buy, Price: 900, Amount: 100})
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, paper.Open, o.Status, "they should be equal")
+				assert.Equal(t, paper.Balance{Free: 910000, Hold: 90000}, ex.Balances()["idr"], "they should be equal")
+				assert.Equal(t, paper.Book{Bids: []paper.Level{{Price: 900, Amount: 100}}}, ex.Book("btc_idr"), "they should be equal")
+
+				ex.OnCandle("btc_idr", indikators.Candle{Time: start.Add(time.Hour), Open: 1000, High: 1010, Low: 850, Close: 950})
+				o, _ = ex.Order(o.ID)
+				assert.Equal(t, paper.Filled, o.Status, "they should be equal")
+				assert.InDelta(t, 0.1, o.Fee, 1e-9, "they should be equal")
+				assert.Equal(t, paper.Balance{Free: 910000}, ex.Balances()["idr"], "they should be equal")
+				assert.InDelta(t, 100.9, ex.Balances()["btc"].Free, 1e-9, "they should be equal")
+				assert.True(t, ex.Fills("btc_idr")[0].Maker, "it should be maker")
+				assert.Equal(t, paper.Book{}, ex.Book("btc_idr"), "they should be equal")
+			},
+		},
+		{
+			name: "Marketable limit fills at the last price as taker",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange) {
+				ex.OnTrade("btc_idr", 1000, 0, start)
+				o, _ := ex.Place(paper.Order{Pair: "btc_idr", Side: indodax.Buy, Price: 1100, Amount: 10})
+				assert.Equal(t, paper.Filled, o.Status, "they should be equal")
+				assert.Equal(t, 10000.0, o.Cost, "they should be equal")
+				assert.Equal(t, paper.Balance{Free: 990000}, ex.Balances()["idr"], "they should refund the limit price difference")
+				assert.InDelta(t, 10.97, ex.Balances()["btc"].Free, 1e-9, "they should be equal")
+			},
+		},
+		{
+			name: "Market buy waits for a price and spends its total",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange) {
+				o, err := ex.Place(paper.Order{Pair: "btc_idr", Side: indodax.Buy, Type: paper.Market, Total: 50000})
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, paper.Open, o.Status, "they should be equal")
+
+				ex.OnTrade("btc_idr", 1000, 20, start)
+				o, _ = ex.Order(o.ID)
+				assert.Equal(t, 20.0, o.Filled, "they should be capped by the trade amount")
+				ex.OnTrade("btc_idr", 1000, 0, start)
+				o, _ = ex.Order(o.ID)
+				assert.Equal(t, paper.Filled, o.Status, "they should be equal")
+				assert.Equal(t, 50000.0, o.Cost, "they should be equal")
+				assert.Equal(t, paper.Balance{Free: 950000}, ex.Balances()["idr"], "they should be equal")
+			},
+		},
+		{
+			name: "Stop sell triggers on the way down",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange) {
+				ex.OnTrade("btc_idr", 1000, 0, start)
+				o, _ := ex.Place(paper.Order{Pair: "btc_idr", Side: indodax.Sell, Type: paper.Stop, StopPrice: 950, Amount: 0.5})
+				ex.OnTrade("btc_idr", 960, 0, start)
+				o, _ = ex.Order(o.ID)
+				assert.False(t, o.Triggered, "it should be false")
+
+				ex.OnTrade("btc_idr", 940, 0, start.Add(time.Minute))
+				o, _ = ex.Order(o.ID)
+				assert.Equal(t, paper.Filled, o.Status, "they should be equal")
+				assert.Equal(t, 470.0, o.Cost, "they should be equal")
+				assert.InDelta(t, 1000000+470*0.997, ex.Balances()["idr"].Free, 1e-6, "they should be equal")
+				assert.Equal(t, start.Add(time.Minute), o.Updated, "they should be equal")
+			},
+		},
+		{
+			name: "Cancel releases the hold",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange) {
+				o, _ := ex.Place(paper.Order{Pair: "btc_idr", Side: indodax.Sell, Price: 2000, Amount: 0.4})
+				assert.Equal(t, paper.Balance{Free: 0.6, Hold: 0.4}, ex.Balances()["btc"], "they should be equal")
+				_, err := ex.Cancel(o.ID)
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, paper.Balance{Free: 1}, ex.Balances()["btc"], "they should be equal")
+				_, err = ex.Cancel(o.ID)
+				assert.Equal(t, paper.ErrOrderClosed, err, "they should be equal")
+			},
+		},
+		{
+			name: "Insufficient balance",
+			funcUseCaseShouldBe: func(t *testing.T, ex *paper.Exchange) {
+				_, err := ex.Place(paper.Order{Pair: "btc_idr", Side: indodax.Sell, Price: 2000, Amount: 2})
+				assert.True(t, errors.Is(err, paper.ErrInsufficientBalance), "it should be insufficient balance")
+				assert.Empty(t, ex.Orders("", false), "it should be empty")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.funcUseCaseShouldBe(t, newExchange())
+		})
+	}
+}
+
+func TestIndodaxHandler(t *testing.T) {
+	ex := newExchange()
+	ex.OnTrade("btc_idr", 1000, 0, start)
+	srv := httptest.NewServer(paper.IndodaxHandler(ex.Indodax(), "secret"))
+	defer srv.Close()
+
+	cfg := indodax.DefaultConfig("key", "secret")
+	cfg.BaseURL, cfg.PublicURL = srv.URL+"/tapi", srv.URL+"/api"
+	c := indodax.NewClient(cfg, net.ProvideIHTTPClient())
+
+	st, err := c.ServerTime()
+	assert.NoError(t, err, "they should be no error")
+	assert.True(t, st.Equal(start), "they should be the simulator clock")
+
+	res, err := c.Trade(indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Buy, Price: 900, Amount: 90000})
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, indodax.Number(90000), res.Amounts["remain_rp"], "they should be equal")
+
+	open, err := c.OpenOrders("btc_idr")
+	assert.NoError(t, err, "they should be no error")
+	assert.Len(t, open["btc_idr"], 1, "they should be equal")
+	assert.Equal(t, indodax.Number(90000), open["btc_idr"][0].Amounts["order_idr"], "they should be equal")
+
+	info, err := c.GetInfo()
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, indodax.Number(90000), info.BalanceHold["idr"], "they should be equal")
+
+	_, err = c.Trade(indodax.TradeRequest{Pair: "btc_idr", Side: indodax.Sell, Price: 900, Amount: 5})
+	assert.True(t, indodax.IsCode(err, "insufficient_balance"), "it should be insufficient balance")
+
+	ex.OnTrade("btc_idr", 890, 0, start.Add(time.Minute))
+	o, err := c.GetOrder("btc_idr", int64(res.OrderID))
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, "filled", o.Status, "they should be equal")
+
+	trades, err := c.TradeHistory(indodax.TradeHistoryQuery{Pair: "btc_idr"})
+	assert.NoError(t, err, "they should be no error")
+	assert.Len(t, trades, 1, "they should be equal")
+	assert.Equal(t, indodax.Number(100), trades[0].Amounts["btc"], "they should be equal")
+
+	_, err = c.CancelOrder("btc_idr", int64(res.OrderID), indodax.Buy)
+	assert.True(t, indodax.IsCode(err, "order_not_open"), "it should be not open")
+
+	_, err = indodax.NewClient(indodax.Config{BaseURL: cfg.BaseURL, Secret: "wrong"}, net.ProvideIHTTPClient()).GetInfo()
+	assert.True(t, indodax.IsCode(err, indodax.CodeInvalidCredentials), "it should be invalid credentials")
+}
@@ -0,0 +1,71 @@
+package indikators
+
+// Developed by Marc Chaikin, the Accumulation Distribution Line is a
+// volume-based indicator designed to measure the cumulative flow of money
+// into and out of a security. Chaikin originally referred to the indicator
+// as the Cumulative Money Flow Line. As with cumulative indicators, the
+// Accumulation Distribution Line is a running total of each period's Money
+// Flow Volume. First, a multiplier is calculated based on the relationship
+// of the close to the high-low range. Second, the Money Flow Multiplier is
+// multiplied by the period's volume to come up with a Money Flow Volume.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:accumulation_distribution_line
+//  https://www.investopedia.com/terms/a/accumulationdistribution.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/accumulation-distribution
+type Ad struct {
+	ad float64
+	sz int64
+}
+
+func NewAd() *Ad {
+	return &Ad{
+		ad: 0,
+		sz: 0,
+	}
+}
+
+func (a *Ad) Update(c Candle) float64 {
+	a.sz++
+
+	hl := c.High - c.Low
+	if hl > 0 {
+		a.ad += ((c.Close - c.Low) - (c.High - c.Close)) / hl * c.Volume
+	}
+
+	return a.ad
+}
+
+func (a *Ad) InitPeriod() int64 {
+	return 0
+}
+
+func (a *Ad) Valid() bool {
+	return a.sz > a.InitPeriod()
+}
+
+func (a *Ad) state(s *stateCodec) {
+	s.float(&a.ad)
+	s.int(&a.sz)
+}
+
+// Developed by Marc Chaikin, the Accumulation Distribution Line is a
+// volume-based indicator designed to measure the cumulative flow of money
+// into and out of a security. Chaikin originally referred to the indicator
+// as the Cumulative Money Flow Line. As with cumulative indicators, the
+// Accumulation Distribution Line is a running total of each period's Money
+// Flow Volume. First, a multiplier is calculated based on the relationship
+// of the close to the high-low range. Second, the Money Flow Multiplier is
+// multiplied by the period's volume to come up with a Money Flow Volume.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:accumulation_distribution_line
+//  https://www.investopedia.com/terms/a/accumulationdistribution.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/accumulation-distribution
+func AdArr(in []Candle, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	a := NewAd()
+	for i, v := range in {
+		out[i] = a.Update(v)
+	}
+	warmup(mode, a.InitPeriod(), out)
+
+	return out
+}
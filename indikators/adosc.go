@@ -0,0 +1,97 @@
+package indikators
+
+// Developed by Marc Chaikin, the Chaikin Oscillator measures the momentum
+// of the Accumulation Distribution Line using the MACD formula. This makes
+// it an indicator of an indicator. The Chaikin Oscillator is the difference
+// between the 3-day EMA of the Accumulation Distribution Line and the
+// 10-day EMA of the Accumulation Distribution Line. Like other momentum
+// indicators, this indicator is designed to anticipate directional changes
+// in the Accumulation Distribution Line by measuring the momentum behind
+// the movements.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:chaikin_oscillator
+//  https://www.investopedia.com/terms/c/chaikinoscillator.asp
+type AdOsc struct {
+	fastK float64
+	slowK float64
+	n     int64
+	ad    *Ad
+	fast  float64
+	slow  float64
+	sz    int64
+}
+
+func NewAdOsc(fastN, slowN int64) *AdOsc {
+	if slowN < fastN {
+		fastN, slowN = slowN, fastN
+	}
+	return &AdOsc{
+		fastK: 2.0 / float64(fastN+1),
+		slowK: 2.0 / float64(slowN+1),
+		n:     slowN,
+		ad:    NewAd(),
+		fast:  0,
+		slow:  0,
+		sz:    0,
+	}
+}
+
+func (a *AdOsc) Update(c Candle) float64 {
+	a.sz++
+
+	ad := a.ad.Update(c)
+
+	// Unlike Ema, both averages are seeded with the first A/D value
+	// rather than a simple average, which is how Chaikin defined it.
+	if a.sz == 1 {
+		a.fast = ad
+		a.slow = ad
+	} else {
+		a.fast = ad*a.fastK + a.fast*(1-a.fastK)
+		a.slow = ad*a.slowK + a.slow*(1-a.slowK)
+	}
+
+	if a.sz < a.n {
+		return 0
+	}
+
+	return a.fast - a.slow
+}
+
+func (a *AdOsc) InitPeriod() int64 {
+	return a.n - 1
+}
+
+func (a *AdOsc) Valid() bool {
+	return a.sz > a.InitPeriod()
+}
+
+func (a *AdOsc) state(s *stateCodec) {
+	s.param(a.n)
+	s.paramFloat(a.fastK)
+	s.sub(a.ad)
+	s.float(&a.fast)
+	s.float(&a.slow)
+	s.int(&a.sz)
+}
+
+// Developed by Marc Chaikin, the Chaikin Oscillator measures the momentum
+// of the Accumulation Distribution Line using the MACD formula. This makes
+// it an indicator of an indicator. The Chaikin Oscillator is the difference
+// between the 3-day EMA of the Accumulation Distribution Line and the
+// 10-day EMA of the Accumulation Distribution Line. Like other momentum
+// indicators, this indicator is designed to anticipate directional changes
+// in the Accumulation Distribution Line by measuring the momentum behind
+// the movements.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:chaikin_oscillator
+//  https://www.investopedia.com/terms/c/chaikinoscillator.asp
+func AdOscArr(in []Candle, fastN, slowN int64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	a := NewAdOsc(fastN, slowN)
+	for i, v := range in {
+		out[i] = a.Update(v)
+	}
+	warmup(mode, a.InitPeriod(), out)
+
+	return out
+}
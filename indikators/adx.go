@@ -0,0 +1,105 @@
+package indikators
+
+import (
+	"math"
+)
+
+// The Average Directional Index (ADX) is used to measure the strength
+// or weakness of a trend, not the actual direction. Directional
+// movement is defined by +DI and -DI. In general, the bulls have the
+// edge when +DI is greater than -DI, while the bears have the edge
+// when -DI is greater. Crosses of these directional indicators can
+// be combined with ADX for a complete trading system. ADX above 25
+// is usually read as a trending market and below 20 as a ranging one.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:average_directional_index_adx
+//  https://www.investopedia.com/terms/a/adx.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/adx
+type Adx struct {
+	n     int64
+	dmi   *Dmi
+	sumDx float64
+	adx   float64
+	sz    int64
+}
+
+func NewAdx(n int64) *Adx {
+	return &Adx{
+		n:     n,
+		dmi:   NewDmi(n),
+		sumDx: 0,
+		adx:   0,
+		sz:    0,
+	}
+}
+
+func (a *Adx) Update(c Candle) float64 {
+	a.sz++
+
+	pdi, mdi := a.dmi.Update(c)
+	if !a.dmi.Valid() {
+		return 0
+	}
+
+	// dx bars seen so far
+	cnt := a.sz - a.dmi.InitPeriod()
+
+	sum := pdi + mdi
+	valid := !almostZero(sum)
+	var dx float64
+	if valid {
+		dx = math.Abs(pdi-mdi) / sum * 100.0
+	}
+
+	if cnt < a.n {
+		a.sumDx += dx
+		return 0
+	} else if cnt == a.n {
+		a.sumDx += dx
+		a.adx = a.sumDx / float64(a.n)
+		return a.adx
+	}
+
+	if valid {
+		a.adx = (a.adx*float64(a.n-1) + dx) / float64(a.n)
+	}
+
+	return a.adx
+}
+
+func (a *Adx) InitPeriod() int64 {
+	return a.n*2 - 1
+}
+
+func (a *Adx) Valid() bool {
+	return a.sz > a.InitPeriod()
+}
+
+func (a *Adx) state(s *stateCodec) {
+	s.param(a.n)
+	s.sub(a.dmi)
+	s.float(&a.sumDx)
+	s.float(&a.adx)
+	s.int(&a.sz)
+}
+
+// The Average Directional Index (ADX) is used to measure the strength
+// or weakness of a trend, not the actual direction. Directional
+// movement is defined by +DI and -DI. In general, the bulls have the
+// edge when +DI is greater than -DI, while the bears have the edge
+// when -DI is greater. Crosses of these directional indicators can
+// be combined with ADX for a complete trading system. ADX above 25
+// is usually read as a trending market and below 20 as a ranging one.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:average_directional_index_adx
+//  https://www.investopedia.com/terms/a/adx.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/adx
+func AdxArr(in []Candle, n int64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	a := NewAdx(n)
+	for i, v := range in {
+		out[i] = a.Update(v)
+	}
+	warmup(mode, a.InitPeriod(), out)
+
+	return out
+}
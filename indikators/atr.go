@@ -0,0 +1,82 @@
+package indikators
+
+// Developed by J. Welles Wilder, the Average True Range (ATR) is an
+// indicator that measures volatility. As with most of his indicators,
+// Wilder designed ATR with commodities and daily prices in mind.
+// Commodities are frequently more volatile than stocks. They are
+// often subject to gaps and limit moves, which occur when a commodity
+// opens up or down its maximum allowed move for the session. A
+// volatility formula based only on the high-low range would fail to
+// capture volatility from gap or limit moves. Wilder created Average
+// True Range to capture this "missing" volatility. It is important to
+// remember that ATR does not provide an indication of price direction,
+// just volatility.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:average_true_range_atr
+//  https://www.investopedia.com/terms/a/atr.asp
+type Atr struct {
+	n   int64
+	tr  *TRange
+	ema *Ema
+	sz  int64
+}
+
+func NewAtr(n int64) *Atr {
+	k := 1.0 / float64(n)
+	return &Atr{
+		n:   n,
+		tr:  NewTRange(),
+		ema: NewEma(n, k),
+		sz:  0,
+	}
+}
+
+func (a *Atr) Update(c Candle) float64 {
+	a.sz++
+
+	tr := a.tr.Update(c)
+	if a.sz == 1 {
+		return 0
+	}
+
+	return a.ema.Update(tr)
+}
+
+func (a *Atr) InitPeriod() int64 {
+	return a.n
+}
+
+func (a *Atr) Valid() bool {
+	return a.sz > a.InitPeriod()
+}
+
+func (a *Atr) state(s *stateCodec) {
+	s.param(a.n)
+	s.sub(a.tr)
+	s.sub(a.ema)
+	s.int(&a.sz)
+}
+
+// Developed by J. Welles Wilder, the Average True Range (ATR) is an
+// indicator that measures volatility. As with most of his indicators,
+// Wilder designed ATR with commodities and daily prices in mind.
+// Commodities are frequently more volatile than stocks. They are
+// often subject to gaps and limit moves, which occur when a commodity
+// opens up or down its maximum allowed move for the session. A
+// volatility formula based only on the high-low range would fail to
+// capture volatility from gap or limit moves. Wilder created Average
+// True Range to capture this "missing" volatility. It is important to
+// remember that ATR does not provide an indication of price direction,
+// just volatility.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:average_true_range_atr
+//  https://www.investopedia.com/terms/a/atr.asp
+func AtrArr(in []Candle, n int64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	a := NewAtr(n)
+	for i, v := range in {
+		out[i] = a.Update(v)
+	}
+	warmup(mode, a.InitPeriod(), out)
+
+	return out
+}
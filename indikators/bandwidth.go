@@ -0,0 +1,66 @@
+package indikators
+
+// Bollinger BandWidth is the distance between the upper and lower
+// Bollinger Band relative to the middle band, (upper - lower) / middle.
+// It narrows when volatility contracts and widens when it expands, the
+// lowest readings mark the Bollinger Band Squeeze that often precedes
+// a volatility breakout, see Squeeze.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:bollinger_band_width
+//  https://www.investopedia.com/articles/technical/04/030304.asp
+type BandWidth struct {
+	bb *BBands
+}
+
+func NewBandWidth(t MaType, n int64, upNStdDev, dnNStdDev float64) *BandWidth {
+	return &BandWidth{
+		bb: NewBBands(t, n, upNStdDev, dnNStdDev),
+	}
+}
+
+func (b *BandWidth) Update(v float64) float64 {
+	u, m, l := b.bb.Update(v)
+
+	if !b.bb.Valid() {
+		return 0
+	}
+
+	return bandWidth(u, m, l)
+}
+
+func bandWidth(u, m, l float64) float64 {
+	if almostZero(m) {
+		return 0
+	}
+	return (u - l) / m
+}
+
+func (b *BandWidth) InitPeriod() int64 {
+	return b.bb.InitPeriod()
+}
+
+func (b *BandWidth) Valid() bool {
+	return b.bb.Valid()
+}
+
+func (b *BandWidth) state(s *stateCodec) {
+	s.sub(b.bb)
+}
+
+// Bollinger BandWidth is the distance between the upper and lower
+// Bollinger Band relative to the middle band, (upper - lower) / middle.
+// It narrows when volatility contracts and widens when it expands, the
+// lowest readings mark the Bollinger Band Squeeze that often precedes
+// a volatility breakout, see Squeeze.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:bollinger_band_width
+//  https://www.investopedia.com/articles/technical/04/030304.asp
+func BandWidthArr(t MaType, in []float64, n int64, upNStdDev, dnNStdDev float64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	b := NewBandWidth(t, n, upNStdDev, dnNStdDev)
+	for i, v := range in {
+		out[i] = b.Update(v)
+	}
+	warmup(mode, b.InitPeriod(), out)
+
+	return out
+}
@@ -49,7 +49,7 @@ func (b *BBands) Update(v float64) (float64, float64, float64) {
 	m := b.ma.Update(v)
 	stddev := b.stdDev.Update(v)
 
-	return m + b.upNStdDev*stddev, m, m - b.upNStdDev*stddev
+	return m + b.upNStdDev*stddev, m, m - b.dnNStdDev*stddev
 }
 
 func (b *BBands) InitPeriod() int64 {
@@ -60,6 +60,14 @@ func (b *BBands) Valid() bool {
 	return b.sz > b.initPeriod
 }
 
+func (b *BBands) state(s *stateCodec) {
+	s.paramFloat(b.upNStdDev)
+	s.paramFloat(b.dnNStdDev)
+	s.sub(b.ma)
+	s.sub(b.stdDev)
+	s.int(&b.sz)
+}
+
 // Developed by John Bollinger, Bollinger Bands are volatility
 // bands placed above and below a moving average. Volatility
 // is based on the standard deviation, which changes as volatility
@@ -75,7 +83,7 @@ func (b *BBands) Valid() bool {
 //  https://school.stockcharts.com/doku.php?id=technical_indicators:bollinger_bands
 //  https://www.investopedia.com/terms/b/bollingerbands.asp
 //  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/bollinger-bands
-func BBandsArr(t MaType, in []float64, n int64, upNStdDev, dnNStdDev float64) ([]float64, []float64, []float64) {
+func BBandsArr(t MaType, in []float64, n int64, upNStdDev, dnNStdDev float64, mode ...WarmupMode) ([]float64, []float64, []float64) {
 	m := make([]float64, len(in))
 	u := make([]float64, len(in))
 	l := make([]float64, len(in))
@@ -84,6 +92,7 @@ func BBandsArr(t MaType, in []float64, n int64, upNStdDev, dnNStdDev float64) ([
 	for i, v := range in {
 		u[i], m[i], l[i] = b.Update(v)
 	}
+	warmup(mode, b.InitPeriod(), u, m, l)
 
 	return u, m, l
 }
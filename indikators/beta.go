@@ -0,0 +1,112 @@
+package indikators
+
+// Beta measures the volatility of a security (y) relative to a benchmark
+// (x), the slope of the regression of the y returns on the x returns over
+// the last n bars. A beta of 1 means y moves with x, above 1 it amplifies
+// the moves of x and below 1 it dampens them. In pair trading it is the
+// hedge ratio between both legs. Like TA-Lib it works on the one bar
+// percentage changes of both series.
+//  https://www.investopedia.com/terms/b/beta.asp
+//  https://www.investopedia.com/terms/p/pairstrade.asp
+type Beta struct {
+	n     int64
+	x     *CBuf
+	y     *CBuf
+	prevX float64
+	prevY float64
+	sumX  float64
+	sumY  float64
+	sumXX float64
+	sumXY float64
+	sz    int64
+}
+
+func NewBeta(n int64) *Beta {
+	return &Beta{
+		n:  n,
+		x:  NewCBuf(n),
+		y:  NewCBuf(n),
+		sz: 0,
+	}
+}
+
+// Update takes the latest benchmark (x) and security (y) prices
+func (b *Beta) Update(x, y float64) float64 {
+	b.sz++
+
+	prevX, prevY := b.prevX, b.prevY
+	b.prevX, b.prevY = x, y
+
+	if b.sz == 1 {
+		return 0
+	}
+
+	rx := float64(0)
+	if prevX != 0 {
+		rx = (x - prevX) / prevX
+	}
+	ry := float64(0)
+	if prevY != 0 {
+		ry = (y - prevY) / prevY
+	}
+
+	oldX := b.x.Append(rx)
+	oldY := b.y.Append(ry)
+	b.sumX += rx - oldX
+	b.sumY += ry - oldY
+	b.sumXX += rx*rx - oldX*oldX
+	b.sumXY += rx*ry - oldX*oldY
+
+	if b.sz <= b.n {
+		return 0
+	}
+
+	n := float64(b.n)
+	d := n*b.sumXX - b.sumX*b.sumX
+	if almostZero(d) {
+		return 0
+	}
+
+	return (n*b.sumXY - b.sumX*b.sumY) / d
+}
+
+func (b *Beta) InitPeriod() int64 {
+	return b.n
+}
+
+func (b *Beta) Valid() bool {
+	return b.sz > b.InitPeriod()
+}
+
+func (b *Beta) state(s *stateCodec) {
+	s.param(b.n)
+	s.sub(b.x)
+	s.sub(b.y)
+	s.float(&b.prevX)
+	s.float(&b.prevY)
+	s.float(&b.sumX)
+	s.float(&b.sumY)
+	s.float(&b.sumXX)
+	s.float(&b.sumXY)
+	s.int(&b.sz)
+}
+
+// Beta measures the volatility of a security (y) relative to a benchmark
+// (x), the slope of the regression of the y returns on the x returns over
+// the last n bars. A beta of 1 means y moves with x, above 1 it amplifies
+// the moves of x and below 1 it dampens them. In pair trading it is the
+// hedge ratio between both legs. Like TA-Lib it works on the one bar
+// percentage changes of both series.
+//  https://www.investopedia.com/terms/b/beta.asp
+//  https://www.investopedia.com/terms/p/pairstrade.asp
+func BetaArr(x, y []float64, n int64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(x))
+
+	b := NewBeta(n)
+	for i := range x {
+		out[i] = b.Update(x[i], y[i])
+	}
+	warmup(mode, b.InitPeriod(), out)
+
+	return out
+}
@@ -0,0 +1,38 @@
+package indikators
+
+import (
+	"time"
+)
+
+// Candle is a single OHLCV bar. Indicators which need more than the
+// close price (true range, directional movement, stochastic, volume
+// based indicators, ...) consume a stream of candles instead of a
+// stream of float64.
+type Candle struct {
+	Time   time.Time `json:"time"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume float64   `json:"volume"`
+}
+
+// Typical price, (high + low + close) / 3
+func (c Candle) Typical() float64 {
+	return (c.High + c.Low + c.Close) / 3.0
+}
+
+// Median price, (high + low) / 2
+func (c Candle) Median() float64 {
+	return (c.High + c.Low) / 2.0
+}
+
+// Closes extracts close prices so candle series can be fed to the
+// single value indicators (Sma, Ema, Rsi, ...)
+func Closes(in []Candle) []float64 {
+	out := make([]float64, len(in))
+	for i, c := range in {
+		out[i] = c.Close
+	}
+	return out
+}
@@ -1,25 +1,26 @@
 package indikators
 
-// circular buffer maintaining fixed sized history
-type CBuf struct {
+// circular buffer maintaining fixed sized history of any type, e.g.
+// candles or int64 timestamps
+type CBufOf[T any] struct {
 	n      int64
-	hist   []float64
+	hist   []T
 	oldest int64
 	newest int64
 	sz     int64
 }
 
-func NewCBuf(n int64) *CBuf {
-	return &CBuf{
+func NewCBufOf[T any](n int64) *CBufOf[T] {
+	return &CBufOf[T]{
 		n:      n,
-		hist:   make([]float64, n),
+		hist:   make([]T, n),
 		oldest: 0,
 		newest: n - 1,
 	}
 }
 
 // Append latest value and return oldest one
-func (c *CBuf) Append(v float64) float64 {
+func (c *CBufOf[T]) Append(v T) T {
 	old := c.hist[c.oldest]
 	c.hist[c.oldest] = v
 	c.newest = c.oldest
@@ -29,12 +30,17 @@ func (c *CBuf) Append(v float64) float64 {
 }
 
 // Number of values appended
-func (c *CBuf) Size() int64 {
+func (c *CBufOf[T]) Size() int64 {
 	return c.sz
 }
 
+// Capacity of the buffer
+func (c *CBufOf[T]) Cap() int64 {
+	return c.n
+}
+
 // From circular buf position to total sequence index
-func (c *CBuf) IndexToSeq(idx int64) int64 {
+func (c *CBufOf[T]) IndexToSeq(idx int64) int64 {
 	if idx < c.oldest {
 		return c.sz - (c.oldest - idx)
 	} else {
@@ -43,28 +49,49 @@ func (c *CBuf) IndexToSeq(idx int64) int64 {
 }
 
 // Index of the latest value
-func (c *CBuf) NewestIndex() int64 {
+func (c *CBufOf[T]) NewestIndex() int64 {
 	return c.newest
 }
 
 // Index of the oldest value
-func (c *CBuf) OldestIndex() int64 {
+func (c *CBufOf[T]) OldestIndex() int64 {
 	return c.oldest
 }
 
 // nthNewest(0) = newest
 // nthNewest(1) = 2nd newest
-func (c *CBuf) NthNewest(offset int64) float64 {
+func (c *CBufOf[T]) NthNewest(offset int64) T {
 	return c.hist[(c.newest+c.n-offset)%c.n]
 }
 
 // nthOldest(0) = oldest
 // nthOldest(1) = 2nd oldest
-func (c *CBuf) NthOldest(offset int64) float64 {
+func (c *CBufOf[T]) NthOldest(offset int64) T {
 	return c.hist[(c.oldest+offset)%c.n]
 }
 
-// Min value in buf
+// Iterate through buf elements and call function for each
+func (c *CBufOf[T]) Iter(fn func(v T)) {
+	idx := c.oldest
+	for i := int64(0); i < c.n; i++ {
+		fn(c.hist[idx])
+		idx = (idx + 1) % c.n
+	}
+}
+
+// circular buffer maintaining fixed sized history
+type CBuf struct {
+	CBufOf[float64]
+}
+
+func NewCBuf(n int64) *CBuf {
+	return &CBuf{
+		CBufOf: *NewCBufOf[float64](n),
+	}
+}
+
+// Min value in buf, scans the whole buffer, use NewRollingMin to track the
+// minimum of a sliding window
 func (c *CBuf) Min() (int64, float64) {
 	min := c.hist[0]
 	minIdx := int64(0)
@@ -77,7 +104,8 @@ func (c *CBuf) Min() (int64, float64) {
 	return minIdx, min
 }
 
-// Max value in buf
+// Max value in buf, scans the whole buffer, use NewRollingMax to track the
+// maximum of a sliding window
 func (c *CBuf) Max() (int64, float64) {
 	max := c.hist[0]
 	maxIdx := int64(0)
@@ -90,11 +118,10 @@ func (c *CBuf) Max() (int64, float64) {
 	return maxIdx, max
 }
 
-// Iterate through buf elements and call function for each
-func (c *CBuf) Iter(fn func(v float64)) {
-	idx := c.oldest
-	for i := int64(0); i < c.n; i++ {
-		fn(c.hist[idx])
-		idx = (idx + 1) % c.n
-	}
+func (c *CBuf) state(s *stateCodec) {
+	s.param(c.n)
+	s.floats(c.hist)
+	s.int(&c.oldest)
+	s.int(&c.newest)
+	s.int(&c.sz)
 }
@@ -0,0 +1,93 @@
+package indikators_test
+
+import (
+	"testing"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCBufOf(t *testing.T) {
+	iter := func(c *indikators.CBufOf[string]) []string {
+		var out []string
+		c.Iter(func(v string) {
+			out = append(out, v)
+		})
+		return out
+	}
+
+	tests := []struct {
+		name                string
+		funcUseCaseShouldBe func(t *testing.T, c *indikators.CBufOf[string])
+	}{
+		{
+			name: "Append returns the value it replaces",
+			funcUseCaseShouldBe: func(t *testing.T, c *indikators.CBufOf[string]) {
+				assert.Equal(t, "", c.Append("a"), "they should be the zero value")
+				c.Append("b")
+				c.Append("c")
+				assert.Equal(t, "a", c.Append("d"), "they should be equal")
+				assert.Equal(t, "b", c.Append("e"), "they should be equal")
+				assert.Equal(t, int64(5), c.Size(), "they should be equal")
+				assert.Equal(t, int64(3), c.Cap(), "they should be equal")
+			},
+		},
+		{
+			name: "Nth newest and oldest",
+			funcUseCaseShouldBe: func(t *testing.T, c *indikators.CBufOf[string]) {
+				for _, v := range []string{"a", "b", "c", "d", "e"} {
+					c.Append(v)
+				}
+				assert.Equal(t, []string{"e", "d", "c"}, []string{c.NthNewest(0), c.NthNewest(1), c.NthNewest(2)}, "they should be equal")
+				assert.Equal(t, []string{"c", "d", "e"}, []string{c.NthOldest(0), c.NthOldest(1), c.NthOldest(2)}, "they should be equal")
+				assert.Equal(t, []string{"c", "d", "e"}, iter(c), "they should be oldest first")
+			},
+		},
+		{
+			name: "Partially filled",
+			funcUseCaseShouldBe: func(t *testing.T, c *indikators.CBufOf[string]) {
+				c.Append("a")
+				assert.Equal(t, "a", c.NthNewest(0), "they should be equal")
+				assert.Equal(t, []string{"", "", "a"}, []string{c.NthOldest(0), c.NthOldest(1), c.NthOldest(2)}, "they should be padded with the zero value")
+			},
+		},
+		{
+			name: "Indexes map to the sequence",
+			funcUseCaseShouldBe: func(t *testing.T, c *indikators.CBufOf[string]) {
+				values := []string{"a", "b", "c", "d", "e"}
+				for _, v := range values {
+					c.Append(v)
+				}
+				assert.Equal(t, int64(4), c.IndexToSeq(c.NewestIndex()), "they should be equal")
+				assert.Equal(t, int64(2), c.IndexToSeq(c.OldestIndex()), "they should be equal")
+				// walking the positions from the oldest one follows the sequence
+				for i := int64(0); i < c.Cap(); i++ {
+					idx := (c.OldestIndex() + i) % c.Cap()
+					seq := c.IndexToSeq(idx)
+					assert.Equal(t, int64(2)+i, seq, "they should be equal")
+					assert.Equal(t, values[seq], c.NthOldest(i), "they should be equal")
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.funcUseCaseShouldBe(t, indikators.NewCBufOf[string](3))
+		})
+	}
+}
+
+func TestCBufMinMax(t *testing.T) {
+	c := indikators.NewCBuf(4)
+	for _, v := range []float64{5, 1, 7, 3, 2, 6} {
+		c.Append(v)
+	}
+	// the buffer holds 2, 6, 7, 3 by position
+	idx, min := c.Min()
+	assert.Equal(t, 2.0, min, "they should be equal")
+	assert.Equal(t, int64(4), c.IndexToSeq(idx), "they should be equal")
+	idx, max := c.Max()
+	assert.Equal(t, 7.0, max, "they should be equal")
+	assert.Equal(t, int64(2), c.IndexToSeq(idx), "they should be equal")
+}
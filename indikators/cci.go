@@ -0,0 +1,90 @@
+package indikators
+
+import (
+	"math"
+)
+
+// Developed by Donald Lambert and featured in Commodities magazine in 1980,
+// the Commodity Channel Index (CCI) is a versatile indicator that can be
+// used to identify a new trend or warn of extreme conditions. CCI measures
+// the current price level relative to an average price level over a given
+// period of time. CCI is relatively high when prices are far above their
+// average and relatively low when prices are far below their average. The
+// constant 0.015 is chosen so that roughly 70 to 80 percent of the values
+// fall between -100 and +100.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:commodity_channel_index_cci
+//  https://www.investopedia.com/terms/c/commoditychannelindex.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/cci
+type Cci struct {
+	n    int64
+	hist *CBuf
+	sum  float64
+}
+
+func NewCci(n int64) *Cci {
+	return &Cci{
+		n:    n,
+		hist: NewCBuf(n),
+		sum:  0,
+	}
+}
+
+func (r *Cci) Update(c Candle) float64 {
+	tp := c.Typical()
+	old := r.hist.Append(tp)
+	r.sum += tp - old
+
+	if r.hist.Size() < r.n {
+		return 0
+	}
+
+	mean := r.sum / float64(r.n)
+	dev := float64(0)
+	r.hist.Iter(func(v float64) {
+		dev += math.Abs(v - mean)
+	})
+
+	diff := tp - mean
+	if almostZero(diff) || almostZero(dev) {
+		return 0
+	}
+
+	return diff / (0.015 * dev / float64(r.n))
+}
+
+func (r *Cci) InitPeriod() int64 {
+	return r.n - 1
+}
+
+func (r *Cci) Valid() bool {
+	return r.hist.Size() > r.InitPeriod()
+}
+
+func (r *Cci) state(s *stateCodec) {
+	s.param(r.n)
+	s.sub(r.hist)
+	s.float(&r.sum)
+}
+
+// Developed by Donald Lambert and featured in Commodities magazine in 1980,
+// the Commodity Channel Index (CCI) is a versatile indicator that can be
+// used to identify a new trend or warn of extreme conditions. CCI measures
+// the current price level relative to an average price level over a given
+// period of time. CCI is relatively high when prices are far above their
+// average and relatively low when prices are far below their average. The
+// constant 0.015 is chosen so that roughly 70 to 80 percent of the values
+// fall between -100 and +100.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:commodity_channel_index_cci
+//  https://www.investopedia.com/terms/c/commoditychannelindex.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/cci
+func CciArr(in []Candle, n int64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	r := NewCci(n)
+	for i, v := range in {
+		out[i] = r.Update(v)
+	}
+	warmup(mode, r.InitPeriod(), out)
+
+	return out
+}
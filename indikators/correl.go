@@ -0,0 +1,96 @@
+package indikators
+
+import (
+	"math"
+)
+
+// Pearson's Correlation Coefficient measures the strength of the linear
+// relationship between two series over the last n values. It ranges from
+// +1, both series move together, to -1, they move in opposite directions.
+// Readings near 0 mean there is no linear relationship. Correlated pairs
+// are the candidates for pair trading, the spread being sized with Beta.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:correlation_coeffici
+//  https://www.investopedia.com/terms/c/correlationcoefficient.asp
+type Correl struct {
+	n     int64
+	x     *CBuf
+	y     *CBuf
+	sumX  float64
+	sumY  float64
+	sumX2 float64
+	sumY2 float64
+	sumXY float64
+	sz    int64
+}
+
+func NewCorrel(n int64) *Correl {
+	return &Correl{
+		n:  n,
+		x:  NewCBuf(n),
+		y:  NewCBuf(n),
+		sz: 0,
+	}
+}
+
+func (c *Correl) Update(x, y float64) float64 {
+	c.sz++
+
+	oldX := c.x.Append(x)
+	oldY := c.y.Append(y)
+	c.sumX += x - oldX
+	c.sumY += y - oldY
+	c.sumX2 += x*x - oldX*oldX
+	c.sumY2 += y*y - oldY*oldY
+	c.sumXY += x*y - oldX*oldY
+
+	if c.sz < c.n {
+		return 0
+	}
+
+	n := float64(c.n)
+	d := (c.sumX2 - c.sumX*c.sumX/n) * (c.sumY2 - c.sumY*c.sumY/n)
+	if d < 0.00000001 {
+		return 0
+	}
+
+	return (c.sumXY - c.sumX*c.sumY/n) / math.Sqrt(d)
+}
+
+func (c *Correl) InitPeriod() int64 {
+	return c.n - 1
+}
+
+func (c *Correl) Valid() bool {
+	return c.sz > c.InitPeriod()
+}
+
+func (c *Correl) state(s *stateCodec) {
+	s.param(c.n)
+	s.sub(c.x)
+	s.sub(c.y)
+	s.float(&c.sumX)
+	s.float(&c.sumY)
+	s.float(&c.sumX2)
+	s.float(&c.sumY2)
+	s.float(&c.sumXY)
+	s.int(&c.sz)
+}
+
+// Pearson's Correlation Coefficient measures the strength of the linear
+// relationship between two series over the last n values. It ranges from
+// +1, both series move together, to -1, they move in opposite directions.
+// Readings near 0 mean there is no linear relationship. Correlated pairs
+// are the candidates for pair trading, the spread being sized with Beta.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:correlation_coeffici
+//  https://www.investopedia.com/terms/c/correlationcoefficient.asp
+func CorrelArr(x, y []float64, n int64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(x))
+
+	c := NewCorrel(n)
+	for i := range x {
+		out[i] = c.Update(x[i], y[i])
+	}
+	warmup(mode, c.InitPeriod(), out)
+
+	return out
+}
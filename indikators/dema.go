@@ -51,6 +51,13 @@ func (d *Dema) Valid() bool {
 	return d.sz > d.InitPeriod()
 }
 
+func (d *Dema) state(s *stateCodec) {
+	s.param(d.n)
+	s.sub(d.ema1)
+	s.sub(d.ema2)
+	s.int(&d.sz)
+}
+
 // The Double Exponential Moving Average (DEMA) reduces the lag
 // of traditional EMAs, making it more responsive and better-suited
 // for short-term traders. DEMA was developed by Patrick Mulloy,
@@ -63,7 +70,7 @@ func (d *Dema) Valid() bool {
 // traditional EMA.
 //  https://school.stockcharts.com/doku.php?id=technical_indicators:dema
 //  https://www.investopedia.com/terms/d/double-exponential-moving-average.asp
-func DemaArr(in []float64, n int64) []float64 {
+func DemaArr(in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	k := 2.0 / float64(n+1)
@@ -71,6 +78,7 @@ func DemaArr(in []float64, n int64) []float64 {
 	for i, v := range in {
 		out[i] = d.Update(v)
 	}
+	warmup(mode, d.InitPeriod(), out)
 
 	return out
 }
@@ -0,0 +1,214 @@
+package indikators
+
+import (
+	"fmt"
+	"math"
+)
+
+// DivergenceKind tells which way price and oscillator disagree
+type DivergenceKind int
+
+const (
+	// price makes a lower low, the oscillator a higher low
+	RegularBullish DivergenceKind = iota
+	// price makes a higher high, the oscillator a lower high
+	RegularBearish
+	// price makes a higher low, the oscillator a lower low
+	HiddenBullish
+	// price makes a lower high, the oscillator a higher high
+	HiddenBearish
+)
+
+var divergenceNames = []string{"regular bullish", "regular bearish", "hidden bullish", "hidden bearish"}
+
+func (k DivergenceKind) String() string {
+	if k < 0 || int(k) >= len(divergenceNames) {
+		return fmt.Sprintf("DivergenceKind(%d)", int(k))
+	}
+	return divergenceNames[k]
+}
+
+func (k DivergenceKind) Bullish() bool {
+	return k == RegularBullish || k == HiddenBullish
+}
+
+// Divergence between two consecutive price swings and the oscillator
+// swings matched to them
+type Divergence struct {
+	Kind      DivergenceKind
+	PriceFrom Swing
+	PriceTo   Swing
+	OscFrom   Swing
+	OscTo     Swing
+	// bar at which all four swings are confirmed
+	Confirmed int
+}
+
+func (d Divergence) String() string {
+	return fmt.Sprintf("%s divergence: price %g@%d -> %g@%d, oscillator %g@%d -> %g@%d",
+		d.Kind,
+		d.PriceFrom.Value, d.PriceFrom.Index, d.PriceTo.Value, d.PriceTo.Index,
+		d.OscFrom.Value, d.OscFrom.Index, d.OscTo.Value, d.OscTo.Index)
+}
+
+// DivergenceSettings configures Divergences, see DefaultDivergenceSettings
+type DivergenceSettings struct {
+	PriceSwings SwingFinder
+	OscSwings   SwingFinder
+	// bars allowed between two price swings
+	MinSpan int
+	MaxSpan int
+	// bars an oscillator swing may be away from its price swing
+	Tolerance int
+	// leading bars ignored, usually the InitPeriod of the oscillator
+	// computed with WarmupZero
+	Skip int
+	// report regular and/or hidden divergences
+	Regular bool
+	Hidden  bool
+}
+
+// Defaults of the TradingView divergence indicator: 5 bar fractals on
+// both series, swings 5 to 60 bars apart
+func DefaultDivergenceSettings() DivergenceSettings {
+	return DivergenceSettings{
+		PriceSwings: Fractal(5, 5),
+		OscSwings:   Fractal(5, 5),
+		MinSpan:     5,
+		MaxSpan:     60,
+		Tolerance:   3,
+		Skip:        0,
+		Regular:     true,
+		Hidden:      true,
+	}
+}
+
+// Divergences finds the divergences between price and an oscillator
+// (Rsi, the MACD histogram, Stoch...) computed on the same bars. Highs
+// are compared on high and lows on low, pass the closes twice to work on
+// closing prices. Every pair of consecutive price swing highs (lows) is
+// matched with the oscillator swing highs (lows) nearest to them; when
+// price and oscillator move in opposite directions the pair is reported.
+// The result is ordered by Confirmed.
+//  https://www.investopedia.com/terms/d/divergence.asp
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:macd-histogram
+func Divergences(high, low, osc []float64, s DivergenceSettings) []Divergence {
+	var out []Divergence
+
+	price := s.PriceSwings(high, low)
+	oscSwings := s.OscSwings(osc, osc)
+
+	for _, isHigh := range []bool{true, false} {
+		var prev *Swing
+		for i := range price {
+			cur := price[i]
+			if cur.High != isHigh || cur.Index < s.Skip {
+				continue
+			}
+			from := prev
+			prev = &price[i]
+			if from == nil {
+				continue
+			}
+
+			span := cur.Index - from.Index
+			if span < s.MinSpan || span > s.MaxSpan {
+				continue
+			}
+
+			oFrom, ok := nearestSwing(oscSwings, from.Index, isHigh, s)
+			if !ok {
+				continue
+			}
+			oTo, ok := nearestSwing(oscSwings, cur.Index, isHigh, s)
+			if !ok || oTo.Index <= oFrom.Index {
+				continue
+			}
+
+			kind, ok := divergenceKind(isHigh, from.Value, cur.Value, oFrom.Value, oTo.Value)
+			if !ok {
+				continue
+			}
+			if kind == RegularBullish || kind == RegularBearish {
+				if !s.Regular {
+					continue
+				}
+			} else if !s.Hidden {
+				continue
+			}
+
+			confirmed := cur.Confirmed
+			if oTo.Confirmed > confirmed {
+				confirmed = oTo.Confirmed
+			}
+			out = append(out, Divergence{
+				Kind:      kind,
+				PriceFrom: *from,
+				PriceTo:   cur,
+				OscFrom:   oFrom,
+				OscTo:     oTo,
+				Confirmed: confirmed,
+			})
+		}
+	}
+
+	// merge highs and lows
+	for i := 1; i < len(out); i++ {
+		for j := i; j > 0 && out[j].Confirmed < out[j-1].Confirmed; j-- {
+			out[j], out[j-1] = out[j-1], out[j]
+		}
+	}
+
+	return out
+}
+
+// CandleDivergences is Divergences on the high and low of candles
+func CandleDivergences(in []Candle, osc []float64, s DivergenceSettings) []Divergence {
+	high := make([]float64, len(in))
+	low := make([]float64, len(in))
+	for i, c := range in {
+		high[i] = c.High
+		low[i] = c.Low
+	}
+	return Divergences(high, low, osc, s)
+}
+
+// oscillator swing of the same side closest to bar idx
+func nearestSwing(swings []Swing, idx int, isHigh bool, s DivergenceSettings) (Swing, bool) {
+	best, found := Swing{}, false
+	for _, sw := range swings {
+		if sw.High != isHigh || sw.Index < s.Skip {
+			continue
+		}
+		d := abs(sw.Index - idx)
+		if d > s.Tolerance {
+			continue
+		}
+		if !found || d < abs(best.Index-idx) {
+			best, found = sw, true
+		}
+	}
+	return best, found
+}
+
+func divergenceKind(isHigh bool, p1, p2, o1, o2 float64) (DivergenceKind, bool) {
+	if math.IsNaN(o1) || math.IsNaN(o2) {
+		return 0, false
+	}
+	if isHigh {
+		switch {
+		case p2 > p1 && o2 < o1:
+			return RegularBearish, true
+		case p2 < p1 && o2 > o1:
+			return HiddenBearish, true
+		}
+	} else {
+		switch {
+		case p2 < p1 && o2 > o1:
+			return RegularBullish, true
+		case p2 > p1 && o2 < o1:
+			return HiddenBullish, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,147 @@
+package indikators_test
+
+import (
+	"testing"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwings(t *testing.T) {
+	tests := []struct {
+		name                string
+		in                  []float64
+		finder              indikators.SwingFinder
+		funcUseCaseShouldBe func(t *testing.T, out []indikators.Swing)
+	}{
+		{
+			name:   "Fractal swings need a strict extreme on the left",
+			in:     []float64{1, 3, 2, 5, 1, 4, 2},
+			finder: indikators.Fractal(1, 1),
+			funcUseCaseShouldBe: func(t *testing.T, out []indikators.Swing) {
+				assert.Equal(t, []indikators.Swing{
+					{Index: 1, Value: 3, High: true, Confirmed: 2},
+					{Index: 2, Value: 2, High: false, Confirmed: 3},
+					{Index: 3, Value: 5, High: true, Confirmed: 4},
+					{Index: 4, Value: 1, High: false, Confirmed: 5},
+					{Index: 5, Value: 4, High: true, Confirmed: 6},
+				}, out, "they should be equal")
+			},
+		},
+		{
+			name:   "ZigZag swings alternate and ignore small reversals",
+			in:     []float64{10, 12, 11, 15, 13.4, 16, 12, 14},
+			finder: indikators.ZigZag(10),
+			funcUseCaseShouldBe: func(t *testing.T, out []indikators.Swing) {
+				assert.Equal(t, []indikators.Swing{
+					{Index: 0, Value: 10, High: false, Confirmed: 1},
+					{Index: 3, Value: 15, High: true, Confirmed: 4},
+					{Index: 4, Value: 13.4, High: false, Confirmed: 5},
+					{Index: 5, Value: 16, High: true, Confirmed: 6},
+					{Index: 6, Value: 12, High: false, Confirmed: 7},
+				}, out, "they should be equal")
+			},
+		},
+		{
+			name:   "ZigZagAbs uses absolute reversals",
+			in:     []float64{-1, 1, 0.5, 2, -1},
+			finder: indikators.ZigZagAbs(1.5),
+			funcUseCaseShouldBe: func(t *testing.T, out []indikators.Swing) {
+				assert.Equal(t, []indikators.Swing{
+					{Index: 0, Value: -1, High: false, Confirmed: 1},
+					{Index: 3, Value: 2, High: true, Confirmed: 4},
+				}, out, "they should be equal")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.funcUseCaseShouldBe(t, test.finder(test.in, test.in))
+		})
+	}
+}
+
+func TestDivergences(t *testing.T) {
+	settings := func(regular, hidden bool) indikators.DivergenceSettings {
+		s := indikators.DefaultDivergenceSettings()
+		s.PriceSwings = indikators.Fractal(1, 1)
+		s.OscSwings = indikators.Fractal(1, 1)
+		s.MinSpan = 1
+		s.MaxSpan = 10
+		s.Tolerance = 1
+		s.Regular = regular
+		s.Hidden = hidden
+		return s
+	}
+
+	tests := []struct {
+		name                string
+		price               []float64
+		osc                 []float64
+		settings            indikators.DivergenceSettings
+		funcUseCaseShouldBe func(t *testing.T, out []indikators.Divergence)
+	}{
+		{
+			name:     "Higher high in price and lower high in the oscillator is regular bearish",
+			price:    []float64{1, 5, 2, 3, 6, 2},
+			osc:      []float64{1, 8, 2, 3, 7, 2},
+			settings: settings(true, true),
+			funcUseCaseShouldBe: func(t *testing.T, out []indikators.Divergence) {
+				assert.Len(t, out, 1, "they should be equal")
+				assert.Equal(t, indikators.RegularBearish, out[0].Kind, "they should be equal")
+				assert.Equal(t, 1, out[0].PriceFrom.Index, "they should be equal")
+				assert.Equal(t, 4, out[0].PriceTo.Index, "they should be equal")
+				assert.Equal(t, 1, out[0].OscFrom.Index, "they should be equal")
+				assert.Equal(t, 4, out[0].OscTo.Index, "they should be equal")
+				assert.Equal(t, 5, out[0].Confirmed, "they should be equal")
+				assert.False(t, out[0].Kind.Bullish(), "it should be false")
+			},
+		},
+		{
+			name:     "Lower low in price and higher low in the oscillator is regular bullish",
+			price:    []float64{5, 2, 4, 3, 1, 4},
+			osc:      []float64{5, 2, 4, 3.5, 3, 4},
+			settings: settings(true, true),
+			funcUseCaseShouldBe: func(t *testing.T, out []indikators.Divergence) {
+				assert.Len(t, out, 1, "they should be equal")
+				assert.Equal(t, indikators.RegularBullish, out[0].Kind, "they should be equal")
+				assert.Equal(t, "regular bullish divergence: price 2@1 -> 1@4, oscillator 2@1 -> 3@4", out[0].String(), "they should be equal")
+			},
+		},
+		{
+			name:     "Lower high in price and higher high in the oscillator is hidden bearish",
+			price:    []float64{1, 6, 2, 3, 5, 2},
+			osc:      []float64{1, 7, 2, 3, 8, 2},
+			settings: settings(true, true),
+			funcUseCaseShouldBe: func(t *testing.T, out []indikators.Divergence) {
+				assert.Len(t, out, 1, "they should be equal")
+				assert.Equal(t, indikators.HiddenBearish, out[0].Kind, "they should be equal")
+			},
+		},
+		{
+			name:     "Hidden divergences are dropped when disabled",
+			price:    []float64{1, 6, 2, 3, 5, 2},
+			osc:      []float64{1, 7, 2, 3, 8, 2},
+			settings: settings(true, false),
+			funcUseCaseShouldBe: func(t *testing.T, out []indikators.Divergence) {
+				assert.Empty(t, out, "it should be empty")
+			},
+		},
+		{
+			name:     "Oscillator swings too far from the price swings are not matched",
+			price:    []float64{1, 5, 2, 3, 6, 2, 1, 0, 1},
+			osc:      []float64{1, 8, 2, 2, 2, 1, 7, 2, 1},
+			settings: settings(true, true),
+			funcUseCaseShouldBe: func(t *testing.T, out []indikators.Divergence) {
+				assert.Empty(t, out, "it should be empty")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.funcUseCaseShouldBe(t, indikators.Divergences(test.price, test.price, test.osc, test.settings))
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package indikators
+
+// The Directional Movement Index (DMI) was developed by J. Welles Wilder
+// to identify the direction of price movement. The plus directional
+// indicator (+DI) measures the strength of upward moves and the minus
+// directional indicator (-DI) measures the strength of downward moves.
+// Both are derived from the smoothed directional movement divided by
+// the smoothed true range, so they are expressed as a percentage of
+// the trading range. A bullish signal occurs when +DI crosses above
+// -DI and a bearish one when -DI crosses above +DI.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:average_directional_index_adx
+//  https://www.investopedia.com/terms/d/dmi.asp
+type Dmi struct {
+	n      int64
+	trange *TRange
+	prevH  float64
+	prevL  float64
+	pdm    float64
+	mdm    float64
+	tr     float64
+	sz     int64
+}
+
+func NewDmi(n int64) *Dmi {
+	return &Dmi{
+		n:      n,
+		trange: NewTRange(),
+		prevH:  0,
+		prevL:  0,
+		pdm:    0,
+		mdm:    0,
+		tr:     0,
+		sz:     0,
+	}
+}
+
+// +DI, -DI
+func (d *Dmi) Update(c Candle) (float64, float64) {
+	d.sz++
+
+	tr := d.trange.Update(c)
+	prevH, prevL := d.prevH, d.prevL
+	d.prevH, d.prevL = c.High, c.Low
+
+	if d.sz == 1 {
+		return 0, 0
+	}
+
+	diffP := c.High - prevH
+	diffM := prevL - c.Low
+	var pdm, mdm float64
+	if diffP > 0 && diffP > diffM {
+		pdm = diffP
+	} else if diffM > 0 && diffM > diffP {
+		mdm = diffM
+	}
+
+	if d.sz <= d.n {
+		// Wilder's smoothing is seeded by the plain sum of the first n-1 values
+		d.pdm += pdm
+		d.mdm += mdm
+		d.tr += tr
+		return 0, 0
+	}
+
+	n := float64(d.n)
+	d.pdm = d.pdm - d.pdm/n + pdm
+	d.mdm = d.mdm - d.mdm/n + mdm
+	d.tr = d.tr - d.tr/n + tr
+
+	if almostZero(d.tr) {
+		return 0, 0
+	}
+
+	return d.pdm / d.tr * 100.0, d.mdm / d.tr * 100.0
+}
+
+func (d *Dmi) InitPeriod() int64 {
+	return d.n
+}
+
+func (d *Dmi) Valid() bool {
+	return d.sz > d.InitPeriod()
+}
+
+func (d *Dmi) state(s *stateCodec) {
+	s.param(d.n)
+	s.sub(d.trange)
+	s.float(&d.prevH)
+	s.float(&d.prevL)
+	s.float(&d.pdm)
+	s.float(&d.mdm)
+	s.float(&d.tr)
+	s.int(&d.sz)
+}
+
+// The Directional Movement Index (DMI) was developed by J. Welles Wilder
+// to identify the direction of price movement. The plus directional
+// indicator (+DI) measures the strength of upward moves and the minus
+// directional indicator (-DI) measures the strength of downward moves.
+// Both are derived from the smoothed directional movement divided by
+// the smoothed true range, so they are expressed as a percentage of
+// the trading range. A bullish signal occurs when +DI crosses above
+// -DI and a bearish one when -DI crosses above +DI.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:average_directional_index_adx
+//  https://www.investopedia.com/terms/d/dmi.asp
+func DmiArr(in []Candle, n int64, mode ...WarmupMode) ([]float64, []float64) {
+	pdi := make([]float64, len(in))
+	mdi := make([]float64, len(in))
+
+	d := NewDmi(n)
+	for i, v := range in {
+		pdi[i], mdi[i] = d.Update(v)
+	}
+	warmup(mode, d.InitPeriod(), pdi, mdi)
+
+	return pdi, mdi
+}
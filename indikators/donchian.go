@@ -0,0 +1,76 @@
+package indikators
+
+// Developed by Richard Donchian, Donchian Channels are formed by taking
+// the highest high and the lowest low of the last n periods. The area
+// between the high and the low is the channel for the period chosen, the
+// middle line is the average of both. Breakouts above the upper channel
+// were the entry signal of the famous Turtle Traders, and the channel
+// width is a simple measure of volatility.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:price_channels
+//  https://www.investopedia.com/terms/d/donchianchannels.asp
+type Donchian struct {
+	n  int64
+	hi *Rolling
+	lo *Rolling
+	sz int64
+}
+
+func NewDonchian(n int64) *Donchian {
+	return &Donchian{
+		n:  n,
+		hi: NewRollingMax(n),
+		lo: NewRollingMin(n),
+		sz: 0,
+	}
+}
+
+// upper, middle, lower
+func (d *Donchian) Update(c Candle) (float64, float64, float64) {
+	d.sz++
+
+	hh := d.hi.Update(c.High)
+	ll := d.lo.Update(c.Low)
+
+	if d.sz < d.n {
+		return 0, 0, 0
+	}
+
+	return hh, (hh + ll) / 2.0, ll
+}
+
+func (d *Donchian) InitPeriod() int64 {
+	return d.n - 1
+}
+
+func (d *Donchian) Valid() bool {
+	return d.sz > d.InitPeriod()
+}
+
+func (d *Donchian) state(s *stateCodec) {
+	s.param(d.n)
+	s.sub(d.hi)
+	s.sub(d.lo)
+	s.int(&d.sz)
+}
+
+// Developed by Richard Donchian, Donchian Channels are formed by taking
+// the highest high and the lowest low of the last n periods. The area
+// between the high and the low is the channel for the period chosen, the
+// middle line is the average of both. Breakouts above the upper channel
+// were the entry signal of the famous Turtle Traders, and the channel
+// width is a simple measure of volatility.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:price_channels
+//  https://www.investopedia.com/terms/d/donchianchannels.asp
+func DonchianArr(in []Candle, n int64, mode ...WarmupMode) ([]float64, []float64, []float64) {
+	u := make([]float64, len(in))
+	m := make([]float64, len(in))
+	l := make([]float64, len(in))
+
+	d := NewDonchian(n)
+	for i, v := range in {
+		u[i], m[i], l[i] = d.Update(v)
+	}
+	warmup(mode, d.InitPeriod(), u, m, l)
+
+	return u, m, l
+}
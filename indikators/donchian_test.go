@@ -0,0 +1,35 @@
+package indikators_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+// highest high and lowest low of the n bars ending at i
+func channel(in []indikators.Candle, i, n int) (float64, float64) {
+	hh, ll := math.Inf(-1), math.Inf(1)
+	for _, c := range in[i-n+1 : i+1] {
+		hh = math.Max(hh, c.High)
+		ll = math.Min(ll, c.Low)
+	}
+	return hh, ll
+}
+
+func TestDonchian(t *testing.T) {
+	in := readOHLCV(t)
+
+	for _, n := range []int{1, 2, 20, 55} {
+		u, m, l := indikators.DonchianArr(in, int64(n), indikators.WarmupNaN)
+		for i := range in {
+			if i < n-1 {
+				assert.True(t, math.IsNaN(u[i]) && math.IsNaN(m[i]) && math.IsNaN(l[i]), "n=%d bar %d should be warming up", n, i)
+				continue
+			}
+			hh, ll := channel(in, i, n)
+			assert.Equal(t, []float64{hh, (hh + ll) / 2, ll}, []float64{u[i], m[i], l[i]}, "n=%d bar %d should be equal", n, i)
+		}
+	}
+}
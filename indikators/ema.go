@@ -41,8 +41,15 @@ func (e *Ema) Valid() bool {
 	return e.sz > e.InitPeriod()
 }
 
+func (e *Ema) state(s *stateCodec) {
+	s.param(e.n)
+	s.paramFloat(e.k1)
+	s.int(&e.sz)
+	s.float(&e.ma)
+}
+
 // Exponential moving averages (EMAs) reduce the lag by
-func EmaArr(in []float64, n int64) []float64 {
+func EmaArr(in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	k := 2.0 / float64(n+1)
@@ -50,6 +57,7 @@ func EmaArr(in []float64, n int64) []float64 {
 	for i, v := range in {
 		out[i] = e.Update(v)
 	}
+	warmup(mode, e.InitPeriod(), out)
 
 	return out
 }
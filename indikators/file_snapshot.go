@@ -0,0 +1,53 @@
+package indikators
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// FileSnapshotStore keeps every snapshot in its own file inside dir
+type FileSnapshotStore struct {
+	dir string
+}
+
+func NewFileSnapshotStore(dir string) (*FileSnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileSnapshotStore{
+		dir: dir,
+	}, nil
+}
+
+func (f *FileSnapshotStore) Save(ctx context.Context, key string, data []byte) error {
+	// write aside and rename, a crash never leaves a truncated snapshot
+	tmp, err := os.CreateTemp(f.dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(key))
+}
+
+func (f *FileSnapshotStore) Load(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoSnapshot
+	}
+	return data, err
+}
+
+func (f *FileSnapshotStore) path(key string) string {
+	return filepath.Join(f.dir, url.PathEscape(key)+".snapshot")
+}
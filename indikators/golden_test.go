@@ -0,0 +1,194 @@
+package indikators_test
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+// golden files are generated by testdata/gen_golden from go-talib, the
+// TA-Lib port pinned in its go.mod
+func readCSV(t *testing.T, name string) [][]float64 {
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := make([][]float64, 0, len(records))
+	for _, record := range records {
+		row := make([]float64, len(record))
+		for i, s := range record {
+			if row[i], err = strconv.ParseFloat(s, 64); err != nil {
+				t.Fatal(err)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func readOHLCV(t *testing.T) []indikators.Candle {
+	return readCandles(t, "ohlcv.csv")
+}
+
+func readCandles(t *testing.T, name string) []indikators.Candle {
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := make([]indikators.Candle, 0, len(records))
+	for _, record := range records[1:] {
+		var v [5]float64
+		for i := range v {
+			if v[i], err = strconv.ParseFloat(record[i], 64); err != nil {
+				t.Fatal(err)
+			}
+		}
+		out = append(out, indikators.Candle{Open: v[0], High: v[1], Low: v[2], Close: v[3], Volume: v[4]})
+	}
+	return out
+}
+
+func series(in []indikators.Candle, src indikators.Source) []float64 {
+	out := make([]float64, len(in))
+	for i, c := range in {
+		out[i] = src.Value(c)
+	}
+	return out
+}
+
+func TestGolden(t *testing.T) {
+	in := readOHLCV(t)
+	closes := indikators.Closes(in)
+
+	tests := []struct {
+		name string
+		fn   func() [][]float64
+	}{
+		{"sma_30", func() [][]float64 { return [][]float64{indikators.SmaArr(closes, 30, indikators.WarmupNaN)} }},
+		{"ema_30", func() [][]float64 { return [][]float64{indikators.EmaArr(closes, 30, indikators.WarmupNaN)} }},
+		{"wma_30", func() [][]float64 { return [][]float64{indikators.WmaArr(closes, 30, indikators.WarmupNaN)} }},
+		{"dema_30", func() [][]float64 { return [][]float64{indikators.DemaArr(closes, 30, indikators.WarmupNaN)} }},
+		{"tema_30", func() [][]float64 { return [][]float64{indikators.TemaArr(closes, 30, indikators.WarmupNaN)} }},
+		{"trima_30", func() [][]float64 { return [][]float64{indikators.TrimaArr(closes, 30, indikators.WarmupNaN)} }},
+		{"trima_31", func() [][]float64 { return [][]float64{indikators.TrimaArr(closes, 31, indikators.WarmupNaN)} }},
+		{"kama_30", func() [][]float64 { return [][]float64{indikators.KamaArr(closes, 30, indikators.WarmupNaN)} }},
+		{"t3_5", func() [][]float64 { return [][]float64{indikators.T3MaArr(closes, 5, 0.7, indikators.WarmupNaN)} }},
+		{"mama", func() [][]float64 {
+			m, f := indikators.MamaArr(closes, 0.5, 0.05, indikators.WarmupNaN)
+			return [][]float64{m, f}
+		}},
+		{"rsi_14", func() [][]float64 { return [][]float64{indikators.RsiArr(closes, 14, indikators.WarmupNaN)} }},
+		{"var_5", func() [][]float64 { return [][]float64{indikators.VarArr(closes, 5, indikators.WarmupNaN)} }},
+		{"stddev_5", func() [][]float64 { return [][]float64{indikators.StdDevArr(closes, 5, indikators.WarmupNaN)} }},
+		{"bbands_20", func() [][]float64 {
+			u, m, l := indikators.BBandsArr(indikators.SMA, closes, 20, 2, 2, indikators.WarmupNaN)
+			return [][]float64{u, m, l}
+		}},
+		{"bbands_20_up25_dn15", func() [][]float64 {
+			u, m, l := indikators.BBandsArr(indikators.SMA, closes, 20, 2.5, 1.5, indikators.WarmupNaN)
+			return [][]float64{u, m, l}
+		}},
+		{"percentb_20", func() [][]float64 {
+			return [][]float64{indikators.PercentBArr(indikators.SMA, closes, 20, 2, 2, indikators.WarmupNaN)}
+		}},
+		{"squeeze_20_30", func() [][]float64 {
+			bw, sq := indikators.SqueezeArr(indikators.SMA, closes, 20, 2, 2, 30, indikators.WarmupNaN)
+			flags := make([]float64, len(sq))
+			for i, v := range sq {
+				if v {
+					flags[i] = 1
+				}
+			}
+			return [][]float64{bw, flags}
+		}},
+		{"macd_12_26_9", func() [][]float64 {
+			m, s, h := indikators.MacdArr(closes, 12, 26, 9, indikators.WarmupNaN)
+			return [][]float64{m, s, h}
+		}},
+		{"macdext_sma_12_26_9", func() [][]float64 {
+			m, s, h := indikators.MacdExtArr(closes, indikators.SMA, 12, indikators.SMA, 26, indikators.SMA, 9, indikators.WarmupNaN)
+			return [][]float64{m, s, h}
+		}},
+		{"stoch_5_3_3", func() [][]float64 {
+			k, d := indikators.StochArr(in, 5, indikators.SMA, 3, indikators.SMA, 3, indikators.WarmupNaN)
+			return [][]float64{k, d}
+		}},
+		{"stochrsi_14_5_3", func() [][]float64 {
+			k, d := indikators.StochRsiArr(closes, 14, 5, indikators.SMA, 3, indikators.WarmupNaN)
+			return [][]float64{k, d}
+		}},
+		{"linreg_14", func() [][]float64 {
+			reg, slope, intercept, angle, forecast, r2 := indikators.LinRegArr(closes, 14, indikators.WarmupNaN)
+			return [][]float64{reg, slope, intercept, angle, forecast, r2}
+		}},
+		{"correl_30", func() [][]float64 {
+			return [][]float64{indikators.CorrelArr(series(in, indikators.High), series(in, indikators.Low), 30, indikators.WarmupNaN)}
+		}},
+		{"beta_5", func() [][]float64 {
+			return [][]float64{indikators.BetaArr(series(in, indikators.High), series(in, indikators.Low), 5, indikators.WarmupNaN)}
+		}},
+		{"zscore_20", func() [][]float64 { return [][]float64{indikators.ZScoreArr(closes, 20, indikators.WarmupNaN)} }},
+		{"trange", func() [][]float64 { return [][]float64{indikators.TRangeArr(in, indikators.WarmupNaN)} }},
+		{"atr_14", func() [][]float64 { return [][]float64{indikators.AtrArr(in, 14, indikators.WarmupNaN)} }},
+		{"dmi_14", func() [][]float64 {
+			p, m := indikators.DmiArr(in, 14, indikators.WarmupNaN)
+			return [][]float64{p, m}
+		}},
+		{"adx_14", func() [][]float64 { return [][]float64{indikators.AdxArr(in, 14, indikators.WarmupNaN)} }},
+		{"cci_14", func() [][]float64 { return [][]float64{indikators.CciArr(in, 14, indikators.WarmupNaN)} }},
+		{"willr_14", func() [][]float64 { return [][]float64{indikators.WillRArr(in, 14, indikators.WarmupNaN)} }},
+		{"obv", func() [][]float64 { return [][]float64{indikators.ObvArr(in, indikators.WarmupNaN)} }},
+		{"ad", func() [][]float64 { return [][]float64{indikators.AdArr(in, indikators.WarmupNaN)} }},
+		{"adosc_3_10", func() [][]float64 { return [][]float64{indikators.AdOscArr(in, 3, 10, indikators.WarmupNaN)} }},
+		{"mfi_14", func() [][]float64 { return [][]float64{indikators.MfiArr(in, 14, indikators.WarmupNaN)} }},
+		{"sar", func() [][]float64 { return [][]float64{indikators.SarArr(in, 0.02, 0.2, indikators.WarmupNaN)} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := readCSV(t, filepath.Join("golden", tt.name+".csv"))
+			outputs := tt.fn()
+			for o, out := range outputs {
+				assert.Equal(t, len(expected), len(out), "they should be equal")
+				for i, v := range out {
+					want := expected[i][o]
+					if math.IsNaN(want) {
+						assert.True(t, math.IsNaN(v), "output %d of bar %d should be NaN, got %v", o, i, v)
+						continue
+					}
+					assert.InDelta(t, want, v, 1e-9*math.Max(1, math.Abs(want)), "output %d of bar %d", o, i)
+				}
+			}
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	beg, out := indikators.Trim(indikators.SmaArr([]float64{1, 2, 3, 4, 5}, 3, indikators.WarmupNaN))
+	assert.Equal(t, 2, beg, "they should be equal")
+	assert.Equal(t, []float64{2, 3, 4}, out, "they should be equal")
+
+	beg, out = indikators.Trim(indikators.SmaArr([]float64{1, 2}, 3, indikators.WarmupNaN))
+	assert.Equal(t, 2, beg, "they should be equal")
+	assert.Empty(t, out, "it should be empty")
+}
@@ -0,0 +1,187 @@
+package indikators
+
+import "math"
+
+// Ichimoku Kinko Hyo (Ichimoku Cloud) is a collection of lines that
+// define support and resistance, momentum and trend direction. The
+// Tenkan-sen (conversion line) and Kijun-sen (base line) are the
+// midpoints of the highest high and lowest low over tenkanN and kijunN
+// periods. Senkou Span A is the average of both and Senkou Span B the
+// midpoint over senkouN periods, both plotted disp periods ahead to form
+// the cloud (kumo). The Chikou Span is the close plotted disp periods
+// behind. Update returns the spans that apply to the current bar, those
+// computed disp bars ago, and the current close as Chikou Span, which
+// belongs to the bar disp periods back. Use Leading for the spans
+// projected ahead of the current bar. A disp of 0 plots the spans on the
+// bar they are computed on.
+//
+//	https://school.stockcharts.com/doku.php?id=technical_indicators:ichimoku_cloud
+//	https://www.investopedia.com/terms/i/ichimoku-cloud.asp
+type Ichimoku struct {
+	tenkanN  int64
+	kijunN   int64
+	senkouN  int64
+	disp     int64
+	tenkanHi *Rolling
+	tenkanLo *Rolling
+	kijunHi  *Rolling
+	kijunLo  *Rolling
+	senkouHi *Rolling
+	senkouLo *Rolling
+	// nil when disp is 0
+	spanA *CBuf
+	spanB *CBuf
+	leadA float64
+	leadB float64
+	sz    int64
+}
+
+func NewIchimoku(tenkanN, kijunN, senkouN, disp int64) *Ichimoku {
+	if disp < 0 {
+		disp = 0
+	}
+	ich := &Ichimoku{
+		tenkanN:  tenkanN,
+		kijunN:   kijunN,
+		senkouN:  senkouN,
+		disp:     disp,
+		tenkanHi: NewRollingMax(tenkanN),
+		tenkanLo: NewRollingMin(tenkanN),
+		kijunHi:  NewRollingMax(kijunN),
+		kijunLo:  NewRollingMin(kijunN),
+		senkouHi: NewRollingMax(senkouN),
+		senkouLo: NewRollingMin(senkouN),
+		sz:       0,
+	}
+	if disp > 0 {
+		ich.spanA = NewCBuf(disp)
+		ich.spanB = NewCBuf(disp)
+	}
+	return ich
+}
+
+// tenkan, kijun, senkou A, senkou B, chikou
+func (ich *Ichimoku) Update(c Candle) (float64, float64, float64, float64, float64) {
+	ich.sz++
+
+	tenkan := midpoint(ich.tenkanHi, ich.tenkanLo, c)
+	kijun := midpoint(ich.kijunHi, ich.kijunLo, c)
+	senkou := midpoint(ich.senkouHi, ich.senkouLo, c)
+
+	ich.leadA = 0
+	if ich.sz >= ich.tenkanN && ich.sz >= ich.kijunN {
+		ich.leadA = (tenkan + kijun) / 2.0
+	}
+	ich.leadB = senkou
+
+	if ich.disp == 0 {
+		return tenkan, kijun, ich.leadA, ich.leadB, c.Close
+	}
+	a := ich.spanA.Append(ich.leadA)
+	b := ich.spanB.Append(ich.leadB)
+
+	return tenkan, kijun, a, b, c.Close
+}
+
+// Leading returns the senkou spans computed on the latest bar, which are
+// plotted disp periods ahead of it
+func (ich *Ichimoku) Leading() (float64, float64) {
+	return ich.leadA, ich.leadB
+}
+
+// midpoint of the highest high and lowest low of the window, 0 until the
+// window is full
+func midpoint(hi, lo *Rolling, c Candle) float64 {
+	hh := hi.Update(c.High)
+	ll := lo.Update(c.Low)
+
+	if !hi.Valid() {
+		return 0
+	}
+
+	return (hh + ll) / 2.0
+}
+
+func (ich *Ichimoku) InitPeriod() int64 {
+	n := ich.senkouN
+	if ich.tenkanN > n {
+		n = ich.tenkanN
+	}
+	if ich.kijunN > n {
+		n = ich.kijunN
+	}
+	return n - 1 + ich.disp
+}
+
+func (ich *Ichimoku) Valid() bool {
+	return ich.sz > ich.InitPeriod()
+}
+
+func (ich *Ichimoku) state(s *stateCodec) {
+	s.param(ich.tenkanN)
+	s.param(ich.kijunN)
+	s.param(ich.senkouN)
+	s.param(ich.disp)
+	s.sub(ich.tenkanHi)
+	s.sub(ich.tenkanLo)
+	s.sub(ich.kijunHi)
+	s.sub(ich.kijunLo)
+	s.sub(ich.senkouHi)
+	s.sub(ich.senkouLo)
+	if ich.disp > 0 {
+		s.sub(ich.spanA)
+		s.sub(ich.spanB)
+	}
+	s.float(&ich.leadA)
+	s.float(&ich.leadB)
+	s.int(&ich.sz)
+}
+
+// Ichimoku Kinko Hyo (Ichimoku Cloud) is a collection of lines that
+// define support and resistance, momentum and trend direction. The
+// Tenkan-sen (conversion line) and Kijun-sen (base line) are the
+// midpoints of the highest high and lowest low over tenkanN and kijunN
+// periods. Senkou Span A is the average of both and Senkou Span B the
+// midpoint over senkouN periods, both plotted disp periods ahead to form
+// the cloud (kumo). The Chikou Span is the close plotted disp periods
+// behind. All outputs are aligned to the input index they are plotted
+// at: the spans at i were computed at i-disp and the Chikou Span at i is
+// the close of i+disp, 0 for the last disp bars. disp is 0 or more.
+//
+//	https://school.stockcharts.com/doku.php?id=technical_indicators:ichimoku_cloud
+//	https://www.investopedia.com/terms/i/ichimoku-cloud.asp
+func IchimokuArr(in []Candle, tenkanN, kijunN, senkouN, disp int64, mode ...WarmupMode) ([]float64, []float64, []float64, []float64, []float64) {
+	tenkan := make([]float64, len(in))
+	kijun := make([]float64, len(in))
+	spanA := make([]float64, len(in))
+	spanB := make([]float64, len(in))
+	chikou := make([]float64, len(in))
+
+	if disp < 0 {
+		disp = 0
+	}
+	ich := NewIchimoku(tenkanN, kijunN, senkouN, disp)
+	for i, v := range in {
+		var close float64
+		tenkan[i], kijun[i], spanA[i], spanB[i], close = ich.Update(v)
+		if j := int64(i) - disp; j >= 0 {
+			chikou[j] = close
+		}
+	}
+	warmup(mode, tenkanN-1, tenkan)
+	warmup(mode, kijunN-1, kijun)
+	if tenkanN > kijunN {
+		warmup(mode, tenkanN-1+disp, spanA)
+	} else {
+		warmup(mode, kijunN-1+disp, spanA)
+	}
+	warmup(mode, senkouN-1+disp, spanB)
+	if nanWarmup(mode) {
+		// the chikou span of the last disp bars is still in the future
+		for i := len(chikou) - 1; i >= 0 && i >= len(chikou)-int(disp); i-- {
+			chikou[i] = math.NaN()
+		}
+	}
+
+	return tenkan, kijun, spanA, spanB, chikou
+}
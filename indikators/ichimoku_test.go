@@ -0,0 +1,74 @@
+package indikators_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIchimoku(t *testing.T) {
+	in := readOHLCV(t)
+	mid := func(i, n int) float64 {
+		if i < n-1 {
+			return math.NaN()
+		}
+		hh, ll := channel(in, i, n)
+		return (hh + ll) / 2
+	}
+
+	tests := []struct {
+		name                       string
+		tenkan, kijun, senkou, dsp int
+	}{
+		{name: "Default", tenkan: 9, kijun: 26, senkou: 52, dsp: 26},
+		{name: "Crypto", tenkan: 20, kijun: 60, senkou: 120, dsp: 30},
+		{name: "Tenkan longer than kijun", tenkan: 12, kijun: 6, senkou: 24, dsp: 3},
+		{name: "No displacement", tenkan: 9, kijun: 26, senkou: 52, dsp: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tenkan, kijun, spanA, spanB, chikou := indikators.IchimokuArr(in, int64(test.tenkan), int64(test.kijun), int64(test.senkou), int64(test.dsp), indikators.WarmupNaN)
+
+			for i := range in {
+				want := []float64{mid(i, test.tenkan), mid(i, test.kijun), math.NaN(), math.NaN(), math.NaN()}
+				if j := i - test.dsp; j >= 0 {
+					want[2] = (mid(j, test.tenkan) + mid(j, test.kijun)) / 2
+					want[3] = mid(j, test.senkou)
+				}
+				if j := i + test.dsp; j < len(in) {
+					want[4] = in[j].Close
+				}
+				got := []float64{tenkan[i], kijun[i], spanA[i], spanB[i], chikou[i]}
+				if !sameValues(want, got) {
+					t.Fatalf("bar %d: got %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestIchimokuLeading(t *testing.T) {
+	in := readOHLCV(t)
+	ich := indikators.NewIchimoku(9, 26, 52, 26)
+	var lead [][2]float64
+	for i, c := range in {
+		_, _, a, b, _ := ich.Update(c)
+		// the spans of bar i are the leading spans of bar i-26
+		if i >= 26 {
+			assert.Equal(t, lead[i-26], [2]float64{a, b}, "bar %d should be equal", i)
+		}
+		la, lb := ich.Leading()
+		lead = append(lead, [2]float64{la, lb})
+	}
+	assert.True(t, ich.Valid(), "it should be valid")
+
+	// a negative displacement is none
+	neg := indikators.NewIchimoku(9, 26, 52, -1)
+	for _, c := range in[:60] {
+		neg.Update(c)
+	}
+	assert.Equal(t, int64(51), neg.InitPeriod(), "they should be equal")
+}
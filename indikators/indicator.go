@@ -0,0 +1,163 @@
+package indikators
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Indicator is the common shape of every streaming indicator. Update takes
+// a whole candle so price and volume based indicators can be driven the
+// same way, indicators working on a single value read the price selected
+// by their Source. Update returns one value per entry of Outputs, e.g.
+// BBands returns upper, middle and lower.
+type Indicator interface {
+	Update(c Candle) []float64
+	InitPeriod() int64
+	Valid() bool
+	Outputs() []string
+}
+
+// PairIndicator is an Indicator computed on two series, e.g. the Correl of
+// two pairs. Update2 takes the candles of both series for the same bar,
+// the x value is read from the first and the y value from the second one.
+// Update reads both values from the same candle.
+type PairIndicator interface {
+	Indicator
+	Update2(x, y Candle) []float64
+}
+
+// Source selects the value of a candle fed to single value indicators
+type Source int
+
+const (
+	Close Source = iota
+	Open
+	High
+	Low
+	Volume
+	HL2
+	HLC3
+	OHLC4
+)
+
+var sourceNames = []string{"close", "open", "high", "low", "volume", "hl2", "hlc3", "ohlc4"}
+
+func (s Source) String() string {
+	if s < 0 || int(s) >= len(sourceNames) {
+		return fmt.Sprintf("Source(%d)", int(s))
+	}
+	return sourceNames[s]
+}
+
+// ParseSource converts a case insensitive name ("close", "hl2", ...) to Source
+func ParseSource(s string) (Source, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for i, name := range sourceNames {
+		if name == s {
+			return Source(i), nil
+		}
+	}
+	return 0, fmt.Errorf("indikators: unknown source %q", s)
+}
+
+// Value of the candle selected by s
+func (s Source) Value(c Candle) float64 {
+	switch s {
+	case Open:
+		return c.Open
+	case High:
+		return c.High
+	case Low:
+		return c.Low
+	case Volume:
+		return c.Volume
+	case HL2:
+		return c.Median()
+	case HLC3:
+		return c.Typical()
+	case OHLC4:
+		return (c.Open + c.High + c.Low + c.Close) / 4.0
+	default:
+		return c.Close
+	}
+}
+
+type periodic interface {
+	InitPeriod() int64
+	Valid() bool
+	Stateful
+}
+
+// funcIndicator adapts the concrete indicators to Indicator
+type funcIndicator struct {
+	periodic
+	outputs []string
+	update  func(c Candle) []float64
+}
+
+func (f *funcIndicator) Update(c Candle) []float64 {
+	return f.update(c)
+}
+
+func (f *funcIndicator) Outputs() []string {
+	return f.outputs
+}
+
+func (f *funcIndicator) state(s *stateCodec) {
+	s.sub(f.periodic)
+}
+
+// newValueIndicator wraps a single value indicator (Sma, Rsi, ...) fed
+// with src of every candle
+func newValueIndicator(u maUpdater, src Source, output string) Indicator {
+	return &funcIndicator{
+		periodic: u,
+		outputs:  []string{output},
+		update: func(c Candle) []float64 {
+			return []float64{u.Update(src.Value(c))}
+		},
+	}
+}
+
+// newCandleIndicator wraps a single value candle indicator (Atr, Obv, ...)
+func newCandleIndicator(u candleUpdater, output string) Indicator {
+	return &funcIndicator{
+		periodic: u,
+		outputs:  []string{output},
+		update: func(c Candle) []float64 {
+			return []float64{u.Update(c)}
+		},
+	}
+}
+
+// pairIndicator adapts the two series indicators (Correl, Beta) to
+// PairIndicator
+type pairIndicator struct {
+	periodic
+	output string
+	x, y   Source
+	update func(x, y float64) float64
+}
+
+func (p *pairIndicator) Update(c Candle) []float64 {
+	return p.Update2(c, c)
+}
+
+func (p *pairIndicator) Update2(x, y Candle) []float64 {
+	return []float64{p.update(p.x.Value(x), p.y.Value(y))}
+}
+
+func (p *pairIndicator) Outputs() []string {
+	return []string{p.output}
+}
+
+func (p *pairIndicator) state(s *stateCodec) {
+	s.sub(p.periodic)
+}
+
+type candleUpdater interface {
+	Update(c Candle) float64
+	InitPeriod() int64
+	Valid() bool
+	Stateful
+}
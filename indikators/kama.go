@@ -81,6 +81,15 @@ func (k *Kama) Valid() bool {
 	return k.sz > k.InitPeriod()
 }
 
+func (k *Kama) state(s *stateCodec) {
+	s.param(k.n)
+	s.int(&k.sz)
+	s.floats(k.hist)
+	s.floats(k.absChgHist)
+	s.float(&k.sumAbsChg)
+	s.float(&k.kama)
+}
+
 // Developed by Perry Kaufman, Kaufman's Adaptive Moving
 // Average (KAMA) is a moving average designed to account
 // for market noise or volatility. KAMA will closely follow
@@ -91,13 +100,14 @@ func (k *Kama) Valid() bool {
 // overall trend, time turning points and filter price
 // movements.
 //  https://school.stockcharts.com/doku.php?id=technical_indicators:kaufman_s_adaptive_moving_average
-func KamaArr(in []float64, n int64) []float64 {
+func KamaArr(in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	k := NewKama(n)
 	for i, v := range in {
 		out[i] = k.Update(v)
 	}
+	warmup(mode, k.InitPeriod(), out)
 
 	return out
 }
@@ -0,0 +1,91 @@
+package indikators
+
+// Keltner Channels are volatility-based envelopes set above and below a
+// moving average. This indicator is similar to Bollinger Bands, which use
+// the standard deviation to set the bands. Instead of using the standard
+// deviation, Keltner Channels use the Average True Range (ATR) to set
+// channel distance. The channels are typically set two Average True Range
+// values above and below the 20-day EMA. The moving average dictates
+// direction and the Average True Range sets channel width. Keltner
+// Channels are a trend following indicator used to identify reversals
+// with channel breakouts and channel direction.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:keltner_channels
+//  https://www.investopedia.com/terms/k/keltnerchannel.asp
+type Keltner struct {
+	initPeriod int64
+	ma         *Ma
+	atr        *Atr
+	mult       float64
+	sz         int64
+}
+
+func NewKeltner(t MaType, n int64, atrN int64, mult float64) *Keltner {
+	ma := NewMa(t, n)
+	atr := NewAtr(atrN)
+	a := ma.InitPeriod()
+	b := atr.InitPeriod()
+	if a < b {
+		a = b
+	}
+	return &Keltner{
+		initPeriod: a,
+		ma:         ma,
+		atr:        atr,
+		mult:       mult,
+		sz:         0,
+	}
+}
+
+// upper, middle, lower
+func (k *Keltner) Update(c Candle) (float64, float64, float64) {
+	k.sz++
+
+	m := k.ma.Update(c.Close)
+	atr := k.atr.Update(c)
+
+	if k.sz <= k.initPeriod {
+		return 0, 0, 0
+	}
+
+	return m + k.mult*atr, m, m - k.mult*atr
+}
+
+func (k *Keltner) InitPeriod() int64 {
+	return k.initPeriod
+}
+
+func (k *Keltner) Valid() bool {
+	return k.sz > k.initPeriod
+}
+
+func (k *Keltner) state(s *stateCodec) {
+	s.paramFloat(k.mult)
+	s.sub(k.ma)
+	s.sub(k.atr)
+	s.int(&k.sz)
+}
+
+// Keltner Channels are volatility-based envelopes set above and below a
+// moving average. This indicator is similar to Bollinger Bands, which use
+// the standard deviation to set the bands. Instead of using the standard
+// deviation, Keltner Channels use the Average True Range (ATR) to set
+// channel distance. The channels are typically set two Average True Range
+// values above and below the 20-day EMA. The moving average dictates
+// direction and the Average True Range sets channel width. Keltner
+// Channels are a trend following indicator used to identify reversals
+// with channel breakouts and channel direction.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:keltner_channels
+//  https://www.investopedia.com/terms/k/keltnerchannel.asp
+func KeltnerArr(t MaType, in []Candle, n int64, atrN int64, mult float64, mode ...WarmupMode) ([]float64, []float64, []float64) {
+	u := make([]float64, len(in))
+	m := make([]float64, len(in))
+	l := make([]float64, len(in))
+
+	k := NewKeltner(t, n, atrN, mult)
+	for i, v := range in {
+		u[i], m[i], l[i] = k.Update(v)
+	}
+	warmup(mode, k.InitPeriod(), u, m, l)
+
+	return u, m, l
+}
@@ -0,0 +1,34 @@
+package indikators_test
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+// Keltner(ema 30, atr 14) is the TA-Lib EMA and ATR goldens combined
+func TestKeltner(t *testing.T) {
+	in := readOHLCV(t)
+	ema := readCSV(t, filepath.Join("golden", "ema_30.csv"))
+	atr := readCSV(t, filepath.Join("golden", "atr_14.csv"))
+
+	for _, mult := range []float64{2, 1.5} {
+		u, m, l := indikators.KeltnerArr(indikators.EMA, in, 30, 14, mult, indikators.WarmupNaN)
+		for i := range in {
+			if i < 29 {
+				assert.True(t, math.IsNaN(u[i]) && math.IsNaN(m[i]) && math.IsNaN(l[i]), "bar %d should be warming up", i)
+				continue
+			}
+			assert.InDelta(t, ema[i][0], m[i], 1e-9, "bar %d middle should be equal", i)
+			assert.InDelta(t, ema[i][0]+mult*atr[i][0], u[i], 1e-9, "bar %d upper should be equal", i)
+			assert.InDelta(t, ema[i][0]-mult*atr[i][0], l[i], 1e-9, "bar %d lower should be equal", i)
+		}
+	}
+
+	// the band is the longer of both warm-ups
+	k := indikators.NewKeltner(indikators.SMA, 5, 20, 2)
+	assert.Equal(t, int64(20), k.InitPeriod(), "they should be equal")
+}
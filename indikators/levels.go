@@ -0,0 +1,110 @@
+package indikators
+
+import (
+	"math"
+	"sort"
+)
+
+// Level is a support/resistance price where several swings clustered
+type Level struct {
+	// average price of the swings in the cluster
+	Price float64 `json:"price"`
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+	// swing highs and lows in the cluster, a level touched from both
+	// sides flipped between support and resistance
+	Highs int `json:"highs"`
+	Lows  int `json:"lows"`
+	// bars of the first and last swing
+	First int `json:"first"`
+	Last  int `json:"last"`
+}
+
+// Touches is the number of swings in the level
+func (l Level) Touches() int {
+	return l.Highs + l.Lows
+}
+
+// LevelSettings configures SupportResistance
+type LevelSettings struct {
+	// swings within Tolerance percent of a cluster's price join it
+	Tolerance float64
+	// levels with fewer swings are dropped
+	MinTouches int
+}
+
+func DefaultLevelSettings() LevelSettings {
+	return LevelSettings{
+		Tolerance:  0.5,
+		MinTouches: 2,
+	}
+}
+
+// SupportResistance clusters swing highs and lows by price into levels.
+// Swings are taken in price order and join the current cluster while
+// within Tolerance percent of its average price. The result is ordered by
+// price, pair it with the last close to tell support (below) from
+// resistance (above).
+//  https://www.investopedia.com/trading/support-and-resistance-basics/
+func SupportResistance(swings []Swing, s LevelSettings) []Level {
+	sorted := make([]Swing, 0, len(swings))
+	for _, sw := range swings {
+		if !math.IsNaN(sw.Value) {
+			sorted = append(sorted, sw)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value < sorted[j].Value
+	})
+
+	var out []Level
+	var cur Level
+	sum := 0.0
+	flush := func() {
+		if cur.Touches() >= s.MinTouches && cur.Touches() > 0 {
+			cur.Price = sum / float64(cur.Touches())
+			out = append(out, cur)
+		}
+	}
+	for _, sw := range sorted {
+		if cur.Touches() > 0 {
+			mean := sum / float64(cur.Touches())
+			if math.Abs(sw.Value-mean) > math.Abs(mean)*s.Tolerance/100.0 {
+				flush()
+				cur, sum = Level{}, 0
+			}
+		}
+		if cur.Touches() == 0 {
+			cur = Level{Low: sw.Value, High: sw.Value, First: sw.Index, Last: sw.Index}
+		}
+		sum += sw.Value
+		if sw.High {
+			cur.Highs++
+		} else {
+			cur.Lows++
+		}
+		cur.Low = min(cur.Low, sw.Value)
+		cur.High = max(cur.High, sw.Value)
+		if sw.Index < cur.First {
+			cur.First = sw.Index
+		}
+		if sw.Index > cur.Last {
+			cur.Last = sw.Index
+		}
+	}
+	flush()
+
+	return out
+}
+
+// CandleLevels finds the swings of candles with finder, e.g. Fractal(5,
+// 5), and clusters them into support/resistance levels
+func CandleLevels(in []Candle, finder SwingFinder, s LevelSettings) []Level {
+	high := make([]float64, len(in))
+	low := make([]float64, len(in))
+	for i, c := range in {
+		high[i] = c.High
+		low[i] = c.Low
+	}
+	return SupportResistance(finder(high, low), s)
+}
@@ -0,0 +1,166 @@
+package indikators
+
+import (
+	"math"
+)
+
+// Linear Regression fits a least squares straight line through the last
+// n values, time being the independent variable. Update returns the value
+// of the line at the latest bar, an average that lags less than the moving
+// averages. The other properties of the fitted line are available after
+// every update: Slope (change per bar), Intercept (value of the line at
+// the oldest bar of the window, like TA-Lib), Angle (slope in degrees),
+// Forecast (the line projected one bar ahead, TA-Lib's Time Series
+// Forecast) and R2 (coefficient of determination, how well the line fits,
+// from 0 to 1).
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:slope
+//  https://www.investopedia.com/terms/r/r-squared.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/linear-regression
+type LinReg struct {
+	n         int64
+	hist      *CBuf
+	sumX      float64
+	sumXSqr   float64
+	divisor   float64
+	sumY      float64
+	sumXY     float64
+	slope     float64
+	intercept float64
+	r2        float64
+	sz        int64
+}
+
+func NewLinReg(n int64) *LinReg {
+	nf := float64(n)
+	sumX := nf * (nf - 1) * 0.5
+	sumXSqr := nf * (nf - 1) * (2*nf - 1) / 6
+	return &LinReg{
+		n:         n,
+		hist:      NewCBuf(n),
+		sumX:      sumX,
+		sumXSqr:   sumXSqr,
+		divisor:   sumX*sumX - nf*sumXSqr,
+		sumY:      0,
+		sumXY:     0,
+		slope:     0,
+		intercept: 0,
+		r2:        0,
+		sz:        0,
+	}
+}
+
+func (l *LinReg) Update(v float64) float64 {
+	l.sz++
+
+	// x counts bars back from the latest one, shifting the window adds the
+	// previous sum to sumXY and drops the oldest value at x = n
+	old := l.hist.Append(v)
+	l.sumXY += l.sumY - float64(l.n)*old
+	l.sumY += v - old
+
+	if l.sz < l.n {
+		return 0
+	}
+
+	nf := float64(l.n)
+	if almostZero(l.divisor) {
+		// single bar window
+		l.slope, l.intercept, l.r2 = 0, v, 0
+		return v
+	}
+	l.slope = (nf*l.sumXY - l.sumX*l.sumY) / l.divisor
+	l.intercept = (l.sumY - l.slope*l.sumX) / nf
+
+	mean := l.sumY / nf
+	ssTot := float64(0)
+	l.hist.Iter(func(v float64) {
+		diff := v - mean
+		ssTot += diff * diff
+	})
+	if almostZero(ssTot) {
+		l.r2 = 0
+	} else {
+		// explained variance over total variance
+		sxx := l.sumXSqr - l.sumX*l.sumX/nf
+		l.r2 = l.slope * l.slope * sxx / ssTot
+	}
+
+	return l.intercept + l.slope*(nf-1)
+}
+
+// Slope of the line fitted on the latest window
+func (l *LinReg) Slope() float64 {
+	return l.slope
+}
+
+// Intercept is the value of the line at the oldest bar of the window
+func (l *LinReg) Intercept() float64 {
+	return l.intercept
+}
+
+// Angle of the slope in degrees
+func (l *LinReg) Angle() float64 {
+	return math.Atan(l.slope) * (180.0 / math.Pi)
+}
+
+// Forecast is the line projected to the next bar (TSF)
+func (l *LinReg) Forecast() float64 {
+	if l.sz < l.n {
+		return 0
+	}
+	return l.intercept + l.slope*float64(l.n)
+}
+
+// R2 is the coefficient of determination of the fit
+func (l *LinReg) R2() float64 {
+	return l.r2
+}
+
+func (l *LinReg) InitPeriod() int64 {
+	return l.n - 1
+}
+
+func (l *LinReg) Valid() bool {
+	return l.sz > l.InitPeriod()
+}
+
+func (l *LinReg) state(s *stateCodec) {
+	s.param(l.n)
+	s.sub(l.hist)
+	s.float(&l.sumY)
+	s.float(&l.sumXY)
+	s.float(&l.slope)
+	s.float(&l.intercept)
+	s.float(&l.r2)
+	s.int(&l.sz)
+}
+
+// Linear Regression fits a least squares straight line through the last
+// n values, time being the independent variable. It returns the value of
+// the line at every bar (TA-Lib LINEARREG) with the slope, the intercept
+// at the oldest bar of the window, the angle in degrees, the forecast of
+// the next bar (TSF) and the coefficient of determination R².
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:slope
+//  https://www.investopedia.com/terms/r/r-squared.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/linear-regression
+func LinRegArr(in []float64, n int64, mode ...WarmupMode) ([]float64, []float64, []float64, []float64, []float64, []float64) {
+	reg := make([]float64, len(in))
+	slope := make([]float64, len(in))
+	intercept := make([]float64, len(in))
+	angle := make([]float64, len(in))
+	forecast := make([]float64, len(in))
+	r2 := make([]float64, len(in))
+
+	l := NewLinReg(n)
+	for i, v := range in {
+		reg[i] = l.Update(v)
+		slope[i] = l.Slope()
+		intercept[i] = l.Intercept()
+		angle[i] = l.Angle()
+		forecast[i] = l.Forecast()
+		r2[i] = l.R2()
+	}
+	warmup(mode, l.InitPeriod(), reg, slope, intercept, angle, forecast, r2)
+
+	return reg, slope, intercept, angle, forecast, r2
+}
@@ -1,8 +1,12 @@
 package indikators
 
+import (
+	"fmt"
+	"strings"
+)
+
 type MaType int
 
-// TODO: support Mama & T3
 const (
 	SMA MaType = iota
 	EMA
@@ -11,6 +15,36 @@ const (
 	TEMA
 	TRIMA
 	KAMA
+	MAMA
+	T3
+)
+
+var maTypeNames = []string{"sma", "ema", "wma", "dema", "tema", "trima", "kama", "mama", "t3"}
+
+func (t MaType) String() string {
+	if t < 0 || int(t) >= len(maTypeNames) {
+		return fmt.Sprintf("MaType(%d)", int(t))
+	}
+	return maTypeNames[t]
+}
+
+// ParseMaType converts a case insensitive name ("sma", "ema", ...) to MaType
+func ParseMaType(s string) (MaType, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for i, name := range maTypeNames {
+		if name == s {
+			return MaType(i), nil
+		}
+	}
+	return 0, fmt.Errorf("indikators: unknown moving average type %q", s)
+}
+
+// Parameters used when MAMA and T3 are created through NewMa, the n
+// period is ignored by MAMA
+const (
+	MamaFastLimit = 0.5
+	MamaSlowLimit = 0.05
+	T3VFactor     = 0.7
 )
 
 // Convenient wrapper for different moving average types
@@ -36,6 +70,10 @@ func NewMa(t MaType, n int64) *Ma {
 		mu = NewTrima(n)
 	case KAMA:
 		mu = NewKama(n)
+	case MAMA:
+		mu = mamaUpdater{NewMama(MamaFastLimit, MamaSlowLimit)}
+	case T3:
+		mu = NewT3Ma(n, T3VFactor)
 	default:
 		return nil
 	}
@@ -57,14 +95,19 @@ func (m *Ma) Valid() bool {
 	return m.mu.Valid()
 }
 
+func (m *Ma) state(s *stateCodec) {
+	s.sub(m.mu)
+}
+
 // Convenient wrapper for different moving average types
-func MaArr(t MaType, in []float64, n int64) []float64 {
+func MaArr(t MaType, in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	m := NewMa(t, n)
 	for i, v := range in {
 		out[i] = m.Update(v)
 	}
+	warmup(mode, m.InitPeriod(), out)
 
 	return out
 }
@@ -73,4 +116,15 @@ type maUpdater interface {
 	Update(v float64) float64
 	InitPeriod() int64
 	Valid() bool
+	Stateful
+}
+
+// Mama yields both MAMA and FAMA, only MAMA is used as moving average
+type mamaUpdater struct {
+	*Mama
+}
+
+func (m mamaUpdater) Update(v float64) float64 {
+	mama, _ := m.Mama.Update(v)
+	return mama
 }
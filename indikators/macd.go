@@ -42,7 +42,7 @@ func NewMacd(fastN, slowN, signalN int64) *Macd {
 //  https://school.stockcharts.com/doku.php?id=technical_indicators:moving_average_convergence_divergence_macd
 //  https://www.investopedia.com/terms/m/macd.asp
 //  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/macd
-func MacdArr(in []float64, fastN, slowN, signalN int64) ([]float64, []float64, []float64) {
+func MacdArr(in []float64, fastN, slowN, signalN int64, mode ...WarmupMode) ([]float64, []float64, []float64) {
 	macd := make([]float64, len(in))
 	signal := make([]float64, len(in))
 	hist := make([]float64, len(in))
@@ -51,6 +51,7 @@ func MacdArr(in []float64, fastN, slowN, signalN int64) ([]float64, []float64, [
 	for i, v := range in {
 		macd[i], signal[i], hist[i] = m.Update(v)
 	}
+	warmup(mode, m.InitPeriod(), macd, signal, hist)
 
 	return macd, signal, hist
 }
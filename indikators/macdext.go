@@ -65,10 +65,20 @@ func (m *MacdExt) Valid() bool {
 	return m.sz > m.InitPeriod()
 }
 
+func (m *MacdExt) state(s *stateCodec) {
+	s.param(m.fastN)
+	s.param(m.slowN)
+	s.param(m.signalN)
+	s.sub(m.fast)
+	s.sub(m.slow)
+	s.sub(m.signal)
+	s.int(&m.sz)
+}
+
 // Refer to MACD.
 // This is a general version of MACD with moving average types
 // for fast, slow, and signal lines as paremters.
-func MacdExtArr(in []float64, fastT MaType, fastN int64, slowT MaType, slowN int64, signalT MaType, signalN int64) ([]float64, []float64, []float64) {
+func MacdExtArr(in []float64, fastT MaType, fastN int64, slowT MaType, slowN int64, signalT MaType, signalN int64, mode ...WarmupMode) ([]float64, []float64, []float64) {
 	macd := make([]float64, len(in))
 	signal := make([]float64, len(in))
 	hist := make([]float64, len(in))
@@ -77,6 +87,7 @@ func MacdExtArr(in []float64, fastT MaType, fastN int64, slowT MaType, slowN int
 	for i, v := range in {
 		macd[i], signal[i], hist[i] = m.Update(v)
 	}
+	warmup(mode, m.InitPeriod(), macd, signal, hist)
 
 	return macd, signal, hist
 }
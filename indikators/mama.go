@@ -0,0 +1,178 @@
+package indikators
+
+import (
+	"math"
+)
+
+// Developed by John Ehlers, the MESA Adaptive Moving Average (MAMA) adapts
+// to price movement based on the rate of change of phase as measured by the
+// Hilbert Transform Discriminator. The fast attack and slow decay lets the
+// average rapidly ratchet toward price changes and then hold the value
+// until the next ratchet occurs. FAMA, the Following Adaptive Moving
+// Average, is MAMA smoothed with half its alpha, so crossings of MAMA and
+// FAMA can be used as trading signals. The n period of a regular moving
+// average is replaced by the fast and slow alpha limits (0.5 and 0.05 by
+// default).
+//  https://www.mesasoftware.com/papers/MAMA.pdf
+//  https://www.tradingview.com/script/foQxLbU3-Ehlers-MESA-Adaptive-Moving-Average-LazyBear/
+type Mama struct {
+	fastLimit float64
+	slowLimit float64
+	sz        int64
+	price     [4]float64
+	smooth    [7]float64
+	detrender [7]float64
+	i1        [7]float64
+	q1        [7]float64
+	i2        float64
+	q2        float64
+	re        float64
+	im        float64
+	period    float64
+	phase     float64
+	mama      float64
+	fama      float64
+}
+
+func NewMama(fastLimit, slowLimit float64) *Mama {
+	return &Mama{
+		fastLimit: fastLimit,
+		slowLimit: slowLimit,
+		sz:        0,
+		period:    0,
+		phase:     0,
+		mama:      0,
+		fama:      0,
+	}
+}
+
+// mama, fama
+func (m *Mama) Update(v float64) (float64, float64) {
+	m.sz++
+
+	shift(m.price[:], v)
+	if m.sz < 4 {
+		return 0, 0
+	}
+
+	// 4 bar weighted price smoother, the first 9 values only warm it up
+	smooth := (4*m.price[0] + 3*m.price[1] + 2*m.price[2] + m.price[3]) / 10.0
+	if m.sz <= 12 {
+		return 0, 0
+	}
+
+	adj := 0.075*m.period + 0.54
+	shift(m.smooth[:], smooth)
+	shift(m.detrender[:], hilbert(m.smooth[:], adj))
+	shift(m.q1[:], hilbert(m.detrender[:], adj))
+	shift(m.i1[:], m.detrender[3])
+	ji := hilbert(m.i1[:], adj)
+	jq := hilbert(m.q1[:], adj)
+
+	// phasor addition for 3 bar averaging
+	i2 := 0.2*(m.i1[0]-jq) + 0.8*m.i2
+	q2 := 0.2*(m.q1[0]+ji) + 0.8*m.q2
+
+	var phase float64
+	if m.i1[0] != 0 {
+		phase = math.Atan(m.q1[0]/m.i1[0]) * 180.0 / math.Pi
+	}
+	deltaPhase := m.phase - phase
+	m.phase = phase
+	if deltaPhase < 1 {
+		deltaPhase = 1
+	}
+
+	alpha := m.fastLimit
+	if deltaPhase > 1 {
+		alpha = m.fastLimit / deltaPhase
+		if alpha < m.slowLimit {
+			alpha = m.slowLimit
+		}
+	}
+
+	m.mama = alpha*v + (1-alpha)*m.mama
+	m.fama = 0.5*alpha*m.mama + (1-0.5*alpha)*m.fama
+
+	// homodyne discriminator, the measured period is used by the next bar
+	m.re = 0.2*(i2*m.i2+q2*m.q2) + 0.8*m.re
+	m.im = 0.2*(i2*m.q2-q2*m.i2) + 0.8*m.im
+	m.i2, m.q2 = i2, q2
+
+	prevPeriod := m.period
+	if m.im != 0 && m.re != 0 {
+		m.period = 360.0 / (math.Atan(m.im/m.re) * 180.0 / math.Pi)
+	}
+	m.period = min(m.period, 1.5*prevPeriod)
+	m.period = max(m.period, 0.67*prevPeriod)
+	m.period = max(min(m.period, 50), 6)
+	m.period = 0.2*m.period + 0.8*prevPeriod
+
+	if m.sz <= m.InitPeriod() {
+		return 0, 0
+	}
+
+	return m.mama, m.fama
+}
+
+func (m *Mama) InitPeriod() int64 {
+	return 32
+}
+
+func (m *Mama) Valid() bool {
+	return m.sz > m.InitPeriod()
+}
+
+func (m *Mama) state(s *stateCodec) {
+	s.paramFloat(m.fastLimit)
+	s.paramFloat(m.slowLimit)
+	s.int(&m.sz)
+	s.floats(m.price[:])
+	s.floats(m.smooth[:])
+	s.floats(m.detrender[:])
+	s.floats(m.i1[:])
+	s.floats(m.q1[:])
+	s.float(&m.i2)
+	s.float(&m.q2)
+	s.float(&m.re)
+	s.float(&m.im)
+	s.float(&m.period)
+	s.float(&m.phase)
+	s.float(&m.mama)
+	s.float(&m.fama)
+}
+
+// Developed by John Ehlers, the MESA Adaptive Moving Average (MAMA) adapts
+// to price movement based on the rate of change of phase as measured by the
+// Hilbert Transform Discriminator. The fast attack and slow decay lets the
+// average rapidly ratchet toward price changes and then hold the value
+// until the next ratchet occurs. FAMA, the Following Adaptive Moving
+// Average, is MAMA smoothed with half its alpha, so crossings of MAMA and
+// FAMA can be used as trading signals. The n period of a regular moving
+// average is replaced by the fast and slow alpha limits (0.5 and 0.05 by
+// default).
+//  https://www.mesasoftware.com/papers/MAMA.pdf
+//  https://www.tradingview.com/script/foQxLbU3-Ehlers-MESA-Adaptive-Moving-Average-LazyBear/
+func MamaArr(in []float64, fastLimit, slowLimit float64, mode ...WarmupMode) ([]float64, []float64) {
+	mama := make([]float64, len(in))
+	fama := make([]float64, len(in))
+
+	m := NewMama(fastLimit, slowLimit)
+	for i, v := range in {
+		mama[i], fama[i] = m.Update(v)
+	}
+	warmup(mode, m.InitPeriod(), mama, fama)
+
+	return mama, fama
+}
+
+// Hilbert transform of the newest value in hist, hist[0] is the newest
+func hilbert(hist []float64, adj float64) float64 {
+	return (0.0962*hist[0] + 0.5769*hist[2] - 0.5769*hist[4] - 0.0962*hist[6]) * adj
+}
+
+// push v as the newest (index 0) value, dropping the oldest one
+func shift(hist []float64, v float64) {
+	copy(hist[1:], hist[:len(hist)-1])
+	hist[0] = v
+}
@@ -0,0 +1,107 @@
+package indikators
+
+// The Money Flow Index (MFI) is an oscillator that uses both price and
+// volume to measure buying and selling pressure. Created by Gene Quong and
+// Avrum Soudack, MFI is also known as volume-weighted RSI. MFI starts with
+// the typical price for each period. Money flow is positive when the
+// typical price rises (buying pressure) and negative when the typical price
+// declines (selling pressure). A ratio of positive and negative money flow
+// is then plugged into an RSI formula to create an oscillator that moves
+// between zero and one hundred.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:money_flow_index_mfi
+//  https://www.investopedia.com/terms/m/mfi.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/MFI
+type Mfi struct {
+	n      int64
+	pos    *CBuf
+	neg    *CBuf
+	posSum float64
+	negSum float64
+	prevTp float64
+	sz     int64
+}
+
+func NewMfi(n int64) *Mfi {
+	return &Mfi{
+		n:      n,
+		pos:    NewCBuf(n),
+		neg:    NewCBuf(n),
+		posSum: 0,
+		negSum: 0,
+		prevTp: 0,
+		sz:     0,
+	}
+}
+
+func (m *Mfi) Update(c Candle) float64 {
+	m.sz++
+
+	tp := c.Typical()
+	prevTp := m.prevTp
+	m.prevTp = tp
+
+	if m.sz == 1 {
+		return 0
+	}
+
+	var pos, neg float64
+	if tp > prevTp {
+		pos = tp * c.Volume
+	} else if tp < prevTp {
+		neg = tp * c.Volume
+	}
+	m.posSum += pos - m.pos.Append(pos)
+	m.negSum += neg - m.neg.Append(neg)
+
+	if m.sz <= m.n {
+		return 0
+	}
+
+	sum := m.posSum + m.negSum
+	if almostZero(sum) {
+		return 0
+	}
+
+	return m.posSum / sum * 100.0
+}
+
+func (m *Mfi) InitPeriod() int64 {
+	return m.n
+}
+
+func (m *Mfi) Valid() bool {
+	return m.sz > m.InitPeriod()
+}
+
+func (m *Mfi) state(s *stateCodec) {
+	s.param(m.n)
+	s.sub(m.pos)
+	s.sub(m.neg)
+	s.float(&m.posSum)
+	s.float(&m.negSum)
+	s.float(&m.prevTp)
+	s.int(&m.sz)
+}
+
+// The Money Flow Index (MFI) is an oscillator that uses both price and
+// volume to measure buying and selling pressure. Created by Gene Quong and
+// Avrum Soudack, MFI is also known as volume-weighted RSI. MFI starts with
+// the typical price for each period. Money flow is positive when the
+// typical price rises (buying pressure) and negative when the typical price
+// declines (selling pressure). A ratio of positive and negative money flow
+// is then plugged into an RSI formula to create an oscillator that moves
+// between zero and one hundred.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:money_flow_index_mfi
+//  https://www.investopedia.com/terms/m/mfi.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/MFI
+func MfiArr(in []Candle, n int64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	m := NewMfi(n)
+	for i, v := range in {
+		out[i] = m.Update(v)
+	}
+	warmup(mode, m.InitPeriod(), out)
+
+	return out
+}
@@ -0,0 +1,81 @@
+package indikators
+
+// On Balance Volume (OBV) measures buying and selling pressure as a
+// cumulative indicator that adds volume on up days and subtracts volume
+// on down days. OBV was developed by Joe Granville and introduced in his
+// 1963 book, Granville's New Key to Stock Market Profits. It was one of
+// the first indicators to measure positive and negative volume flow.
+// Chartists can look for divergences between OBV and price to predict
+// price movements or use OBV to confirm price trends.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:on_balance_volume_obv
+//  https://www.investopedia.com/terms/o/onbalancevolume.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/obv
+type Obv struct {
+	prevC float64
+	obv   float64
+	sz    int64
+}
+
+func NewObv() *Obv {
+	return &Obv{
+		prevC: 0,
+		obv:   0,
+		sz:    0,
+	}
+}
+
+func (o *Obv) Update(c Candle) float64 {
+	o.sz++
+
+	prevC := o.prevC
+	o.prevC = c.Close
+
+	if o.sz == 1 {
+		o.obv = c.Volume
+		return o.obv
+	}
+
+	if c.Close > prevC {
+		o.obv += c.Volume
+	} else if c.Close < prevC {
+		o.obv -= c.Volume
+	}
+
+	return o.obv
+}
+
+func (o *Obv) InitPeriod() int64 {
+	return 0
+}
+
+func (o *Obv) Valid() bool {
+	return o.sz > o.InitPeriod()
+}
+
+func (o *Obv) state(s *stateCodec) {
+	s.float(&o.prevC)
+	s.float(&o.obv)
+	s.int(&o.sz)
+}
+
+// On Balance Volume (OBV) measures buying and selling pressure as a
+// cumulative indicator that adds volume on up days and subtracts volume
+// on down days. OBV was developed by Joe Granville and introduced in his
+// 1963 book, Granville's New Key to Stock Market Profits. It was one of
+// the first indicators to measure positive and negative volume flow.
+// Chartists can look for divergences between OBV and price to predict
+// price movements or use OBV to confirm price trends.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:on_balance_volume_obv
+//  https://www.investopedia.com/terms/o/onbalancevolume.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/obv
+func ObvArr(in []Candle, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	o := NewObv()
+	for i, v := range in {
+		out[i] = o.Update(v)
+	}
+	warmup(mode, o.InitPeriod(), out)
+
+	return out
+}
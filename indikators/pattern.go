@@ -0,0 +1,529 @@
+package indikators
+
+import (
+	"math"
+)
+
+// Pattern is a candlestick pattern recognised by Patterns
+type Pattern int
+
+const (
+	Doji Pattern = iota
+	Hammer
+	ShootingStar
+	Marubozu
+	Engulfing
+	Harami
+	MorningStar
+	EveningStar
+	ThreeWhiteSoldiers
+	ThreeBlackCrows
+	patternCount
+)
+
+var patternNames = []string{
+	"doji", "hammer", "shooting_star", "marubozu", "engulfing", "harami",
+	"morning_star", "evening_star", "three_white_soldiers", "three_black_crows",
+}
+
+func (p Pattern) String() string {
+	if p < 0 || p >= patternCount {
+		return "unknown"
+	}
+	return patternNames[p]
+}
+
+// PatternScores holds the score of every pattern for one bar, indexed by
+// Pattern. Like the TA-Lib CDL functions a score is 100 for a bullish
+// pattern, -100 for a bearish one (80 when only loosely matched) and 0
+// when the pattern is not found. Doji carries no direction and scores 100.
+type PatternScores [patternCount]int
+
+// Sum of the directional scores, doji excluded
+func (p PatternScores) Total() int {
+	total := 0
+	for i, v := range p {
+		if Pattern(i) != Doji {
+			total += v
+		}
+	}
+	return total
+}
+
+// CandleRange is the part of a candle a CandleSetting is measured on
+type CandleRange int
+
+const (
+	RealBody CandleRange = iota
+	HighLow
+	Shadows
+)
+
+// CandleSetting defines what "long", "short", "near"... means. The
+// reference is Factor times the average range of the Period candles
+// before the one being checked, or of the candle itself when Period is 0.
+type CandleSetting struct {
+	Range  CandleRange
+	Period int
+	Factor float64
+}
+
+// PatternSettings are the thresholds used by Patterns, see
+// DefaultPatternSettings for the TA-Lib defaults
+type PatternSettings struct {
+	BodyLong        CandleSetting
+	BodyShort       CandleSetting
+	BodyDoji        CandleSetting
+	ShadowLong      CandleSetting
+	ShadowVeryShort CandleSetting
+	Near            CandleSetting
+	Far             CandleSetting
+	// How deep the third candle of a morning/evening star must close
+	// into the body of the first one
+	Penetration float64
+}
+
+func DefaultPatternSettings() PatternSettings {
+	return PatternSettings{
+		BodyLong:        CandleSetting{Range: RealBody, Period: 10, Factor: 1.0},
+		BodyShort:       CandleSetting{Range: RealBody, Period: 10, Factor: 1.0},
+		BodyDoji:        CandleSetting{Range: HighLow, Period: 10, Factor: 0.1},
+		ShadowLong:      CandleSetting{Range: RealBody, Period: 0, Factor: 1.0},
+		ShadowVeryShort: CandleSetting{Range: HighLow, Period: 10, Factor: 0.1},
+		Near:            CandleSetting{Range: HighLow, Period: 5, Factor: 0.2},
+		Far:             CandleSetting{Range: HighLow, Period: 5, Factor: 0.6},
+		Penetration:     0.3,
+	}
+}
+
+// Patterns recognises common candlestick patterns on a candle stream:
+// doji, hammer, shooting star, marubozu, engulfing, harami, morning and
+// evening star, three white soldiers and three black crows. The rules
+// follow the TA-Lib CDL functions, body and shadow sizes are compared to
+// the average of the preceding candles as defined by PatternSettings.
+//  https://www.investopedia.com/articles/active-trading/092315/5-most-powerful-candlestick-patterns.asp
+//  https://school.stockcharts.com/doku.php?id=chart_analysis:introduction_to_candlesticks
+//  https://ta-lib.org/functions/
+type Patterns struct {
+	settings PatternSettings
+	// bars before the first score of each pattern
+	lookback PatternScores
+	// newest candle last
+	hist []Candle
+	cap  int
+	sz   int64
+}
+
+func NewPatterns(s PatternSettings) *Patterns {
+	period := 0
+	for _, cs := range []CandleSetting{s.BodyLong, s.BodyShort, s.BodyDoji, s.ShadowLong, s.ShadowVeryShort, s.Near, s.Far} {
+		if cs.Period > period {
+			period = cs.Period
+		}
+	}
+	return &Patterns{
+		settings: s,
+		lookback: patternLookback(s),
+		cap:      period + 4,
+		sz:       0,
+	}
+}
+
+// patternLookback is the TA-Lib lookback of every CDL function, a pattern
+// scores 0 before it even when its averages are already known
+func patternLookback(s PatternSettings) PatternScores {
+	periods := func(cs ...CandleSetting) int {
+		n := 0
+		for _, c := range cs {
+			if c.Period > n {
+				n = c.Period
+			}
+		}
+		return n
+	}
+	var out PatternScores
+	out[Doji] = periods(s.BodyDoji)
+	out[Hammer] = periods(s.BodyShort, s.ShadowLong, s.ShadowVeryShort, s.Near) + 1
+	out[ShootingStar] = periods(s.BodyShort, s.ShadowLong, s.ShadowVeryShort) + 1
+	out[Marubozu] = periods(s.BodyLong, s.ShadowVeryShort)
+	out[Engulfing] = 2
+	out[Harami] = periods(s.BodyShort, s.BodyLong) + 1
+	out[MorningStar] = periods(s.BodyShort, s.BodyLong) + 2
+	out[EveningStar] = out[MorningStar]
+	out[ThreeWhiteSoldiers] = periods(s.ShadowVeryShort, s.BodyShort, s.Far, s.Near) + 2
+	out[ThreeBlackCrows] = periods(s.ShadowVeryShort) + 3
+	return out
+}
+
+func (p *Patterns) Update(c Candle) PatternScores {
+	p.sz++
+
+	if len(p.hist) == p.cap {
+		copy(p.hist, p.hist[1:])
+		p.hist = p.hist[:p.cap-1]
+	}
+	p.hist = append(p.hist, c)
+
+	var out PatternScores
+	out[Doji] = p.doji()
+	out[Hammer] = p.hammer()
+	out[ShootingStar] = p.shootingStar()
+	out[Marubozu] = p.marubozu()
+	out[Engulfing] = p.engulfing()
+	out[Harami] = p.harami()
+	out[MorningStar] = p.morningStar()
+	out[EveningStar] = p.eveningStar()
+	out[ThreeWhiteSoldiers] = p.threeWhiteSoldiers()
+	out[ThreeBlackCrows] = p.threeBlackCrows()
+	for i, n := range p.lookback {
+		if p.sz <= int64(n) {
+			out[i] = 0
+		}
+	}
+	return out
+}
+
+func (p *Patterns) InitPeriod() int64 {
+	return int64(p.cap - 1)
+}
+
+func (p *Patterns) Valid() bool {
+	return p.sz > p.InitPeriod()
+}
+
+func (p *Patterns) state(s *stateCodec) {
+	s.param(int64(p.cap))
+	s.int(&p.sz)
+	n := int64(len(p.hist))
+	s.count(&n)
+	if n < 0 || n > int64(p.cap) {
+		s.err = ErrSnapshotMismatch
+		return
+	}
+	hist := p.hist
+	if s.r != nil {
+		hist = make([]Candle, n)
+	}
+	for i := range hist {
+		s.candle(&hist[i])
+	}
+	if s.r != nil && !s.dry {
+		p.hist = hist
+	}
+}
+
+// candle i bars ago, 0 is the newest
+func (p *Patterns) at(i int) (Candle, bool) {
+	if i >= len(p.hist) {
+		return Candle{}, false
+	}
+	return p.hist[len(p.hist)-1-i], true
+}
+
+// reference size for the candle i bars ago
+func (p *Patterns) avg(cs CandleSetting, i int) (float64, bool) {
+	c, ok := p.at(i)
+	if !ok {
+		return 0, false
+	}
+	v := candleRange(cs.Range, c)
+	if cs.Period > 0 {
+		if i+cs.Period >= len(p.hist) {
+			return 0, false
+		}
+		v = 0
+		for j := i + 1; j <= i+cs.Period; j++ {
+			prev, _ := p.at(j)
+			v += candleRange(cs.Range, prev)
+		}
+		v /= float64(cs.Period)
+	}
+	if cs.Range == Shadows {
+		v /= 2
+	}
+	return cs.Factor * v, true
+}
+
+// checks a list of (value, setting, bars ago) against the averages, less
+// tells whether value has to be below the reference or above it
+type avgCheck struct {
+	v    float64
+	cs   CandleSetting
+	i    int
+	less bool
+}
+
+func (p *Patterns) check(checks ...avgCheck) bool {
+	for _, c := range checks {
+		ref, ok := p.avg(c.cs, c.i)
+		if !ok {
+			return false
+		}
+		if c.less && c.v >= ref {
+			return false
+		}
+		if !c.less && c.v <= ref {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Patterns) doji() int {
+	c, _ := p.at(0)
+	ref, ok := p.avg(p.settings.BodyDoji, 0)
+	if ok && realBody(c) <= ref {
+		return 100
+	}
+	return 0
+}
+
+func (p *Patterns) hammer() int {
+	c, _ := p.at(0)
+	prev, ok := p.at(1)
+	if !ok {
+		return 0
+	}
+	near, ok := p.avg(p.settings.Near, 1)
+	if !ok {
+		return 0
+	}
+	s := p.settings
+	if p.check(
+		avgCheck{realBody(c), s.BodyShort, 0, true},
+		avgCheck{lowerShadow(c), s.ShadowLong, 0, false},
+		avgCheck{upperShadow(c), s.ShadowVeryShort, 0, true},
+	) && math.Min(c.Open, c.Close) <= prev.Low+near {
+		return 100
+	}
+	return 0
+}
+
+func (p *Patterns) shootingStar() int {
+	c, _ := p.at(0)
+	prev, ok := p.at(1)
+	if !ok {
+		return 0
+	}
+	s := p.settings
+	if p.check(
+		avgCheck{realBody(c), s.BodyShort, 0, true},
+		avgCheck{upperShadow(c), s.ShadowLong, 0, false},
+		avgCheck{lowerShadow(c), s.ShadowVeryShort, 0, true},
+	) && bodyGapUp(c, prev) {
+		return -100
+	}
+	return 0
+}
+
+func (p *Patterns) marubozu() int {
+	c, _ := p.at(0)
+	s := p.settings
+	if p.check(
+		avgCheck{realBody(c), s.BodyLong, 0, false},
+		avgCheck{upperShadow(c), s.ShadowVeryShort, 0, true},
+		avgCheck{lowerShadow(c), s.ShadowVeryShort, 0, true},
+	) {
+		return color(c) * 100
+	}
+	return 0
+}
+
+func (p *Patterns) engulfing() int {
+	c, _ := p.at(0)
+	prev, ok := p.at(1)
+	if !ok {
+		return 0
+	}
+	white := color(c) == 1 && color(prev) == -1 &&
+		((c.Close >= prev.Open && c.Open < prev.Close) || (c.Close > prev.Open && c.Open <= prev.Close))
+	black := color(c) == -1 && color(prev) == 1 &&
+		((c.Open >= prev.Close && c.Close < prev.Open) || (c.Open > prev.Close && c.Close <= prev.Open))
+	if !white && !black {
+		return 0
+	}
+	if c.Open != prev.Close && c.Close != prev.Open {
+		return color(c) * 100
+	}
+	return color(c) * 80
+}
+
+func (p *Patterns) harami() int {
+	c, _ := p.at(0)
+	prev, ok := p.at(1)
+	if !ok {
+		return 0
+	}
+	s := p.settings
+	if !p.check(avgCheck{realBody(prev), s.BodyLong, 1, false}) || !p.bodyAtMost(c, s.BodyShort, 0) {
+		return 0
+	}
+	top, bottom := math.Max(c.Open, c.Close), math.Min(c.Open, c.Close)
+	prevTop, prevBottom := math.Max(prev.Open, prev.Close), math.Min(prev.Open, prev.Close)
+	if top < prevTop && bottom > prevBottom {
+		return -color(prev) * 100
+	}
+	if top <= prevTop && bottom >= prevBottom {
+		return -color(prev) * 80
+	}
+	return 0
+}
+
+func (p *Patterns) morningStar() int {
+	first, ok := p.at(2)
+	if !ok {
+		return 0
+	}
+	second, _ := p.at(1)
+	third, _ := p.at(0)
+	s := p.settings
+	if color(first) == -1 && color(third) == 1 &&
+		p.check(
+			avgCheck{realBody(first), s.BodyLong, 2, false},
+			avgCheck{realBody(third), s.BodyShort, 0, false},
+		) && p.bodyAtMost(second, s.BodyShort, 1) &&
+		bodyGapDown(second, first) &&
+		third.Close > first.Close+realBody(first)*s.Penetration {
+		return 100
+	}
+	return 0
+}
+
+func (p *Patterns) eveningStar() int {
+	first, ok := p.at(2)
+	if !ok {
+		return 0
+	}
+	second, _ := p.at(1)
+	third, _ := p.at(0)
+	s := p.settings
+	if color(first) == 1 && color(third) == -1 &&
+		p.check(
+			avgCheck{realBody(first), s.BodyLong, 2, false},
+			avgCheck{realBody(third), s.BodyShort, 0, false},
+		) && p.bodyAtMost(second, s.BodyShort, 1) &&
+		bodyGapUp(second, first) &&
+		third.Close < first.Close-realBody(first)*s.Penetration {
+		return -100
+	}
+	return 0
+}
+
+func (p *Patterns) threeWhiteSoldiers() int {
+	first, ok := p.at(2)
+	if !ok {
+		return 0
+	}
+	second, _ := p.at(1)
+	third, _ := p.at(0)
+	if color(first) != 1 || color(second) != 1 || color(third) != 1 ||
+		!(third.Close > second.Close && second.Close > first.Close) {
+		return 0
+	}
+	s := p.settings
+	near2, ok2 := p.avg(s.Near, 2)
+	near1, ok1 := p.avg(s.Near, 1)
+	far2, ok3 := p.avg(s.Far, 2)
+	far1, ok4 := p.avg(s.Far, 1)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return 0
+	}
+	if p.check(
+		avgCheck{upperShadow(first), s.ShadowVeryShort, 2, true},
+		avgCheck{upperShadow(second), s.ShadowVeryShort, 1, true},
+		avgCheck{upperShadow(third), s.ShadowVeryShort, 0, true},
+		avgCheck{realBody(third), s.BodyShort, 0, false},
+	) &&
+		// each opens within or near the previous body
+		second.Open > first.Open && second.Open <= first.Close+near2 &&
+		third.Open > second.Open && third.Open <= second.Close+near1 &&
+		// and is not far shorter than the previous one
+		realBody(second) > realBody(first)-far2 &&
+		realBody(third) > realBody(second)-far1 {
+		return 100
+	}
+	return 0
+}
+
+func (p *Patterns) threeBlackCrows() int {
+	prior, ok := p.at(3)
+	if !ok {
+		return 0
+	}
+	first, _ := p.at(2)
+	second, _ := p.at(1)
+	third, _ := p.at(0)
+	s := p.settings
+	if color(prior) == 1 && color(first) == -1 && color(second) == -1 && color(third) == -1 &&
+		p.check(
+			avgCheck{lowerShadow(first), s.ShadowVeryShort, 2, true},
+			avgCheck{lowerShadow(second), s.ShadowVeryShort, 1, true},
+			avgCheck{lowerShadow(third), s.ShadowVeryShort, 0, true},
+		) &&
+		// each opens within the previous body
+		second.Open < first.Open && second.Open > first.Close &&
+		third.Open < second.Open && third.Open > second.Close &&
+		// the first one closes under the high of the prior white candle
+		prior.High > first.Close &&
+		first.Close > second.Close && second.Close > third.Close {
+		return -100
+	}
+	return 0
+}
+
+// real body of c is not larger than the reference
+func (p *Patterns) bodyAtMost(c Candle, cs CandleSetting, i int) bool {
+	ref, ok := p.avg(cs, i)
+	return ok && realBody(c) <= ref
+}
+
+// Candlestick patterns recognised on a candle series, see Patterns
+func PatternsArr(in []Candle, s PatternSettings) []PatternScores {
+	out := make([]PatternScores, len(in))
+
+	p := NewPatterns(s)
+	for i, v := range in {
+		out[i] = p.Update(v)
+	}
+
+	return out
+}
+
+func candleRange(r CandleRange, c Candle) float64 {
+	switch r {
+	case HighLow:
+		return c.High - c.Low
+	case Shadows:
+		return upperShadow(c) + lowerShadow(c)
+	default:
+		return realBody(c)
+	}
+}
+
+func realBody(c Candle) float64 {
+	return math.Abs(c.Close - c.Open)
+}
+
+func upperShadow(c Candle) float64 {
+	return c.High - math.Max(c.Open, c.Close)
+}
+
+func lowerShadow(c Candle) float64 {
+	return math.Min(c.Open, c.Close) - c.Low
+}
+
+// 1 for a white (rising) candle, -1 for a black one
+func color(c Candle) int {
+	if c.Close >= c.Open {
+		return 1
+	}
+	return -1
+}
+
+func bodyGapUp(c, prev Candle) bool {
+	return math.Min(c.Open, c.Close) > math.Max(prev.Open, prev.Close)
+}
+
+func bodyGapDown(c, prev Candle) bool {
+	return math.Max(c.Open, c.Close) < math.Min(prev.Open, prev.Close)
+}
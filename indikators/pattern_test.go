@@ -0,0 +1,170 @@
+package indikators_test
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+// flat returns n white candles with a body of 1 and a range of 2, they set
+// the averages the pattern candles are compared to: long body > 1, doji
+// and very short shadow < 0.2, near 0.4, far 1.2
+func flat(n int) []indikators.Candle {
+	out := make([]indikators.Candle, n)
+	for i := range out {
+		out[i] = indikators.Candle{Open: 100, High: 101.5, Low: 99.5, Close: 101}
+	}
+	return out
+}
+
+func ohlc(o, h, l, c float64) indikators.Candle {
+	return indikators.Candle{Open: o, High: h, Low: l, Close: c}
+}
+
+// lastScore is the score of p on the last of 12 flat candles followed by
+// the fixture
+func lastScore(p indikators.Pattern, fixture ...indikators.Candle) int {
+	out := indikators.PatternsArr(append(flat(12), fixture...), indikators.DefaultPatternSettings())
+	return out[len(out)-1][p]
+}
+
+func TestPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern indikators.Pattern
+		fixture []indikators.Candle
+		want    int
+	}{
+		{name: "Doji", pattern: indikators.Doji, fixture: []indikators.Candle{ohlc(100, 101, 99, 100.1)}, want: 100},
+		{name: "Doji body too large", pattern: indikators.Doji, fixture: []indikators.Candle{ohlc(100, 101, 99, 100.5)}, want: 0},
+
+		{name: "Hammer", pattern: indikators.Hammer, fixture: []indikators.Candle{ohlc(99.6, 99.85, 99, 99.8)}, want: 100},
+		{name: "Hammer far above the prior low", pattern: indikators.Hammer, fixture: []indikators.Candle{ohlc(101.6, 101.85, 101, 101.8)}, want: 0},
+		{name: "Hammer with an upper shadow", pattern: indikators.Hammer, fixture: []indikators.Candle{ohlc(99.6, 100.3, 99, 99.8)}, want: 0},
+
+		{name: "Shooting star", pattern: indikators.ShootingStar, fixture: []indikators.Candle{ohlc(101.3, 102, 101.15, 101.2)}, want: -100},
+		{name: "Shooting star without a gap", pattern: indikators.ShootingStar, fixture: []indikators.Candle{ohlc(100.9, 101.6, 100.75, 100.8)}, want: 0},
+
+		{name: "White marubozu", pattern: indikators.Marubozu, fixture: []indikators.Candle{ohlc(100, 102.05, 99.95, 102)}, want: 100},
+		{name: "Black marubozu", pattern: indikators.Marubozu, fixture: []indikators.Candle{ohlc(102, 102.05, 99.95, 100)}, want: -100},
+		{name: "Marubozu with an upper shadow", pattern: indikators.Marubozu, fixture: []indikators.Candle{ohlc(100, 102.5, 99.95, 102)}, want: 0},
+
+		{name: "Bullish engulfing", pattern: indikators.Engulfing, fixture: []indikators.Candle{ohlc(101, 101.2, 99.8, 100), ohlc(99.8, 101.6, 99.7, 101.5)}, want: 100},
+		{name: "Bullish engulfing opening at the prior close", pattern: indikators.Engulfing, fixture: []indikators.Candle{ohlc(101, 101.2, 99.8, 100), ohlc(100, 101.6, 99.7, 101.5)}, want: 80},
+		{name: "Bearish engulfing", pattern: indikators.Engulfing, fixture: []indikators.Candle{ohlc(100, 101.2, 99.8, 101), ohlc(101.2, 101.3, 99.7, 99.8)}, want: -100},
+		{name: "Engulfing inside the prior body", pattern: indikators.Engulfing, fixture: []indikators.Candle{ohlc(101, 101.2, 99.8, 100), ohlc(100.2, 101, 100.1, 100.8)}, want: 0},
+
+		{name: "Bullish harami", pattern: indikators.Harami, fixture: []indikators.Candle{ohlc(103, 103.1, 99.9, 100), ohlc(101, 101.6, 100.9, 101.5)}, want: 100},
+		{name: "Bullish harami touching the prior body", pattern: indikators.Harami, fixture: []indikators.Candle{ohlc(103, 103.1, 99.9, 100), ohlc(100, 100.6, 99.9, 100.5)}, want: 80},
+		{name: "Bearish harami", pattern: indikators.Harami, fixture: []indikators.Candle{ohlc(100, 103.1, 99.9, 103), ohlc(101.5, 101.6, 100.9, 101)}, want: -100},
+		{name: "Harami outside the prior body", pattern: indikators.Harami, fixture: []indikators.Candle{ohlc(103, 103.1, 99.9, 100), ohlc(102.5, 103.6, 102.4, 103.5)}, want: 0},
+
+		{name: "Morning star", pattern: indikators.MorningStar, fixture: []indikators.Candle{ohlc(103, 103.1, 99.9, 100), ohlc(99.5, 99.6, 99.2, 99.3), ohlc(99.6, 102.1, 99.5, 102)}, want: 100},
+		{name: "Morning star not closing into the first body", pattern: indikators.MorningStar, fixture: []indikators.Candle{ohlc(103, 103.1, 99.9, 100), ohlc(99.5, 99.6, 99.2, 99.3), ohlc(99.6, 100.6, 99.5, 100.5)}, want: 0},
+		{name: "Morning star without a gap", pattern: indikators.MorningStar, fixture: []indikators.Candle{ohlc(103, 103.1, 99.9, 100), ohlc(100.3, 100.4, 100, 100.1), ohlc(99.6, 102.1, 99.5, 102)}, want: 0},
+
+		{name: "Evening star", pattern: indikators.EveningStar, fixture: []indikators.Candle{ohlc(100, 103.1, 99.9, 103), ohlc(103.5, 103.8, 103.4, 103.7), ohlc(103.4, 103.5, 100.9, 101)}, want: -100},
+		{name: "Evening star not closing into the first body", pattern: indikators.EveningStar, fixture: []indikators.Candle{ohlc(100, 103.1, 99.9, 103), ohlc(103.5, 103.8, 103.4, 103.7), ohlc(103.4, 103.5, 102.4, 102.5)}, want: 0},
+
+		{name: "Three white soldiers", pattern: indikators.ThreeWhiteSoldiers, fixture: []indikators.Candle{ohlc(101, 102.05, 100.9, 102), ohlc(101.8, 103.05, 101.7, 103), ohlc(102.8, 104.05, 102.7, 104)}, want: 100},
+		{name: "Three white soldiers with a long upper shadow", pattern: indikators.ThreeWhiteSoldiers, fixture: []indikators.Candle{ohlc(101, 102.05, 100.9, 102), ohlc(101.8, 103.05, 101.7, 103), ohlc(102.8, 104.5, 102.7, 104)}, want: 0},
+		{name: "Three white soldiers opening above the prior close", pattern: indikators.ThreeWhiteSoldiers, fixture: []indikators.Candle{ohlc(101, 102.05, 100.9, 102), ohlc(101.8, 103.05, 101.7, 103), ohlc(103.5, 104.55, 103.4, 104.5)}, want: 0},
+
+		{name: "Three black crows", pattern: indikators.ThreeBlackCrows, fixture: []indikators.Candle{ohlc(101.2, 101.3, 100.15, 100.2), ohlc(100.8, 100.9, 99.45, 99.5), ohlc(100, 100.1, 98.75, 98.8)}, want: -100},
+		{name: "Three black crows opening below the prior close", pattern: indikators.ThreeBlackCrows, fixture: []indikators.Candle{ohlc(101.2, 101.3, 100.15, 100.2), ohlc(100.8, 100.9, 99.45, 99.5), ohlc(99.4, 99.5, 98.15, 98.2)}, want: 0},
+		{name: "Three black crows after a black candle", pattern: indikators.ThreeBlackCrows, fixture: []indikators.Candle{ohlc(101.5, 101.6, 100.45, 100.5), ohlc(101.2, 101.3, 100.15, 100.2), ohlc(100.8, 100.9, 99.45, 99.5), ohlc(100, 100.1, 98.75, 98.8)}, want: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, lastScore(test.pattern, test.fixture...), "they should be equal")
+		})
+	}
+}
+
+func TestPatternsLookback(t *testing.T) {
+	star := ohlc(101.3, 102, 101.15, 101.2)
+	engulfing := []indikators.Candle{ohlc(101, 101.2, 99.8, 100), ohlc(99.8, 101.6, 99.7, 101.5)}
+
+	tests := []struct {
+		name    string
+		pattern indikators.Pattern
+		in      []indikators.Candle
+		want    int
+	}{
+		// the averages are known from bar 10 but TA-Lib needs the prior candle too
+		{name: "Shooting star on bar 10", pattern: indikators.ShootingStar, in: append(flat(10), star), want: 0},
+		{name: "Shooting star on bar 11", pattern: indikators.ShootingStar, in: append(flat(11), star), want: -100},
+		{name: "Engulfing on bar 1", pattern: indikators.Engulfing, in: engulfing, want: 0},
+		{name: "Engulfing on bar 2", pattern: indikators.Engulfing, in: append(flat(1), engulfing...), want: 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := indikators.PatternsArr(test.in, indikators.DefaultPatternSettings())
+			assert.Equal(t, test.want, out[len(out)-1][test.pattern], "they should be equal")
+		})
+	}
+}
+
+func TestPatternScores(t *testing.T) {
+	var s indikators.PatternScores
+	s[indikators.Doji] = 100
+	s[indikators.Engulfing] = 80
+	s[indikators.ThreeBlackCrows] = -100
+	assert.Equal(t, -20, s.Total(), "doji should be excluded")
+
+	assert.Equal(t, "three_white_soldiers", indikators.ThreeWhiteSoldiers.String(), "they should be equal")
+	assert.Equal(t, "unknown", indikators.Pattern(-1).String(), "they should be equal")
+}
+
+// golden/patterns.csv holds the TA-Lib CDL scores of patterns_ohlcv.csv
+// for the patterns named in its header
+func TestPatternsGolden(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "golden", "patterns.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := make([]indikators.Pattern, len(records[0]))
+	for j, name := range records[0] {
+		columns[j] = -1
+		for p := range (indikators.PatternScores{}) {
+			if indikators.Pattern(p).String() == name {
+				columns[j] = indikators.Pattern(p)
+			}
+		}
+		assert.NotEqual(t, indikators.Pattern(-1), columns[j], "%s should be a pattern", name)
+	}
+
+	out := indikators.PatternsArr(readCandles(t, "patterns_ohlcv.csv"), indikators.DefaultPatternSettings())
+	expected := records[1:]
+	assert.Len(t, expected, len(out), "they should be equal")
+	found := 0
+	for i := range expected {
+		for j, p := range columns {
+			want, err := strconv.Atoi(strings.TrimSuffix(expected[i][j], ".0"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want != 0 {
+				found++
+			}
+			if out[i][p] != want {
+				t.Errorf("bar %d %s: got %d, want %d", i, p, out[i][p], want)
+			}
+		}
+	}
+	assert.NotZero(t, found, "the patterns should be found")
+}
@@ -0,0 +1,67 @@
+package indikators
+
+// %B quantifies a security's price relative to the upper and lower
+// Bollinger Band. It is 1 when the price is at the upper band, 0 at the
+// lower band and 0.5 on the middle one. Readings above 1 or below 0 mean
+// the price is outside of the bands. Collapsed bands (no volatility)
+// return 0.5.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:bollinger_band_perce
+//  https://www.investopedia.com/terms/p/percentb.asp
+type PercentB struct {
+	bb *BBands
+}
+
+func NewPercentB(t MaType, n int64, upNStdDev, dnNStdDev float64) *PercentB {
+	return &PercentB{
+		bb: NewBBands(t, n, upNStdDev, dnNStdDev),
+	}
+}
+
+func (p *PercentB) Update(v float64) float64 {
+	u, _, l := p.bb.Update(v)
+
+	if !p.bb.Valid() {
+		return 0
+	}
+
+	return percentB(v, u, l)
+}
+
+func percentB(v, u, l float64) float64 {
+	diff := u - l
+	if almostZero(diff) {
+		return 0.5
+	}
+	return (v - l) / diff
+}
+
+func (p *PercentB) InitPeriod() int64 {
+	return p.bb.InitPeriod()
+}
+
+func (p *PercentB) Valid() bool {
+	return p.bb.Valid()
+}
+
+func (p *PercentB) state(s *stateCodec) {
+	s.sub(p.bb)
+}
+
+// %B quantifies a security's price relative to the upper and lower
+// Bollinger Band. It is 1 when the price is at the upper band, 0 at the
+// lower band and 0.5 on the middle one. Readings above 1 or below 0 mean
+// the price is outside of the bands. Collapsed bands (no volatility)
+// return 0.5.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:bollinger_band_perce
+//  https://www.investopedia.com/terms/p/percentb.asp
+func PercentBArr(t MaType, in []float64, n int64, upNStdDev, dnNStdDev float64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	p := NewPercentB(t, n, upNStdDev, dnNStdDev)
+	for i, v := range in {
+		out[i] = p.Update(v)
+	}
+	warmup(mode, p.InitPeriod(), out)
+
+	return out
+}
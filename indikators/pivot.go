@@ -0,0 +1,191 @@
+package indikators
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// PivotMethod selects the pivot point formulas
+type PivotMethod int
+
+const (
+	Classic PivotMethod = iota
+	Fibonacci
+	Camarilla
+	Woodie
+	DeMark
+)
+
+var pivotMethodNames = []string{"classic", "fibonacci", "camarilla", "woodie", "demark"}
+
+func (m PivotMethod) String() string {
+	if m < 0 || int(m) >= len(pivotMethodNames) {
+		return fmt.Sprintf("PivotMethod(%d)", int(m))
+	}
+	return pivotMethodNames[m]
+}
+
+// ParsePivotMethod converts a case insensitive name ("classic", ...) to
+// PivotMethod
+func ParsePivotMethod(s string) (PivotMethod, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for i, name := range pivotMethodNames {
+		if name == s {
+			return PivotMethod(i), nil
+		}
+	}
+	return 0, fmt.Errorf("indikators: unknown pivot method %q", s)
+}
+
+// PivotLevels are the pivot point and the resistance (R) and support (S)
+// levels around it. Levels a method does not define are NaN: classic and
+// Woodie stop at R3/S3, DeMark only has R1/S1.
+type PivotLevels struct {
+	P  float64 `json:"p"`
+	R1 float64 `json:"r1"`
+	R2 float64 `json:"r2"`
+	R3 float64 `json:"r3"`
+	R4 float64 `json:"r4"`
+	S1 float64 `json:"s1"`
+	S2 float64 `json:"s2"`
+	S3 float64 `json:"s3"`
+	S4 float64 `json:"s4"`
+}
+
+// Values in P, R1..R4, S1..S4 order
+func (l PivotLevels) Values() []float64 {
+	return []float64{l.P, l.R1, l.R2, l.R3, l.R4, l.S1, l.S2, l.S3, l.S4}
+}
+
+// PivotLevelsOf computes the levels from the previous session's candle.
+// open is the open of the current session, only Woodie uses it.
+//  https://www.investopedia.com/trading/using-pivot-points-for-predictions/
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:pivot_points
+func PivotLevelsOf(m PivotMethod, prev Candle, open float64) PivotLevels {
+	h, l, c := prev.High, prev.Low, prev.Close
+	r := h - l
+	nan := math.NaN()
+	out := PivotLevels{R4: nan, S4: nan}
+
+	switch m {
+	case Fibonacci:
+		out.P = (h + l + c) / 3.0
+		out.R1, out.S1 = out.P+0.382*r, out.P-0.382*r
+		out.R2, out.S2 = out.P+0.618*r, out.P-0.618*r
+		out.R3, out.S3 = out.P+r, out.P-r
+	case Camarilla:
+		out.P = (h + l + c) / 3.0
+		out.R1, out.S1 = c+r*1.1/12.0, c-r*1.1/12.0
+		out.R2, out.S2 = c+r*1.1/6.0, c-r*1.1/6.0
+		out.R3, out.S3 = c+r*1.1/4.0, c-r*1.1/4.0
+		out.R4, out.S4 = c+r*1.1/2.0, c-r*1.1/2.0
+	case DeMark:
+		var x float64
+		switch {
+		case c < prev.Open:
+			x = h + 2*l + c
+		case c > prev.Open:
+			x = 2*h + l + c
+		default:
+			x = h + l + 2*c
+		}
+		out = PivotLevels{P: x / 4.0, R1: x/2.0 - l, S1: x/2.0 - h, R2: nan, R3: nan, R4: nan, S2: nan, S3: nan, S4: nan}
+	default:
+		out.P = (h + l + c) / 3.0
+		if m == Woodie {
+			out.P = (h + l + 2*open) / 4.0
+		}
+		out.R1, out.S1 = 2*out.P-l, 2*out.P-h
+		out.R2, out.S2 = out.P+r, out.P-r
+		out.R3, out.S3 = h+2*(out.P-l), l-2*(h-out.P)
+	}
+
+	return out
+}
+
+// Pivots tracks daily sessions of a candle stream and returns the pivot
+// levels computed from the previous session. Sessions start at midnight
+// of the given location.
+type Pivots struct {
+	method  PivotMethod
+	loc     *time.Location
+	session time.Time
+	// current and previous session aggregated into one candle
+	cur    Candle
+	prev   Candle
+	levels PivotLevels
+	// completed sessions
+	done int64
+}
+
+// NewPivots creates pivots on daily sessions in loc, Jakarta when nil
+func NewPivots(m PivotMethod, loc *time.Location) *Pivots {
+	if loc == nil {
+		loc = Jakarta
+	}
+	return &Pivots{
+		method: m,
+		loc:    loc,
+	}
+}
+
+// Update returns the levels of the session c belongs to, zero until the
+// first session completed
+func (p *Pivots) Update(c Candle) PivotLevels {
+	session := startOfDay(c.Time, p.loc)
+	switch {
+	case p.session.IsZero():
+		p.session = session
+		p.cur = c
+	case !session.Equal(p.session):
+		p.session = session
+		p.prev = p.cur
+		p.cur = c
+		p.done++
+		p.levels = PivotLevelsOf(p.method, p.prev, c.Open)
+	default:
+		p.cur.High = max(p.cur.High, c.High)
+		p.cur.Low = min(p.cur.Low, c.Low)
+		p.cur.Close = c.Close
+		p.cur.Volume += c.Volume
+	}
+	return p.levels
+}
+
+// Previous session candle
+func (p *Pivots) Session() Candle {
+	return p.prev
+}
+
+func (p *Pivots) InitPeriod() int64 {
+	return 0
+}
+
+func (p *Pivots) Valid() bool {
+	return p.done > 0
+}
+
+func (p *Pivots) state(s *stateCodec) {
+	s.param(int64(p.method))
+	s.time(&p.session)
+	s.candle(&p.cur)
+	s.candle(&p.prev)
+	for _, v := range []*float64{&p.levels.P, &p.levels.R1, &p.levels.R2, &p.levels.R3, &p.levels.R4, &p.levels.S1, &p.levels.S2, &p.levels.S3, &p.levels.S4} {
+		s.float(v)
+	}
+	s.int(&p.done)
+}
+
+// PivotsArr returns the levels of every candle, see Pivots
+func PivotsArr(in []Candle, m PivotMethod, loc *time.Location) []PivotLevels {
+	out := make([]PivotLevels, len(in))
+
+	p := NewPivots(m, loc)
+	for i, c := range in {
+		out[i] = p.Update(c)
+	}
+
+	return out
+}
@@ -0,0 +1,107 @@
+package indikators_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPivotLevelsOf(t *testing.T) {
+	prev := indikators.Candle{Open: 100, High: 110, Low: 90, Close: 105}
+
+	tests := []struct {
+		name   string
+		method indikators.PivotMethod
+		want   indikators.PivotLevels
+	}{
+		{
+			name:   "Classic",
+			method: indikators.Classic,
+			want:   indikators.PivotLevels{P: 101.66666666666667, R1: 113.33333333333334, R2: 121.66666666666667, R3: 133.33333333333334, S1: 93.33333333333334, S2: 81.66666666666667, S3: 73.33333333333334},
+		},
+		{
+			name:   "Fibonacci",
+			method: indikators.Fibonacci,
+			want:   indikators.PivotLevels{P: 101.66666666666667, R1: 109.30666666666667, R2: 114.02666666666667, R3: 121.66666666666667, S1: 94.02666666666667, S2: 89.30666666666667, S3: 81.66666666666667},
+		},
+		{
+			name:   "Camarilla",
+			method: indikators.Camarilla,
+			want:   indikators.PivotLevels{P: 101.66666666666667, R1: 106.83333333333333, R2: 108.66666666666667, R3: 110.5, R4: 116, S1: 103.16666666666667, S2: 101.33333333333333, S3: 99.5, S4: 94},
+		},
+		{
+			name:   "Woodie uses the open of the new session",
+			method: indikators.Woodie,
+			want:   indikators.PivotLevels{P: 101, R1: 112, R2: 121, R3: 132, S1: 92, S2: 81, S3: 72},
+		},
+		{
+			name:   "DeMark on an up session",
+			method: indikators.DeMark,
+			want:   indikators.PivotLevels{P: 103.75, R1: 117.5, S1: 97.5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := indikators.PivotLevelsOf(test.method, prev, 102).Values()
+			for i, want := range test.want.Values() {
+				if want == 0 {
+					assert.True(t, math.IsNaN(got[i]), "level %d should be NaN", i)
+				} else {
+					assert.InDelta(t, want, got[i], 1e-9, "level %d should be equal", i)
+				}
+			}
+		})
+	}
+}
+
+func TestPivots(t *testing.T) {
+	utc := time.FixedZone("UTC", 0)
+	at := func(day, hour int) time.Time {
+		return time.Date(2023, 1, day, hour, 0, 0, 0, utc)
+	}
+	in := []indikators.Candle{
+		// 2 Jan 17:00 UTC is already 3 Jan in Jakarta
+		{Time: at(2, 10), Open: 100, High: 105, Low: 95, Close: 101},
+		{Time: at(2, 15), Open: 101, High: 110, Low: 90, Close: 105},
+		{Time: at(2, 17), Open: 105, High: 106, Low: 104, Close: 105},
+		{Time: at(2, 20), Open: 105, High: 107, Low: 103, Close: 106},
+	}
+
+	p := indikators.NewPivots(indikators.Classic, nil)
+	assert.Equal(t, indikators.PivotLevels{}, p.Update(in[0]), "they should be zero before a full session")
+	p.Update(in[1])
+	assert.False(t, p.Valid(), "it should be false")
+
+	got := p.Update(in[2])
+	assert.True(t, p.Valid(), "it should be true")
+	assert.InDelta(t, 101.66666666666667, got.P, 1e-9, "they should be equal")
+	assert.Equal(t, got.R1, p.Update(in[3]).R1, "they should not change within a session")
+
+	utcPivots := indikators.PivotsArr(in, indikators.Classic, utc)
+	assert.Equal(t, indikators.PivotLevels{}, utcPivots[3], "they should be one session in UTC")
+}
+
+func TestSupportResistance(t *testing.T) {
+	swings := []indikators.Swing{
+		{Index: 3, Value: 100, High: false},
+		{Index: 10, Value: 110, High: true},
+		{Index: 15, Value: 100.4, High: false},
+		{Index: 22, Value: 120, High: true},
+		{Index: 30, Value: 109.6, High: true},
+		{Index: 40, Value: 110.2, High: false},
+	}
+
+	levels := indikators.SupportResistance(swings, indikators.LevelSettings{Tolerance: 0.5, MinTouches: 2})
+
+	assert.Len(t, levels, 2, "they should be equal")
+	assert.InDelta(t, 100.2, levels[0].Price, 1e-9, "they should be equal")
+	assert.Equal(t, 2, levels[0].Lows, "they should be equal")
+	assert.Equal(t, [2]int{3, 15}, [2]int{levels[0].First, levels[0].Last}, "they should be equal")
+	assert.InDelta(t, 109.93333333333334, levels[1].Price, 1e-9, "they should be equal")
+	assert.Equal(t, [2]int{2, 1}, [2]int{levels[1].Highs, levels[1].Lows}, "they should be equal")
+	assert.Equal(t, [2]float64{109.6, 110.2}, [2]float64{levels[1].Low, levels[1].High}, "they should be equal")
+}
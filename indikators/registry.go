@@ -0,0 +1,740 @@
+package indikators
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Fatiri/areuy/json"
+)
+
+// Builder creates an indicator from its parameters, see Build
+type Builder func(p json.Object) (Indicator, error)
+
+type registration struct {
+	build Builder
+	keys  map[string]bool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]registration{}
+)
+
+// Register makes an indicator available to Build under name, keys lists
+// the parameters b reads. Registering an existing name replaces the
+// previous builder.
+func Register(name string, b Builder, keys ...string) {
+	r := registration{build: b, keys: map[string]bool{}}
+	for _, key := range keys {
+		r.keys[key] = true
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(name)] = r
+}
+
+// Registered lists the names accepted by Build
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build creates an indicator from a parameter map, the "type" key selects
+// the indicator, e.g.
+//
+//	{"type":"bbands","ma":"ema","n":20,"up":2,"dn":2}
+//
+// Single value indicators read the candle field named by "src" (close by
+// default). A parameter the indicator does not know or of the wrong type
+// is an error, "name" is left to the caller to label the indicator.
+func Build(p json.Object) (Indicator, error) {
+	name := strings.ToLower(p.GetString("type"))
+	registryMu.RLock()
+	r, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("indikators: unknown indicator type %q", name)
+	}
+
+	keys := make([]string, 0, len(p))
+	for key := range p {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key != "type" && key != "name" && !r.keys[key] {
+			return nil, fmt.Errorf("indikators: unknown parameter %q for %s", key, name)
+		}
+	}
+	return r.build(p)
+}
+
+// BuildJSON is Build for a JSON encoded parameter object
+func BuildJSON(data []byte) (Indicator, error) {
+	p, err := json.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return Build(p)
+}
+
+func init() {
+	for _, t := range []MaType{SMA, EMA, WMA, DEMA, TEMA, TRIMA, KAMA} {
+		t := t
+		Register(t.String(), func(p json.Object) (Indicator, error) {
+			n, err := paramPeriod(p, "n", 30)
+			if err != nil {
+				return nil, err
+			}
+			return valueIndicator(p, NewMa(t, n), t.String())
+		}, "n", "src")
+	}
+	Register("ma", func(p json.Object) (Indicator, error) {
+		t, err := paramMaType(p, "ma", SMA)
+		if err != nil {
+			return nil, err
+		}
+		n, err := paramPeriod(p, "n", 30)
+		if err != nil {
+			return nil, err
+		}
+		return valueIndicator(p, NewMa(t, n), "ma")
+	}, "ma", "n", "src")
+	Register("t3", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 5)
+		if err != nil {
+			return nil, err
+		}
+		vfactor, err := paramFloat(p, "vfactor", T3VFactor)
+		if err != nil {
+			return nil, err
+		}
+		return valueIndicator(p, NewT3Ma(n, vfactor), "t3")
+	}, "n", "vfactor", "src")
+	Register("mama", func(p json.Object) (Indicator, error) {
+		src, err := paramSource(p)
+		if err != nil {
+			return nil, err
+		}
+		fast, err := paramFloat(p, "fast", MamaFastLimit)
+		if err != nil {
+			return nil, err
+		}
+		slow, err := paramFloat(p, "slow", MamaSlowLimit)
+		if err != nil {
+			return nil, err
+		}
+		m := NewMama(fast, slow)
+		return &funcIndicator{
+			periodic: m,
+			outputs:  []string{"mama", "fama"},
+			update: func(c Candle) []float64 {
+				mama, fama := m.Update(src.Value(c))
+				return []float64{mama, fama}
+			},
+		}, nil
+	}, "src", "fast", "slow")
+	Register("rsi", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 14)
+		if err != nil {
+			return nil, err
+		}
+		return valueIndicator(p, NewRsi(n), "rsi")
+	}, "n", "src")
+	Register("var", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 5)
+		if err != nil {
+			return nil, err
+		}
+		return valueIndicator(p, NewVar(n), "var")
+	}, "n", "src")
+	Register("stddev", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 5)
+		if err != nil {
+			return nil, err
+		}
+		return valueIndicator(p, NewStdDev(n), "stddev")
+	}, "n", "src")
+	Register("bbands", func(p json.Object) (Indicator, error) {
+		src, err := paramSource(p)
+		if err != nil {
+			return nil, err
+		}
+		t, n, up, dn, err := bbandsParams(p)
+		if err != nil {
+			return nil, err
+		}
+		b := NewBBands(t, n, up, dn)
+		return &funcIndicator{
+			periodic: b,
+			outputs:  []string{"upper", "middle", "lower"},
+			update: func(c Candle) []float64 {
+				u, m, l := b.Update(src.Value(c))
+				return []float64{u, m, l}
+			},
+		}, nil
+	}, "src", "ma", "n", "up", "dn")
+	Register("percentb", func(p json.Object) (Indicator, error) {
+		src, err := paramSource(p)
+		if err != nil {
+			return nil, err
+		}
+		t, n, up, dn, err := bbandsParams(p)
+		if err != nil {
+			return nil, err
+		}
+		return newValueIndicator(NewPercentB(t, n, up, dn), src, "percent_b"), nil
+	}, "src", "ma", "n", "up", "dn")
+	Register("bandwidth", func(p json.Object) (Indicator, error) {
+		src, err := paramSource(p)
+		if err != nil {
+			return nil, err
+		}
+		t, n, up, dn, err := bbandsParams(p)
+		if err != nil {
+			return nil, err
+		}
+		return newValueIndicator(NewBandWidth(t, n, up, dn), src, "bandwidth"), nil
+	}, "src", "ma", "n", "up", "dn")
+	Register("squeeze", func(p json.Object) (Indicator, error) {
+		src, err := paramSource(p)
+		if err != nil {
+			return nil, err
+		}
+		t, n, up, dn, err := bbandsParams(p)
+		if err != nil {
+			return nil, err
+		}
+		lookback, err := paramPeriod(p, "lookback", 125)
+		if err != nil {
+			return nil, err
+		}
+		s := NewSqueeze(t, n, up, dn, lookback)
+		return &funcIndicator{
+			periodic: s,
+			outputs:  []string{"bandwidth", "squeeze"},
+			update: func(c Candle) []float64 {
+				bw, sq := s.Update(src.Value(c))
+				if sq {
+					return []float64{bw, 1}
+				}
+				return []float64{bw, 0}
+			},
+		}, nil
+	}, "src", "ma", "n", "up", "dn", "lookback")
+	Register("macd", func(p json.Object) (Indicator, error) {
+		return macdIndicator(p, EMA, EMA, EMA)
+	}, "src", "fast", "slow", "signal")
+	Register("macdext", func(p json.Object) (Indicator, error) {
+		fastT, err := paramMaType(p, "fastma", EMA)
+		if err != nil {
+			return nil, err
+		}
+		slowT, err := paramMaType(p, "slowma", EMA)
+		if err != nil {
+			return nil, err
+		}
+		signalT, err := paramMaType(p, "signalma", EMA)
+		if err != nil {
+			return nil, err
+		}
+		return macdIndicator(p, fastT, slowT, signalT)
+	}, "src", "fast", "slow", "signal", "fastma", "slowma", "signalma")
+	Register("stochrsi", func(p json.Object) (Indicator, error) {
+		src, err := paramSource(p)
+		if err != nil {
+			return nil, err
+		}
+		n, err := paramPeriod(p, "n", 14)
+		if err != nil {
+			return nil, err
+		}
+		fastK, err := paramPeriod(p, "fastk", 5)
+		if err != nil {
+			return nil, err
+		}
+		fastD, err := paramPeriod(p, "fastd", 3)
+		if err != nil {
+			return nil, err
+		}
+		t, err := paramMaType(p, "ma", SMA)
+		if err != nil {
+			return nil, err
+		}
+		s := NewStochRsi(n, fastK, t, fastD)
+		return &funcIndicator{
+			periodic: s,
+			outputs:  []string{"k", "d"},
+			update: func(c Candle) []float64 {
+				k, d := s.Update(src.Value(c))
+				return []float64{k, d}
+			},
+		}, nil
+	}, "src", "n", "fastk", "fastd", "ma")
+	Register("linreg", func(p json.Object) (Indicator, error) {
+		src, err := paramSource(p)
+		if err != nil {
+			return nil, err
+		}
+		n, err := paramPeriod(p, "n", 14)
+		if err != nil {
+			return nil, err
+		}
+		l := NewLinReg(n)
+		return &funcIndicator{
+			periodic: l,
+			outputs:  []string{"linreg", "slope", "intercept", "angle", "forecast", "r2"},
+			update: func(c Candle) []float64 {
+				v := l.Update(src.Value(c))
+				return []float64{v, l.Slope(), l.Intercept(), l.Angle(), l.Forecast(), l.R2()}
+			},
+		}, nil
+	}, "src", "n")
+	Register("zscore", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 20)
+		if err != nil {
+			return nil, err
+		}
+		return valueIndicator(p, NewZScore(n), "zscore")
+	}, "n", "src")
+	// x and y default to the high and low of one candle, feed two series
+	// with PairIndicator.Update2, e.g. {"type":"correl","x":"close","y":"close"}
+	Register("correl", func(p json.Object) (Indicator, error) {
+		x, y, err := pairSources(p)
+		if err != nil {
+			return nil, err
+		}
+		n, err := paramPeriod(p, "n", 30)
+		if err != nil {
+			return nil, err
+		}
+		c := NewCorrel(n)
+		return &pairIndicator{periodic: c, output: "correl", x: x, y: y, update: c.Update}, nil
+	}, "n", "x", "y")
+	Register("beta", func(p json.Object) (Indicator, error) {
+		x, y, err := pairSources(p)
+		if err != nil {
+			return nil, err
+		}
+		n, err := paramPeriod(p, "n", 5)
+		if err != nil {
+			return nil, err
+		}
+		b := NewBeta(n)
+		return &pairIndicator{periodic: b, output: "beta", x: x, y: y, update: b.Update}, nil
+	}, "n", "x", "y")
+	Register("trange", func(p json.Object) (Indicator, error) {
+		return newCandleIndicator(NewTRange(), "trange"), nil
+	})
+	Register("atr", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 14)
+		if err != nil {
+			return nil, err
+		}
+		return newCandleIndicator(NewAtr(n), "atr"), nil
+	}, "n")
+	Register("adx", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 14)
+		if err != nil {
+			return nil, err
+		}
+		return newCandleIndicator(NewAdx(n), "adx"), nil
+	}, "n")
+	Register("dmi", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 14)
+		if err != nil {
+			return nil, err
+		}
+		d := NewDmi(n)
+		return &funcIndicator{
+			periodic: d,
+			outputs:  []string{"plus_di", "minus_di"},
+			update: func(c Candle) []float64 {
+				pdi, mdi := d.Update(c)
+				return []float64{pdi, mdi}
+			},
+		}, nil
+	}, "n")
+	Register("stoch", func(p json.Object) (Indicator, error) {
+		fastK, err := paramPeriod(p, "fastk", 5)
+		if err != nil {
+			return nil, err
+		}
+		slowK, err := paramPeriod(p, "slowk", 3)
+		if err != nil {
+			return nil, err
+		}
+		slowKT, err := paramMaType(p, "slowkma", SMA)
+		if err != nil {
+			return nil, err
+		}
+		slowD, err := paramPeriod(p, "slowd", 3)
+		if err != nil {
+			return nil, err
+		}
+		slowDT, err := paramMaType(p, "slowdma", SMA)
+		if err != nil {
+			return nil, err
+		}
+		s := NewStoch(fastK, slowKT, slowK, slowDT, slowD)
+		return &funcIndicator{
+			periodic: s,
+			outputs:  []string{"k", "d"},
+			update: func(c Candle) []float64 {
+				k, d := s.Update(c)
+				return []float64{k, d}
+			},
+		}, nil
+	}, "fastk", "slowk", "slowkma", "slowd", "slowdma")
+	Register("cci", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 14)
+		if err != nil {
+			return nil, err
+		}
+		return newCandleIndicator(NewCci(n), "cci"), nil
+	}, "n")
+	Register("willr", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 14)
+		if err != nil {
+			return nil, err
+		}
+		return newCandleIndicator(NewWillR(n), "willr"), nil
+	}, "n")
+	Register("obv", func(p json.Object) (Indicator, error) {
+		return newCandleIndicator(NewObv(), "obv"), nil
+	})
+	Register("ad", func(p json.Object) (Indicator, error) {
+		return newCandleIndicator(NewAd(), "ad"), nil
+	})
+	Register("adosc", func(p json.Object) (Indicator, error) {
+		fast, err := paramPeriod(p, "fast", 3)
+		if err != nil {
+			return nil, err
+		}
+		slow, err := paramPeriod(p, "slow", 10)
+		if err != nil {
+			return nil, err
+		}
+		return newCandleIndicator(NewAdOsc(fast, slow), "adosc"), nil
+	}, "fast", "slow")
+	Register("mfi", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 14)
+		if err != nil {
+			return nil, err
+		}
+		return newCandleIndicator(NewMfi(n), "mfi"), nil
+	}, "n")
+	Register("keltner", func(p json.Object) (Indicator, error) {
+		t, err := paramMaType(p, "ma", EMA)
+		if err != nil {
+			return nil, err
+		}
+		n, err := paramPeriod(p, "n", 20)
+		if err != nil {
+			return nil, err
+		}
+		atrN, err := paramPeriod(p, "atr", 10)
+		if err != nil {
+			return nil, err
+		}
+		mult, err := paramFloat(p, "mult", 2)
+		if err != nil {
+			return nil, err
+		}
+		k := NewKeltner(t, n, atrN, mult)
+		return &funcIndicator{
+			periodic: k,
+			outputs:  []string{"upper", "middle", "lower"},
+			update: func(c Candle) []float64 {
+				u, m, l := k.Update(c)
+				return []float64{u, m, l}
+			},
+		}, nil
+	}, "ma", "n", "atr", "mult")
+	Register("donchian", func(p json.Object) (Indicator, error) {
+		n, err := paramPeriod(p, "n", 20)
+		if err != nil {
+			return nil, err
+		}
+		d := NewDonchian(n)
+		return &funcIndicator{
+			periodic: d,
+			outputs:  []string{"upper", "middle", "lower"},
+			update: func(c Candle) []float64 {
+				u, m, l := d.Update(c)
+				return []float64{u, m, l}
+			},
+		}, nil
+	}, "n")
+	Register("ichimoku", func(p json.Object) (Indicator, error) {
+		tenkan, err := paramPeriod(p, "tenkan", 9)
+		if err != nil {
+			return nil, err
+		}
+		kijun, err := paramPeriod(p, "kijun", 26)
+		if err != nil {
+			return nil, err
+		}
+		senkou, err := paramPeriod(p, "senkou", 52)
+		if err != nil {
+			return nil, err
+		}
+		disp, err := paramPeriod(p, "disp", 26)
+		if err != nil {
+			return nil, err
+		}
+		ich := NewIchimoku(tenkan, kijun, senkou, disp)
+		return &funcIndicator{
+			periodic: ich,
+			outputs:  []string{"tenkan", "kijun", "senkou_a", "senkou_b", "chikou"},
+			update: func(c Candle) []float64 {
+				t, k, a, b, ch := ich.Update(c)
+				return []float64{t, k, a, b, ch}
+			},
+		}, nil
+	}, "tenkan", "kijun", "senkou", "disp")
+	Register("sar", func(p json.Object) (Indicator, error) {
+		accel, err := paramFloat(p, "accel", 0.02)
+		if err != nil {
+			return nil, err
+		}
+		if accel <= 0 {
+			return nil, fmt.Errorf("indikators: accel must be positive, got %v", accel)
+		}
+		max, err := paramFloat(p, "max", 0.2)
+		if err != nil {
+			return nil, err
+		}
+		if max <= 0 {
+			return nil, fmt.Errorf("indikators: max must be positive, got %v", max)
+		}
+		return newCandleIndicator(NewSar(accel, max), "sar"), nil
+	}, "accel", "max")
+	Register("patterns", func(p json.Object) (Indicator, error) {
+		s := DefaultPatternSettings()
+		var err error
+		if s.Penetration, err = paramFloat(p, "penetration", s.Penetration); err != nil {
+			return nil, err
+		}
+		pt := NewPatterns(s)
+		return &funcIndicator{
+			periodic: pt,
+			outputs:  append([]string{}, patternNames...),
+			update: func(c Candle) []float64 {
+				scores := pt.Update(c)
+				out := make([]float64, len(scores))
+				for i, v := range scores {
+					out[i] = float64(v)
+				}
+				return out
+			},
+		}, nil
+	}, "penetration")
+	Register("vwap", func(p json.Object) (Indicator, error) {
+		// "tz" selects the session timezone, an empty one never resets
+		loc := Jakarta
+		if p.Has("tz") {
+			tz, err := paramString(p, "tz", "")
+			if err != nil {
+				return nil, err
+			}
+			loc = nil
+			if tz != "" {
+				if loc, err = time.LoadLocation(tz); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return newCandleIndicator(NewVwap(loc), "vwap"), nil
+	}, "tz")
+	Register("pivots", func(p json.Object) (Indicator, error) {
+		m := Classic
+		if p.Has("method") {
+			method, err := paramString(p, "method", "")
+			if err != nil {
+				return nil, err
+			}
+			if m, err = ParsePivotMethod(method); err != nil {
+				return nil, err
+			}
+		}
+		tz, err := paramString(p, "tz", "")
+		if err != nil {
+			return nil, err
+		}
+		loc := Jakarta
+		if tz != "" {
+			if loc, err = time.LoadLocation(tz); err != nil {
+				return nil, err
+			}
+		}
+		pv := NewPivots(m, loc)
+		return &funcIndicator{
+			periodic: pv,
+			outputs:  []string{"p", "r1", "r2", "r3", "r4", "s1", "s2", "s3", "s4"},
+			update: func(c Candle) []float64 {
+				return pv.Update(c).Values()
+			},
+		}, nil
+	}, "method", "tz")
+}
+
+func macdIndicator(p json.Object, fastT, slowT, signalT MaType) (Indicator, error) {
+	src, err := paramSource(p)
+	if err != nil {
+		return nil, err
+	}
+	fast, err := paramPeriod(p, "fast", 12)
+	if err != nil {
+		return nil, err
+	}
+	slow, err := paramPeriod(p, "slow", 26)
+	if err != nil {
+		return nil, err
+	}
+	signal, err := paramPeriod(p, "signal", 9)
+	if err != nil {
+		return nil, err
+	}
+	m := NewMacdExt(fastT, fast, slowT, slow, signalT, signal)
+	return &funcIndicator{
+		periodic: m,
+		outputs:  []string{"macd", "signal", "hist"},
+		update: func(c Candle) []float64 {
+			macd, sig, hist := m.Update(src.Value(c))
+			return []float64{macd, sig, hist}
+		},
+	}, nil
+}
+
+// pairSources reads the "x" and "y" sources of the two series indicators,
+// high and low by default like TA-Lib
+func pairSources(p json.Object) (Source, Source, error) {
+	x, err := paramSourceKey(p, "x", High)
+	if err != nil {
+		return 0, 0, err
+	}
+	y, err := paramSourceKey(p, "y", Low)
+	if err != nil {
+		return 0, 0, err
+	}
+	return x, y, nil
+}
+
+// bbandsParams reads the moving average type, period and band multipliers
+// shared by the Bollinger Bands family
+func bbandsParams(p json.Object) (MaType, int64, float64, float64, error) {
+	t, err := paramMaType(p, "ma", SMA)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	n, err := paramPeriod(p, "n", 20)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	up, err := paramFloat(p, "up", 2)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	dn, err := paramFloat(p, "dn", 2)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return t, n, up, dn, nil
+}
+
+func valueIndicator(p json.Object, u maUpdater, output string) (Indicator, error) {
+	src, err := paramSource(p)
+	if err != nil {
+		return nil, err
+	}
+	return newValueIndicator(u, src, output), nil
+}
+
+func paramSource(p json.Object) (Source, error) {
+	return paramSourceKey(p, "src", Close)
+}
+
+func paramSourceKey(p json.Object, key string, def Source) (Source, error) {
+	if !p.Has(key) {
+		return def, nil
+	}
+	v, err := paramString(p, key, "")
+	if err != nil {
+		return 0, err
+	}
+	return ParseSource(v)
+}
+
+func paramMaType(p json.Object, key string, def MaType) (MaType, error) {
+	if !p.Has(key) {
+		return def, nil
+	}
+	v, err := paramString(p, key, "")
+	if err != nil {
+		return 0, err
+	}
+	return ParseMaType(v)
+}
+
+// paramPeriod reads a positive integer, a number with a fraction or a
+// value of another type is an error
+func paramPeriod(p json.Object, key string, def int64) (int64, error) {
+	n := def
+	if p.Has(key) {
+		switch v := p[key].(type) {
+		case int:
+			n = int64(v)
+		case int64:
+			n = v
+		case float64:
+			if v != math.Trunc(v) {
+				return 0, fmt.Errorf("indikators: %s must be an integer, got %v", key, v)
+			}
+			n = int64(v)
+		default:
+			return 0, fmt.Errorf("indikators: %s must be an integer, got %T", key, v)
+		}
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("indikators: %s must be positive, got %d", key, n)
+	}
+	return n, nil
+}
+
+func paramFloat(p json.Object, key string, def float64) (float64, error) {
+	if !p.Has(key) {
+		return def, nil
+	}
+	switch v := p[key].(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("indikators: %s must be a number, got %T", key, v)
+	}
+}
+
+func paramString(p json.Object, key string, def string) (string, error) {
+	if !p.Has(key) {
+		return def, nil
+	}
+	v, ok := p[key].(string)
+	if !ok {
+		return "", fmt.Errorf("indikators: %s must be a string, got %T", key, p[key])
+	}
+	return v, nil
+}
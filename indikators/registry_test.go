@@ -0,0 +1,82 @@
+package indikators_test
+
+import (
+	"testing"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairIndicator(t *testing.T) {
+	in := readOHLCV(t)
+	// a second series: the golden input shifted by 7 bars
+	other := append(append([]indikators.Candle{}, in[len(in)-7:]...), in[:len(in)-7]...)
+
+	tests := []struct {
+		name string
+		p    json.Object
+		want func(x, y float64) float64
+	}{
+		{name: "Correl", p: json.Object{"type": "correl", "n": 10, "x": "close", "y": "close"}, want: indikators.NewCorrel(10).Update},
+		{name: "Beta", p: json.Object{"type": "beta", "n": 5, "x": "close", "y": "open"}, want: indikators.NewBeta(5).Update},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			x, err := indikators.Build(test.p)
+			assert.NoError(t, err, "they should be no error")
+			pair, ok := x.(indikators.PairIndicator)
+			assert.True(t, ok, "it should be a PairIndicator")
+
+			for i := range in {
+				src, _ := indikators.ParseSource(test.p.GetString("y"))
+				want := test.want(in[i].Close, src.Value(other[i]))
+				assert.Equal(t, []float64{want}, pair.Update2(in[i], other[i]), "bar %d should be equal", i)
+			}
+		})
+	}
+
+	t.Run("Update reads both values from one candle", func(t *testing.T) {
+		x, _ := indikators.Build(json.Object{"type": "correl", "n": 30})
+		c := indikators.NewCorrel(30)
+		for _, cd := range in {
+			assert.Equal(t, []float64{c.Update(cd.High, cd.Low)}, x.Update(cd), "they should be equal")
+		}
+	})
+}
+
+func TestBuildParams(t *testing.T) {
+	tests := []struct {
+		name string
+		p    json.Object
+		err  string
+	}{
+		{name: "Defaults", p: json.Object{"type": "bbands"}},
+		{name: "Integer and float values", p: json.Object{"type": "bbands", "n": 20, "up": 2.5, "dn": 1}},
+		{name: "Whole float period", p: json.Object{"type": "rsi", "n": 14.0}},
+		{name: "Label", p: json.Object{"type": "rsi", "name": "rsi_fast", "n": 7}},
+		{name: "Unknown type", p: json.Object{"type": "nope"}, err: `indikators: unknown indicator type "nope"`},
+		{name: "Unknown parameter", p: json.Object{"type": "rsi", "period": 14}, err: `indikators: unknown parameter "period" for rsi`},
+		{name: "Parameter of another indicator", p: json.Object{"type": "ad", "n": 14}, err: `indikators: unknown parameter "n" for ad`},
+		{name: "String period", p: json.Object{"type": "rsi", "n": "14"}, err: "indikators: n must be an integer, got string"},
+		{name: "Fractional period", p: json.Object{"type": "rsi", "n": 14.5}, err: "indikators: n must be an integer, got 14.5"},
+		{name: "Non positive period", p: json.Object{"type": "rsi", "n": 0}, err: "indikators: n must be positive, got 0"},
+		{name: "String multiplier", p: json.Object{"type": "bbands", "up": "2"}, err: "indikators: up must be a number, got string"},
+		{name: "Boolean factor", p: json.Object{"type": "t3", "vfactor": true}, err: "indikators: vfactor must be a number, got bool"},
+		{name: "Numeric source", p: json.Object{"type": "sma", "src": 1.0}, err: "indikators: src must be a string, got float64"},
+		{name: "Numeric ma type", p: json.Object{"type": "keltner", "ma": 1}, err: "indikators: ma must be a string, got int"},
+		{name: "Numeric timezone", p: json.Object{"type": "vwap", "tz": 7.0}, err: "indikators: tz must be a string, got float64"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := indikators.Build(test.p)
+			if test.err == "" {
+				assert.NoError(t, err, "they should be no error")
+			} else {
+				assert.EqualError(t, err, test.err, "they should be equal")
+			}
+		})
+	}
+}
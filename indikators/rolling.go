@@ -0,0 +1,108 @@
+package indikators
+
+// Rolling keeps the minimum or maximum of the last n values using a
+// monotonic deque. Every value is pushed and popped at most once, so
+// Update is amortised O(1) instead of the O(n) scan of CBuf.Min/Max. The
+// deque holds the sequence number of each candidate to expire the ones
+// that slid out of the window.
+type Rolling struct {
+	n    int64
+	max  bool
+	seq  []int64
+	vals []float64
+	head int64
+	cnt  int64
+	sz   int64
+}
+
+func NewRollingMin(n int64) *Rolling {
+	return newRolling(n, false)
+}
+
+func NewRollingMax(n int64) *Rolling {
+	return newRolling(n, true)
+}
+
+func newRolling(n int64, max bool) *Rolling {
+	return &Rolling{
+		n:    n,
+		max:  max,
+		seq:  make([]int64, n),
+		vals: make([]float64, n),
+		head: 0,
+		cnt:  0,
+		sz:   0,
+	}
+}
+
+// Update appends v and returns the extreme of the last n values
+func (r *Rolling) Update(v float64) float64 {
+	// expire the front once it slid out of the window
+	if r.cnt > 0 && r.seq[r.head] <= r.sz-r.n {
+		r.head = (r.head + 1) % r.n
+		r.cnt--
+	}
+
+	// drop candidates that can never be the extreme again
+	for r.cnt > 0 {
+		back := (r.head + r.cnt - 1) % r.n
+		if r.max && r.vals[back] > v || !r.max && r.vals[back] < v {
+			break
+		}
+		r.cnt--
+	}
+
+	back := (r.head + r.cnt) % r.n
+	r.seq[back] = r.sz
+	r.vals[back] = v
+	r.cnt++
+	r.sz++
+
+	return r.vals[r.head]
+}
+
+// Value is the extreme of the window, 0 before the first update
+func (r *Rolling) Value() float64 {
+	if r.cnt == 0 {
+		return 0
+	}
+	return r.vals[r.head]
+}
+
+// Seq is the sequence number (0 based count of updates) of the extreme,
+// the newest one when it is repeated in the window
+func (r *Rolling) Seq() int64 {
+	if r.cnt == 0 {
+		return 0
+	}
+	return r.seq[r.head]
+}
+
+// Number of values appended
+func (r *Rolling) Size() int64 {
+	return r.sz
+}
+
+func (r *Rolling) InitPeriod() int64 {
+	return r.n - 1
+}
+
+func (r *Rolling) Valid() bool {
+	return r.sz > r.InitPeriod()
+}
+
+func (r *Rolling) state(s *stateCodec) {
+	s.param(r.n)
+	var max int64
+	if r.max {
+		max = 1
+	}
+	s.param(max)
+	for i := range r.seq {
+		s.int(&r.seq[i])
+	}
+	s.floats(r.vals)
+	s.int(&r.head)
+	s.int(&r.cnt)
+	s.int(&r.sz)
+}
@@ -0,0 +1,60 @@
+package indikators_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+// bruteExtreme scans the window ending at i, the newest index wins ties
+func bruteExtreme(in []float64, i, n int, max bool) (int, float64) {
+	idx := i
+	for j := i - 1; j >= 0 && j > i-n; j-- {
+		if max && in[j] > in[idx] || !max && in[j] < in[idx] {
+			idx = j
+		}
+	}
+	return idx, in[idx]
+}
+
+func TestRolling(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	random := make([]float64, 1000)
+	ties := make([]float64, 1000)
+	for i := range random {
+		random[i] = rnd.NormFloat64() * 100
+		ties[i] = float64(rnd.Intn(4))
+	}
+
+	tests := []struct {
+		name string
+		in   []float64
+	}{
+		{name: "Random", in: random},
+		{name: "Ties", in: ties},
+		{name: "Increasing", in: []float64{1, 2, 3, 4, 5, 6, 7, 8}},
+		{name: "Decreasing", in: []float64{8, 7, 6, 5, 4, 3, 2, 1}},
+	}
+
+	for _, test := range tests {
+		for _, n := range []int{1, 2, 3, 14, 50} {
+			for _, max := range []bool{false, true} {
+				r := indikators.NewRollingMin(int64(n))
+				if max {
+					r = indikators.NewRollingMax(int64(n))
+				}
+				for i, v := range test.in {
+					got := r.Update(v)
+					idx, want := bruteExtreme(test.in, i, n, max)
+					if got != want || r.Value() != want || r.Seq() != int64(idx) {
+						t.Fatalf("%s n=%d max=%v bar %d: got %v at %d, want %v at %d", test.name, n, max, i, got, r.Seq(), want, idx)
+					}
+					assert.Equal(t, i >= n-1, r.Valid(), "they should be equal")
+				}
+				assert.Equal(t, int64(len(test.in)), r.Size(), "they should be equal")
+			}
+		}
+	}
+}
@@ -57,13 +57,22 @@ func (r *Rsi) InitPeriod() int64 {
 func (r *Rsi) Valid() bool {
 	return r.sz > r.InitPeriod()
 }
-func RsiArr(in []float64, n int64) []float64 {
+
+func (r *Rsi) state(s *stateCodec) {
+	s.param(r.n)
+	s.sub(r.up)
+	s.sub(r.dn)
+	s.float(&r.prevC)
+	s.int(&r.sz)
+}
+func RsiArr(in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	r := NewRsi(n)
 	for i, v := range in {
 		out[i] = r.Update(v)
 	}
+	warmup(mode, r.InitPeriod(), out)
 
 	return out
 }
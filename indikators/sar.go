@@ -0,0 +1,145 @@
+package indikators
+
+// Developed by J. Welles Wilder, the Parabolic SAR sets trailing price
+// stops for long or short positions. Also referred to as the stop-and-
+// reversal indicator (SAR stands for "stop and reverse"), Parabolic SAR
+// is more popular for setting stops than for establishing direction or
+// trend. Wilder recommended establishing the trend first and then
+// trading with Parabolic SAR in the direction of the trend. The
+// acceleration factor starts at accel, grows by accel every time a new
+// extreme point is made and is capped at max (0.02 and 0.2 by default).
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:parabolic_sar
+//  https://www.investopedia.com/terms/p/parabolicindicator.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/sar
+type Sar struct {
+	accel  float64
+	max    float64
+	af     float64
+	isLong bool
+	sar    float64
+	ep     float64
+	prevH  float64
+	prevL  float64
+	sz     int64
+}
+
+func NewSar(accel, max float64) *Sar {
+	if accel > max {
+		accel = max
+	}
+	return &Sar{
+		accel: accel,
+		max:   max,
+		af:    accel,
+		sz:    0,
+	}
+}
+
+func (s *Sar) Update(c Candle) float64 {
+	s.sz++
+
+	if s.sz == 1 {
+		s.prevH, s.prevL = c.High, c.Low
+		return 0
+	}
+
+	prevH, prevL := s.prevH, s.prevL
+	if s.sz == 2 {
+		// initial direction from the directional movement of the first
+		// two bars, short when the low moved down more than the high up
+		diffP := c.High - prevH
+		diffM := prevL - c.Low
+		s.isLong = !(diffM > 0 && diffP < diffM)
+		if s.isLong {
+			s.ep, s.sar = c.High, prevL
+		} else {
+			s.ep, s.sar = c.Low, prevH
+		}
+		prevH, prevL = c.High, c.Low
+	}
+	s.prevH, s.prevL = c.High, c.Low
+
+	var out float64
+	if s.isLong {
+		if c.Low <= s.sar {
+			// switch to short
+			s.isLong = false
+			out = max(max(s.ep, prevH), c.High)
+			s.af = s.accel
+			s.ep = c.Low
+			s.sar = out + s.af*(s.ep-out)
+			s.sar = max(max(s.sar, prevH), c.High)
+		} else {
+			out = s.sar
+			if c.High > s.ep {
+				s.ep = c.High
+				s.af = min(s.af+s.accel, s.max)
+			}
+			s.sar = s.sar + s.af*(s.ep-s.sar)
+			s.sar = min(min(s.sar, prevL), c.Low)
+		}
+	} else {
+		if c.High >= s.sar {
+			// switch to long
+			s.isLong = true
+			out = min(min(s.ep, prevL), c.Low)
+			s.af = s.accel
+			s.ep = c.High
+			s.sar = out + s.af*(s.ep-out)
+			s.sar = min(min(s.sar, prevL), c.Low)
+		} else {
+			out = s.sar
+			if c.Low < s.ep {
+				s.ep = c.Low
+				s.af = min(s.af+s.accel, s.max)
+			}
+			s.sar = s.sar + s.af*(s.ep-s.sar)
+			s.sar = max(max(s.sar, prevH), c.High)
+		}
+	}
+
+	return out
+}
+
+func (s *Sar) InitPeriod() int64 {
+	return 1
+}
+
+func (s *Sar) Valid() bool {
+	return s.sz > s.InitPeriod()
+}
+
+func (s *Sar) state(st *stateCodec) {
+	st.paramFloat(s.accel)
+	st.paramFloat(s.max)
+	st.float(&s.af)
+	st.bool(&s.isLong)
+	st.float(&s.sar)
+	st.float(&s.ep)
+	st.float(&s.prevH)
+	st.float(&s.prevL)
+	st.int(&s.sz)
+}
+
+// Developed by J. Welles Wilder, the Parabolic SAR sets trailing price
+// stops for long or short positions. Also referred to as the stop-and-
+// reversal indicator (SAR stands for "stop and reverse"), Parabolic SAR
+// is more popular for setting stops than for establishing direction or
+// trend. Wilder recommended establishing the trend first and then
+// trading with Parabolic SAR in the direction of the trend. The
+// acceleration factor starts at accel, grows by accel every time a new
+// extreme point is made and is capped at max (0.02 and 0.2 by default).
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:parabolic_sar
+//  https://www.investopedia.com/terms/p/parabolicindicator.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/sar
+func SarArr(in []Candle, accel, max float64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	s := NewSar(accel, max)
+	for i, v := range in {
+		out[i] = s.Update(v)
+	}
+	warmup(mode, s.InitPeriod(), out)
+
+	return out
+}
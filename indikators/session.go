@@ -0,0 +1,23 @@
+package indikators
+
+import (
+	"time"
+)
+
+// Jakarta is the default session timezone (WIB, UTC+7). It falls back to a
+// fixed +07:00 zone when the tz database is not available on the host.
+var Jakarta = loadLocation("Asia/Jakarta", 7*60*60)
+
+func loadLocation(name string, offset int) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone(name, offset)
+	}
+	return loc
+}
+
+// Start of the day t belongs to, in the given location
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
@@ -47,6 +47,13 @@ func (s *Sma) Valid() bool {
 	return s.sz > s.InitPeriod()
 }
 
+func (s *Sma) state(st *stateCodec) {
+	st.param(s.n)
+	st.sub(s.hist)
+	st.int(&s.sz)
+	st.float(&s.sum)
+}
+
 // A simple moving average is formed by computing the average price of a security
 // over a specific number of periods. Most moving averages are based on closing
 // prices; for example, a 5-day simple moving average is the five-day sum of closing
@@ -57,13 +64,14 @@ func (s *Sma) Valid() bool {
 //  https://school.stockcharts.com/doku.php?id=technical_indicators:moving_averages
 //  https://www.investopedia.com/terms/s/sma.asp
 //  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/sma
-func SmaArr(in []float64, n int64) []float64 {
+func SmaArr(in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	s := NewSma(n)
 	for i, v := range in {
 		out[i] = s.Update(v)
 	}
+	warmup(mode, s.InitPeriod(), out)
 
 	return out
 }
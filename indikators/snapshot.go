@@ -0,0 +1,277 @@
+package indikators
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// SnapshotVersion is written into every snapshot, snapshots of a newer
+// version are refused by Restore
+const SnapshotVersion = 1
+
+var snapshotMagic = []byte("AIND")
+
+var (
+	// ErrNoSnapshot is returned by snapshot stores when the key is unknown
+	ErrNoSnapshot = errors.New("indikators: snapshot not found")
+	// ErrSnapshotMismatch is returned when a snapshot is restored into an
+	// indicator of another type or created with other parameters
+	ErrSnapshotMismatch = errors.New("indikators: snapshot does not match indicator")
+)
+
+// Stateful is implemented by every indicator of this package (and the
+// ones built by the registry). Its state can be captured with
+// TakeSnapshot and put back with Snapshot.Restore, so a restarted process
+// does not need to replay the warm up period.
+type Stateful interface {
+	state(s *stateCodec)
+}
+
+// Snapshot is the serialised state of an indicator. It can be stored in
+// binary form (MarshalBinary) or as JSON, State holds the binary encoded
+// indicator fields.
+type Snapshot struct {
+	Version int    `json:"version"`
+	Type    string `json:"type"`
+	State   []byte `json:"state"`
+}
+
+func TakeSnapshot(x Stateful) (*Snapshot, error) {
+	s := &stateCodec{w: &bytes.Buffer{}}
+	x.state(s)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &Snapshot{
+		Version: SnapshotVersion,
+		Type:    typeName(x),
+		State:   s.w.Bytes(),
+	}, nil
+}
+
+// Restore puts the snapshot state back into x, which must be of the same
+// type and created with the same parameters as the indicator the
+// snapshot was taken from. x is left untouched when an error is returned.
+func (s *Snapshot) Restore(x Stateful) error {
+	if s.Version < 1 || s.Version > SnapshotVersion {
+		return fmt.Errorf("indikators: unsupported snapshot version %d", s.Version)
+	}
+	if s.Type != typeName(x) {
+		return fmt.Errorf("%w: %s into %s", ErrSnapshotMismatch, s.Type, typeName(x))
+	}
+
+	// dry run first so a bad snapshot does not leave x half restored
+	for _, dry := range []bool{true, false} {
+		c := &stateCodec{r: bytes.NewReader(s.State), dry: dry}
+		x.state(c)
+		if c.err == nil && c.r.Len() != 0 {
+			c.err = ErrSnapshotMismatch
+		}
+		if c.err != nil {
+			return c.err
+		}
+	}
+	return nil
+}
+
+func (s *Snapshot) MarshalBinary() ([]byte, error) {
+	b := append([]byte{}, snapshotMagic...)
+	b = binary.LittleEndian.AppendUint16(b, uint16(s.Version))
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(s.Type)))
+	b = append(b, s.Type...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s.State)))
+	b = append(b, s.State...)
+	return b, nil
+}
+
+func (s *Snapshot) UnmarshalBinary(data []byte) error {
+	bad := errors.New("indikators: malformed snapshot")
+	if len(data) < 8 || !bytes.Equal(data[:4], snapshotMagic) {
+		return bad
+	}
+	version := binary.LittleEndian.Uint16(data[4:])
+	n := int(binary.LittleEndian.Uint16(data[6:]))
+	data = data[8:]
+	if len(data) < n+4 {
+		return bad
+	}
+	typ := string(data[:n])
+	data = data[n:]
+	n = int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+	if len(data) != n {
+		return bad
+	}
+
+	s.Version = int(version)
+	s.Type = typ
+	s.State = append([]byte{}, data...)
+	return nil
+}
+
+// SnapshotStore persists binary snapshots, see FileSnapshotStore and
+// storage.RedisSnapshot. Load returns ErrNoSnapshot for unknown keys.
+type SnapshotStore interface {
+	Save(ctx context.Context, key string, data []byte) error
+	Load(ctx context.Context, key string) ([]byte, error)
+}
+
+// SaveSnapshot takes a snapshot of x and saves it under key
+func SaveSnapshot(ctx context.Context, store SnapshotStore, key string, x Stateful) error {
+	s, err := TakeSnapshot(x)
+	if err != nil {
+		return err
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return store.Save(ctx, key, data)
+}
+
+// LoadSnapshot restores x from the snapshot saved under key
+func LoadSnapshot(ctx context.Context, store SnapshotStore, key string, x Stateful) error {
+	data, err := store.Load(ctx, key)
+	if err != nil {
+		return err
+	}
+	s := &Snapshot{}
+	if err := s.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	return s.Restore(x)
+}
+
+func typeName(x Stateful) string {
+	return fmt.Sprintf("%T", x)
+}
+
+// stateCodec walks the fields of an indicator. The same state method is
+// used to encode (w set) and to decode (r set), in dry mode values are
+// read and checked but not assigned.
+type stateCodec struct {
+	w   *bytes.Buffer
+	r   *bytes.Reader
+	dry bool
+	err error
+}
+
+func (s *stateCodec) uint64(p *uint64) {
+	if s.err != nil {
+		return
+	}
+	if s.w != nil {
+		s.w.Write(binary.LittleEndian.AppendUint64(nil, *p))
+		return
+	}
+	var b [8]byte
+	if _, err := io.ReadFull(s.r, b[:]); err != nil {
+		s.err = ErrSnapshotMismatch
+		return
+	}
+	if !s.dry {
+		*p = binary.LittleEndian.Uint64(b[:])
+	}
+}
+
+// Length of variable sized state, unlike other values it is also
+// assigned in dry mode as it drives the rest of the decoding
+func (s *stateCodec) count(p *int64) {
+	dry := s.dry
+	s.dry = false
+	s.int(p)
+	s.dry = dry
+}
+
+func (s *stateCodec) int(p *int64) {
+	v := uint64(*p)
+	s.uint64(&v)
+	*p = int64(v)
+}
+
+func (s *stateCodec) float(p *float64) {
+	v := math.Float64bits(*p)
+	s.uint64(&v)
+	*p = math.Float64frombits(v)
+}
+
+func (s *stateCodec) bool(p *bool) {
+	var v int64
+	if *p {
+		v = 1
+	}
+	s.int(&v)
+	*p = v == 1
+}
+
+func (s *stateCodec) time(p *time.Time) {
+	zero := p.IsZero()
+	s.bool(&zero)
+	var ns int64
+	if !zero {
+		ns = p.UnixNano()
+	}
+	s.int(&ns)
+	if s.err == nil && !s.dry && s.r != nil {
+		if zero {
+			*p = time.Time{}
+		} else {
+			*p = time.Unix(0, ns)
+		}
+	}
+}
+
+func (s *stateCodec) candle(c *Candle) {
+	s.time(&c.Time)
+	s.float(&c.Open)
+	s.float(&c.High)
+	s.float(&c.Low)
+	s.float(&c.Close)
+	s.float(&c.Volume)
+}
+
+// Fixed length slice, the length has to match when decoding
+func (s *stateCodec) floats(p []float64) {
+	s.param(int64(len(p)))
+	for i := range p {
+		s.float(&p[i])
+	}
+}
+
+// Constructor parameter, it is only checked when decoding
+func (s *stateCodec) param(v int64) {
+	got := uint64(v)
+	s.uint64(&got)
+	if s.err == nil && int64(got) != v {
+		s.err = ErrSnapshotMismatch
+	}
+}
+
+func (s *stateCodec) paramFloat(v float64) {
+	s.param(int64(math.Float64bits(v)))
+}
+
+// Nested indicator, prefixed by its type
+func (s *stateCodec) sub(x Stateful) {
+	name := typeName(x)
+	s.param(int64(len(name)))
+	if s.err != nil {
+		return
+	}
+	if s.w != nil {
+		s.w.WriteString(name)
+	} else {
+		b := make([]byte, len(name))
+		if _, err := io.ReadFull(s.r, b); err != nil || string(b) != name {
+			s.err = ErrSnapshotMismatch
+			return
+		}
+	}
+	x.state(s)
+}
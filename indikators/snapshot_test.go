@@ -0,0 +1,216 @@
+package indikators_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/json"
+	"github.com/stretchr/testify/assert"
+)
+
+// timedOHLCV is the golden input with hourly times, vwap and pivots need them
+func timedOHLCV(t *testing.T) []indikators.Candle {
+	in := readOHLCV(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, indikators.Jakarta)
+	for i := range in {
+		in[i].Time = start.Add(time.Duration(i) * time.Hour)
+	}
+	return in
+}
+
+func build(t *testing.T, name string) indikators.Indicator {
+	t.Helper()
+	x, err := indikators.Build(json.Object{"type": name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func snapshotBytes(t *testing.T, x indikators.Indicator) []byte {
+	t.Helper()
+	s, err := indikators.TakeSnapshot(x.(indikators.Stateful))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// sameValues compares outputs bit by bit so NaN equals NaN
+func sameValues(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Float64bits(a[i]) != math.Float64bits(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	in := timedOHLCV(t)
+	split := 150
+
+	for _, name := range indikators.Registered() {
+		t.Run(name, func(t *testing.T) {
+			x := build(t, name)
+			for _, c := range in[:split] {
+				x.Update(c)
+			}
+
+			s := &indikators.Snapshot{}
+			assert.NoError(t, s.UnmarshalBinary(snapshotBytes(t, x)), "they should be no error")
+			restored := build(t, name)
+			assert.NoError(t, s.Restore(restored.(indikators.Stateful)), "they should be no error")
+			assert.Equal(t, x.Valid(), restored.Valid(), "they should be equal")
+
+			for i, c := range in[split:] {
+				want, got := x.Update(c), restored.Update(c)
+				if !sameValues(want, got) {
+					t.Fatalf("bar %d: got %v, want %v", split+i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	in := timedOHLCV(t)
+
+	tests := []struct {
+		name                string
+		funcUseCaseShouldBe func(t *testing.T)
+	}{
+		{
+			name: "Newer version is refused",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				s, _ := indikators.TakeSnapshot(indikators.NewSma(5))
+				s.Version = indikators.SnapshotVersion + 1
+				assert.EqualError(t, s.Restore(indikators.NewSma(5)), "indikators: unsupported snapshot version 2", "they should be equal")
+				s.Version = 0
+				assert.Error(t, s.Restore(indikators.NewSma(5)), "they should be error")
+			},
+		},
+		{
+			name: "Other type is refused",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				s, _ := indikators.TakeSnapshot(indikators.NewSma(5))
+				err := s.Restore(indikators.NewEma(5, 2.0/6))
+				assert.True(t, errors.Is(err, indikators.ErrSnapshotMismatch), "it should be a mismatch")
+				assert.Contains(t, err.Error(), "*indikators.Sma into *indikators.Ema", "they should contain")
+			},
+		},
+		{
+			name: "Other indicator behind the registry is refused",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				data := snapshotBytes(t, build(t, "sma"))
+				s := &indikators.Snapshot{}
+				assert.NoError(t, s.UnmarshalBinary(data), "they should be no error")
+				err := s.Restore(build(t, "ema").(indikators.Stateful))
+				assert.True(t, errors.Is(err, indikators.ErrSnapshotMismatch), "it should be a mismatch")
+			},
+		},
+		{
+			name: "Other parameters are refused",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				s, _ := indikators.TakeSnapshot(indikators.NewSma(5))
+				err := s.Restore(indikators.NewSma(10))
+				assert.True(t, errors.Is(err, indikators.ErrSnapshotMismatch), "it should be a mismatch")
+			},
+		},
+		{
+			name: "Truncated binary snapshot",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				data := snapshotBytes(t, build(t, "bbands"))
+				for _, n := range []int{0, 3, 7, 9, len(data) - 1} {
+					assert.EqualError(t, (&indikators.Snapshot{}).UnmarshalBinary(data[:n]), "indikators: malformed snapshot", "%d bytes should be malformed", n)
+				}
+				assert.Error(t, (&indikators.Snapshot{}).UnmarshalBinary(append(data, 0)), "trailing bytes should be malformed")
+				bad := append([]byte("XIND"), data[4:]...)
+				assert.Error(t, (&indikators.Snapshot{}).UnmarshalBinary(bad), "bad magic should be malformed")
+			},
+		},
+		{
+			name: "Truncated or padded state is refused",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				s, _ := indikators.TakeSnapshot(indikators.NewSma(5))
+				state := s.State
+				s.State = state[:len(state)-1]
+				assert.True(t, errors.Is(s.Restore(indikators.NewSma(5)), indikators.ErrSnapshotMismatch), "it should be a mismatch")
+				s.State = append(append([]byte{}, state...), 0)
+				assert.True(t, errors.Is(s.Restore(indikators.NewSma(5)), indikators.ErrSnapshotMismatch), "it should be a mismatch")
+			},
+		},
+		{
+			name: "Failed restore leaves the indicator untouched",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				for _, name := range indikators.Registered() {
+					src := build(t, name)
+					for _, c := range in[:120] {
+						src.Update(c)
+					}
+					s, _ := indikators.TakeSnapshot(src.(indikators.Stateful))
+
+					dst := build(t, name)
+					for _, c := range in[200:230] {
+						dst.Update(c)
+					}
+					before := snapshotBytes(t, dst)
+					// every field is read but the last one is missing
+					s.State = s.State[:len(s.State)-1]
+					assert.Error(t, s.Restore(dst.(indikators.Stateful)), "%s should fail", name)
+					assert.Equal(t, before, snapshotBytes(t, dst), "%s should be untouched", name)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.funcUseCaseShouldBe(t)
+		})
+	}
+}
+
+func TestFileSnapshotStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := indikators.NewFileSnapshotStore(dir)
+	assert.NoError(t, err, "they should be no error")
+
+	_, err = store.Load(ctx, "btc_idr/rsi")
+	assert.Equal(t, indikators.ErrNoSnapshot, err, "they should be equal")
+
+	in := timedOHLCV(t)
+	x := indikators.NewRsi(14)
+	for _, c := range in[:100] {
+		x.Update(c.Close)
+	}
+	assert.NoError(t, indikators.SaveSnapshot(ctx, store, "btc_idr/rsi", x), "they should be no error")
+	// overwriting replaces the file in place
+	assert.NoError(t, indikators.SaveSnapshot(ctx, store, "btc_idr/rsi", x), "they should be no error")
+
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1, "they should be no temporary file left")
+	assert.Equal(t, "btc_idr%2Frsi.snapshot", entries[0].Name(), "they should be equal")
+
+	restored := indikators.NewRsi(14)
+	assert.NoError(t, indikators.LoadSnapshot(ctx, store, "btc_idr/rsi", restored), "they should be no error")
+	for _, c := range in[100:] {
+		assert.Equal(t, x.Update(c.Close), restored.Update(c.Close), "they should be equal")
+	}
+
+	err = indikators.LoadSnapshot(ctx, store, "btc_idr/rsi", indikators.NewRsi(7))
+	assert.True(t, errors.Is(err, indikators.ErrSnapshotMismatch), "it should be a mismatch")
+}
@@ -0,0 +1,78 @@
+package indikators
+
+// The Bollinger Band Squeeze occurs when volatility falls to low levels
+// and the Bollinger Bands narrow. John Bollinger flags a squeeze when
+// BandWidth reaches its lowest level of the last lookback periods (six
+// months in his book). Low volatility periods are followed by high
+// volatility ones, so a squeeze is used as a setup for breakout trades,
+// the direction coming from the band the price breaks first.
+//  https://school.stockcharts.com/doku.php?id=trading_strategies:bollinger_band_squeeze
+//  https://www.investopedia.com/articles/technical/04/030304.asp
+type Squeeze struct {
+	lookback int64
+	bw       *BandWidth
+	lo       *Rolling
+	sz       int64
+}
+
+func NewSqueeze(t MaType, n int64, upNStdDev, dnNStdDev float64, lookback int64) *Squeeze {
+	return &Squeeze{
+		lookback: lookback,
+		bw:       NewBandWidth(t, n, upNStdDev, dnNStdDev),
+		lo:       NewRollingMin(lookback),
+		sz:       0,
+	}
+}
+
+// bandwidth, squeeze
+func (s *Squeeze) Update(v float64) (float64, bool) {
+	s.sz++
+
+	bw := s.bw.Update(v)
+	if !s.bw.Valid() {
+		return 0, false
+	}
+
+	low := s.lo.Update(bw)
+	if !s.lo.Valid() {
+		return bw, false
+	}
+
+	return bw, bw <= low
+}
+
+func (s *Squeeze) InitPeriod() int64 {
+	return s.bw.InitPeriod() + s.lookback - 1
+}
+
+func (s *Squeeze) Valid() bool {
+	return s.sz > s.InitPeriod()
+}
+
+func (s *Squeeze) state(st *stateCodec) {
+	st.param(s.lookback)
+	st.sub(s.bw)
+	st.sub(s.lo)
+	st.int(&s.sz)
+}
+
+// The Bollinger Band Squeeze occurs when volatility falls to low levels
+// and the Bollinger Bands narrow. John Bollinger flags a squeeze when
+// BandWidth reaches its lowest level of the last lookback periods (six
+// months in his book). Low volatility periods are followed by high
+// volatility ones, so a squeeze is used as a setup for breakout trades,
+// the direction coming from the band the price breaks first.
+//  https://school.stockcharts.com/doku.php?id=trading_strategies:bollinger_band_squeeze
+//  https://www.investopedia.com/articles/technical/04/030304.asp
+func SqueezeArr(t MaType, in []float64, n int64, upNStdDev, dnNStdDev float64, lookback int64, mode ...WarmupMode) ([]float64, []bool) {
+	bw := make([]float64, len(in))
+	sq := make([]bool, len(in))
+
+	s := NewSqueeze(t, n, upNStdDev, dnNStdDev, lookback)
+	for i, v := range in {
+		bw[i], sq[i] = s.Update(v)
+	}
+	warmup(mode, s.bw.InitPeriod(), bw)
+
+	return bw, sq
+}
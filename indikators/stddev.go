@@ -38,6 +38,10 @@ func (s *StdDev) Valid() bool {
 	return s.v.Valid()
 }
 
+func (st *StdDev) state(s *stateCodec) {
+	s.sub(st.v)
+}
+
 // Standard deviation is a statistical term that measures the amount of
 // variability or dispersion around an average. Standard deviation is also
 // a measure of volatility. Generally speaking, dispersion is the difference
@@ -49,13 +53,14 @@ func (s *StdDev) Valid() bool {
 //  https://school.stockcharts.com/doku.php?id=technical_indicators:standard_deviation_volatility
 //  https://www.investopedia.com/terms/s/standarddeviation.asp
 //  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/standard-deviation
-func StdDevArr(in []float64, n int64) []float64 {
+func StdDevArr(in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	s := NewStdDev(n)
 	for i, v := range in {
 		out[i] = s.Update(v)
 	}
+	warmup(mode, s.InitPeriod(), out)
 
 	return out
 }
@@ -0,0 +1,106 @@
+package indikators
+
+// Developed by George C. Lane in the late 1950s, the Stochastic Oscillator
+// is a momentum indicator that shows the location of the close relative to
+// the high-low range over a set number of periods. The raw %K compares the
+// close with the highest high and lowest low of the look-back window. The
+// slow %K smooths the raw %K with a moving average and %D is a moving average
+// of the slow %K. Readings above 80 are considered overbought and readings
+// below 20 oversold.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:stochastic_oscillator_fast_slow_and_full
+//  https://www.investopedia.com/terms/s/stochasticoscillator.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/slow-stochastic
+type Stoch struct {
+	fastKN int64
+	hi     *Rolling
+	lo     *Rolling
+	k      *Ma
+	d      *Ma
+	sz     int64
+}
+
+func NewStoch(fastKN int64, slowKT MaType, slowKN int64, slowDT MaType, slowDN int64) *Stoch {
+	return &Stoch{
+		fastKN: fastKN,
+		hi:     NewRollingMax(fastKN),
+		lo:     NewRollingMin(fastKN),
+		k:      NewMa(slowKT, slowKN),
+		d:      NewMa(slowDT, slowDN),
+		sz:     0,
+	}
+}
+
+// slow %K, slow %D
+func (s *Stoch) Update(c Candle) (float64, float64) {
+	s.sz++
+
+	hh := s.hi.Update(c.High)
+	ll := s.lo.Update(c.Low)
+
+	if s.sz < s.fastKN {
+		return 0, 0
+	}
+
+	fastK := stochK(c.Close, hh, ll)
+
+	k := s.k.Update(fastK)
+	if !s.k.Valid() {
+		return 0, 0
+	}
+
+	d := s.d.Update(k)
+	if !s.d.Valid() {
+		return 0, 0
+	}
+
+	return k, d
+}
+
+func (s *Stoch) InitPeriod() int64 {
+	return s.fastKN - 1 + s.k.InitPeriod() + s.d.InitPeriod()
+}
+
+func (s *Stoch) Valid() bool {
+	return s.sz > s.InitPeriod()
+}
+
+func (st *Stoch) state(s *stateCodec) {
+	s.param(st.fastKN)
+	s.sub(st.hi)
+	s.sub(st.lo)
+	s.sub(st.k)
+	s.sub(st.d)
+	s.int(&st.sz)
+}
+
+// Developed by George C. Lane in the late 1950s, the Stochastic Oscillator
+// is a momentum indicator that shows the location of the close relative to
+// the high-low range over a set number of periods. The raw %K compares the
+// close with the highest high and lowest low of the look-back window. The
+// slow %K smooths the raw %K with a moving average and %D is a moving average
+// of the slow %K. Readings above 80 are considered overbought and readings
+// below 20 oversold.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:stochastic_oscillator_fast_slow_and_full
+//  https://www.investopedia.com/terms/s/stochasticoscillator.asp
+//  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/slow-stochastic
+func StochArr(in []Candle, fastKN int64, slowKT MaType, slowKN int64, slowDT MaType, slowDN int64, mode ...WarmupMode) ([]float64, []float64) {
+	k := make([]float64, len(in))
+	d := make([]float64, len(in))
+
+	s := NewStoch(fastKN, slowKT, slowKN, slowDT, slowDN)
+	for i, v := range in {
+		k[i], d[i] = s.Update(v)
+	}
+	warmup(mode, s.InitPeriod(), k, d)
+
+	return k, d
+}
+
+// position of v inside [ll, hh] scaled to 0..100
+func stochK(v, hh, ll float64) float64 {
+	diff := hh - ll
+	if almostZero(diff) {
+		return 0
+	}
+	return (v - ll) / diff * 100.0
+}
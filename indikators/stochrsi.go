@@ -0,0 +1,92 @@
+package indikators
+
+// Developed by Tushar Chande and Stanley Kroll, StochRSI is an oscillator
+// that measures the level of RSI relative to its high-low range over a set
+// time period. StochRSI applies the Stochastics formula to RSI values,
+// rather than price values, making it an indicator of an indicator. The
+// result is an oscillator that fluctuates between 0 and 100 and is more
+// sensitive than the plain RSI.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:stochrsi
+//  https://www.investopedia.com/terms/s/stochrsi.asp
+type StochRsi struct {
+	fastKN int64
+	rsi    *Rsi
+	hi     *Rolling
+	lo     *Rolling
+	d      *Ma
+	sz     int64
+}
+
+func NewStochRsi(n int64, fastKN int64, fastDT MaType, fastDN int64) *StochRsi {
+	return &StochRsi{
+		fastKN: fastKN,
+		rsi:    NewRsi(n),
+		hi:     NewRollingMax(fastKN),
+		lo:     NewRollingMin(fastKN),
+		d:      NewMa(fastDT, fastDN),
+		sz:     0,
+	}
+}
+
+// fast %K, fast %D
+func (s *StochRsi) Update(v float64) (float64, float64) {
+	s.sz++
+
+	r := s.rsi.Update(v)
+	if !s.rsi.Valid() {
+		return 0, 0
+	}
+
+	hh := s.hi.Update(r)
+	ll := s.lo.Update(r)
+	if !s.hi.Valid() {
+		return 0, 0
+	}
+
+	k := stochK(r, hh, ll)
+
+	d := s.d.Update(k)
+	if !s.d.Valid() {
+		return 0, 0
+	}
+
+	return k, d
+}
+
+func (s *StochRsi) InitPeriod() int64 {
+	return s.rsi.InitPeriod() + s.fastKN - 1 + s.d.InitPeriod()
+}
+
+func (s *StochRsi) Valid() bool {
+	return s.sz > s.InitPeriod()
+}
+
+func (st *StochRsi) state(s *stateCodec) {
+	s.param(st.fastKN)
+	s.sub(st.rsi)
+	s.sub(st.hi)
+	s.sub(st.lo)
+	s.sub(st.d)
+	s.int(&st.sz)
+}
+
+// Developed by Tushar Chande and Stanley Kroll, StochRSI is an oscillator
+// that measures the level of RSI relative to its high-low range over a set
+// time period. StochRSI applies the Stochastics formula to RSI values,
+// rather than price values, making it an indicator of an indicator. The
+// result is an oscillator that fluctuates between 0 and 100 and is more
+// sensitive than the plain RSI.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:stochrsi
+//  https://www.investopedia.com/terms/s/stochrsi.asp
+func StochRsiArr(in []float64, n int64, fastKN int64, fastDT MaType, fastDN int64, mode ...WarmupMode) ([]float64, []float64) {
+	k := make([]float64, len(in))
+	d := make([]float64, len(in))
+
+	s := NewStochRsi(n, fastKN, fastDT, fastDN)
+	for i, v := range in {
+		k[i], d[i] = s.Update(v)
+	}
+	warmup(mode, s.InitPeriod(), k, d)
+
+	return k, d
+}
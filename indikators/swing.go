@@ -0,0 +1,136 @@
+package indikators
+
+import "math"
+
+// Swing is a confirmed swing high or low (pivot) of a series
+type Swing struct {
+	// bar of the extreme
+	Index int
+	Value float64
+	High  bool
+	// bar at which the swing became known, use it instead of Index to
+	// avoid looking ahead when replaying history
+	Confirmed int
+}
+
+// SwingFinder finds the swings of a series. For a single value series
+// such as an oscillator pass the same slice as high and low.
+type SwingFinder func(high, low []float64) []Swing
+
+// Fractal finds swings the way Bill Williams' fractals do: a bar is a
+// swing high when its high is above the left bars before it and not
+// below the right bars after it, swing lows likewise. A swing is only
+// confirmed right bars later.
+//  https://www.investopedia.com/terms/f/fractal.asp
+func Fractal(left, right int) SwingFinder {
+	return func(high, low []float64) []Swing {
+		return FractalSwings(high, low, left, right)
+	}
+}
+
+// ZigZag finds swings that are followed by a reversal of at least pct
+// percent. Swings alternate between highs and lows.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:zigzag
+func ZigZag(pct float64) SwingFinder {
+	return func(high, low []float64) []Swing {
+		return zigzag(high, low, func(from, to float64) bool {
+			return math.Abs(to-from) >= math.Abs(from)*pct/100.0
+		})
+	}
+}
+
+// ZigZagAbs is ZigZag with the reversal given in absolute units, which
+// suits oscillators crossing zero like MACD
+func ZigZagAbs(delta float64) SwingFinder {
+	return func(high, low []float64) []Swing {
+		return zigzag(high, low, func(from, to float64) bool {
+			return math.Abs(to-from) >= delta
+		})
+	}
+}
+
+// FractalSwings returns the swings of high and low ordered by Index, see
+// Fractal. NaN values are never swings.
+func FractalSwings(high, low []float64, left, right int) []Swing {
+	var out []Swing
+	for i := left; i+right < len(high); i++ {
+		if isFractal(high, i, left, right, func(a, b float64) bool { return a > b }) {
+			out = append(out, Swing{Index: i, Value: high[i], High: true, Confirmed: i + right})
+		}
+		if isFractal(low, i, left, right, func(a, b float64) bool { return a < b }) {
+			out = append(out, Swing{Index: i, Value: low[i], High: false, Confirmed: i + right})
+		}
+	}
+	return out
+}
+
+// beyond(a, b) reports a is past b in the swing direction
+func isFractal(in []float64, i, left, right int, beyond func(a, b float64) bool) bool {
+	v := in[i]
+	if math.IsNaN(v) {
+		return false
+	}
+	for j := i - left; j < i; j++ {
+		if math.IsNaN(in[j]) || !beyond(v, in[j]) {
+			return false
+		}
+	}
+	for j := i + 1; j <= i+right; j++ {
+		if math.IsNaN(in[j]) || beyond(in[j], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// reversed(from, to) reports the move from an extreme is a reversal
+func zigzag(high, low []float64, reversed func(from, to float64) bool) []Swing {
+	var out []Swing
+
+	// skip the NaN warm-up
+	start := 0
+	for start < len(high) && (math.IsNaN(high[start]) || math.IsNaN(low[start])) {
+		start++
+	}
+
+	// 1 while tracking the high of an up leg, -1 for the low of a down
+	// leg, 0 until the first reversal
+	dir := 0
+	hi, lo := start, start
+	for i := start + 1; i < len(high); i++ {
+		switch dir {
+		case 0:
+			if high[i] > high[hi] {
+				hi = i
+			}
+			if low[i] < low[lo] {
+				lo = i
+			}
+			if lo < hi && reversed(low[lo], high[hi]) {
+				out = append(out, Swing{Index: lo, Value: low[lo], High: false, Confirmed: i})
+				dir = 1
+			} else if hi < lo && reversed(high[hi], low[lo]) {
+				out = append(out, Swing{Index: hi, Value: high[hi], High: true, Confirmed: i})
+				dir = -1
+			}
+		case 1:
+			if high[i] >= high[hi] {
+				hi = i
+			} else if reversed(high[hi], low[i]) {
+				out = append(out, Swing{Index: hi, Value: high[hi], High: true, Confirmed: i})
+				lo = i
+				dir = -1
+			}
+		case -1:
+			if low[i] <= low[lo] {
+				lo = i
+			} else if reversed(low[lo], high[i]) {
+				out = append(out, Swing{Index: lo, Value: low[lo], High: false, Confirmed: i})
+				hi = i
+				dir = 1
+			}
+		}
+	}
+
+	return out
+}
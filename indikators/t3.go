@@ -0,0 +1,96 @@
+package indikators
+
+// Developed by Tim Tillson, the T3 moving average is a six times smoothed
+// exponential moving average. It applies a generalized DEMA (GD) three
+// times, where GD(x) = EMA(x)*(1+v) - EMA(EMA(x))*v. The volume factor v
+// controls how much the average reacts to recent prices: 0 turns T3 into
+// a triple smoothed EMA and 1 into a triple DEMA. Tillson recommends 0.7.
+// The result follows prices closely while staying smoother than a DEMA
+// or TEMA of the same period.
+//  https://www.fmlabs.com/reference/default.htm?url=T3.htm
+//  https://www.tradingview.com/script/qzoC9H1I-T3-Average/
+type T3Ma struct {
+	n    int64
+	sz   int64
+	c1   float64
+	c2   float64
+	c3   float64
+	c4   float64
+	emas [6]*Ema
+}
+
+func NewT3Ma(n int64, vFactor float64) *T3Ma {
+	k := 2.0 / float64(n+1)
+	vv := vFactor * vFactor
+	c1 := -vv * vFactor
+	t := &T3Ma{
+		n:  n,
+		sz: 0,
+		c1: c1,
+		c2: 3.0 * (vv - c1),
+		c3: -6.0*vv - 3.0*(vFactor-c1),
+		c4: 1.0 + 3.0*vFactor - c1 + 3.0*vv,
+	}
+	for i := range t.emas {
+		t.emas[i] = NewEma(n, k)
+	}
+	return t
+}
+
+func (t *T3Ma) Update(v float64) float64 {
+	t.sz++
+
+	var e [6]float64
+	for i, ema := range t.emas {
+		// every EMA starts once the previous one produces valid values
+		if t.sz <= int64(i)*(t.n-1) {
+			return 0
+		}
+		e[i] = ema.Update(v)
+		v = e[i]
+	}
+
+	if t.sz <= t.InitPeriod() {
+		return 0
+	}
+
+	return t.c1*e[5] + t.c2*e[4] + t.c3*e[3] + t.c4*e[2]
+}
+
+func (t *T3Ma) InitPeriod() int64 {
+	return t.n*6 - 6
+}
+
+func (t *T3Ma) Valid() bool {
+	return t.sz > t.InitPeriod()
+}
+
+func (t *T3Ma) state(s *stateCodec) {
+	s.param(t.n)
+	s.paramFloat(t.c1)
+	s.int(&t.sz)
+	for _, e := range t.emas {
+		s.sub(e)
+	}
+}
+
+// Developed by Tim Tillson, the T3 moving average is a six times smoothed
+// exponential moving average. It applies a generalized DEMA (GD) three
+// times, where GD(x) = EMA(x)*(1+v) - EMA(EMA(x))*v. The volume factor v
+// controls how much the average reacts to recent prices: 0 turns T3 into
+// a triple smoothed EMA and 1 into a triple DEMA. Tillson recommends 0.7.
+// The result follows prices closely while staying smoother than a DEMA
+// or TEMA of the same period.
+//  https://www.fmlabs.com/reference/default.htm?url=T3.htm
+//  https://www.tradingview.com/script/qzoC9H1I-T3-Average/
+func T3MaArr(in []float64, n int64, vFactor float64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	t := NewT3Ma(n, vFactor)
+	for i, v := range in {
+		out[i] = t.Update(v)
+	}
+	warmup(mode, t.InitPeriod(), out)
+
+	return out
+}
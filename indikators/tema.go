@@ -58,6 +58,14 @@ func (t *Tema) Valid() bool {
 	return t.sz > t.InitPeriod()
 }
 
+func (t *Tema) state(s *stateCodec) {
+	s.param(t.n)
+	s.int(&t.sz)
+	s.sub(t.ema1)
+	s.sub(t.ema2)
+	s.sub(t.ema3)
+}
+
 // The Triple Exponential Moving Average (TEMA) reduces the lag of traditional
 // EMAs, making it more responsive and better-suited for short-term trading.
 // Shortly after developing the Double Exponential Moving Average (DEMA) in 1994,
@@ -70,7 +78,7 @@ func (t *Tema) Valid() bool {
 // price bars than DEMA.
 //  https://school.stockcharts.com/doku.php?id=technical_indicators:tema
 //  https://www.investopedia.com/terms/t/triple-exponential-moving-average.asp
-func TemaArr(in []float64, n int64) []float64 {
+func TemaArr(in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	k := 2.0 / float64(n+1)
@@ -78,6 +86,7 @@ func TemaArr(in []float64, n int64) []float64 {
 	for i, v := range in {
 		out[i] = t.Update(v)
 	}
+	warmup(mode, t.InitPeriod(), out)
 
 	return out
 }
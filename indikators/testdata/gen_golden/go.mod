@@ -0,0 +1,8 @@
+module github.com/Fatiri/areuy/indikators/testdata/gen_golden
+
+go 1.23.2
+
+require (
+	github.com/iwat/talib-cdl-go v1.0.0
+	github.com/markcheno/go-talib v0.0.0-20250114000313-ec55a20c902f
+)
@@ -0,0 +1,73 @@
+package indikators
+
+import (
+	"math"
+)
+
+// Developed by J. Welles Wilder, the true range is the greatest of the
+// current high less the current low, the absolute value of the current
+// high less the previous close and the absolute value of the current
+// low less the previous close. Gaps are taken into account by using the
+// previous close, which a simple high-low range ignores.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:average_true_range_atr
+//  https://www.investopedia.com/terms/a/atr.asp
+type TRange struct {
+	prevC float64
+	sz    int64
+}
+
+func NewTRange() *TRange {
+	return &TRange{
+		prevC: 0,
+		sz:    0,
+	}
+}
+
+func (t *TRange) Update(c Candle) float64 {
+	t.sz++
+
+	prevC := t.prevC
+	t.prevC = c.Close
+
+	if t.sz == 1 {
+		return 0
+	}
+
+	tr := c.High - c.Low
+	tr = max(tr, math.Abs(c.High-prevC))
+	tr = max(tr, math.Abs(c.Low-prevC))
+
+	return tr
+}
+
+func (t *TRange) InitPeriod() int64 {
+	return 1
+}
+
+func (t *TRange) Valid() bool {
+	return t.sz > t.InitPeriod()
+}
+
+func (t *TRange) state(s *stateCodec) {
+	s.float(&t.prevC)
+	s.int(&t.sz)
+}
+
+// Developed by J. Welles Wilder, the true range is the greatest of the
+// current high less the current low, the absolute value of the current
+// high less the previous close and the absolute value of the current
+// low less the previous close. Gaps are taken into account by using the
+// previous close, which a simple high-low range ignores.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:average_true_range_atr
+//  https://www.investopedia.com/terms/a/atr.asp
+func TRangeArr(in []Candle, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	t := NewTRange()
+	for i, v := range in {
+		out[i] = t.Update(v)
+	}
+	warmup(mode, t.InitPeriod(), out)
+
+	return out
+}
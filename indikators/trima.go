@@ -73,6 +73,14 @@ func (t *Trima) Valid() bool {
 	return t.hist.Size() > t.InitPeriod()
 }
 
+func (t *Trima) state(s *stateCodec) {
+	s.param(t.n)
+	s.sub(t.hist)
+	s.float(&t.leftSum)
+	s.float(&t.rightSum)
+	s.float(&t.sum)
+}
+
 // The triangular moving average (TMA) is a technical indicator that is similar
 // to other moving averages. The TMA shows the average (or mean) price of an
 // asset over a specified number of data points—usually a number of price bars.
@@ -80,13 +88,14 @@ func (t *Trima) Valid() bool {
 // smoothed—which also means averaged twice.
 //  https://www.thebalance.com/triangular-moving-average-tma-description-and-uses-1031203
 //  https://www.fidelity.com/viewpoints/active-investor/moving-averages
-func TrimaArr(in []float64, n int64) []float64 {
+func TrimaArr(in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	t := NewTrima(n)
 	for i, v := range in {
 		out[i] = t.Update(v)
 	}
+	warmup(mode, t.InitPeriod(), out)
 
 	return out
 }
@@ -18,3 +18,10 @@ func min(a, b float64) float64 {
 	}
 	return b
 }
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -48,6 +48,64 @@ func (r *Var) Valid() bool {
 	return r.hist.Size() > r.InitPeriod()
 }
 
+func (r *Var) state(s *stateCodec) {
+	s.param(r.n)
+	s.sub(r.hist)
+	s.float(&r.sum)
+}
+
+// CumVar is the population variance of every value since the first one,
+// where Var covers the last n values. It is updated in O(1) with Welford's
+// algorithm, which unlike a sum of squares keeps its precision over a long
+// history.
+//  https://en.wikipedia.org/wiki/Algorithms_for_calculating_variance#Welford's_online_algorithm
+type CumVar struct {
+	sz   int64
+	mean float64
+	m2   float64
+}
+
+func NewCumVar() *CumVar {
+	return &CumVar{
+		sz:   0,
+		mean: 0,
+		m2:   0,
+	}
+}
+
+func (r *CumVar) Update(v float64) float64 {
+	r.sz++
+	delta := v - r.mean
+	r.mean += delta / float64(r.sz)
+	r.m2 += delta * (v - r.mean)
+
+	return r.m2 / float64(r.sz)
+}
+
+// Mean of every value so far
+func (r *CumVar) Mean() float64 {
+	return r.mean
+}
+
+// Number of values fed
+func (r *CumVar) Size() int64 {
+	return r.sz
+}
+
+func (r *CumVar) InitPeriod() int64 {
+	return 0
+}
+
+func (r *CumVar) Valid() bool {
+	return r.sz > r.InitPeriod()
+}
+
+func (r *CumVar) state(s *stateCodec) {
+	s.int(&r.sz)
+	s.float(&r.mean)
+	s.float(&r.m2)
+}
+
 // The term variance refers to a statistical measurement of the spread between
 // numbers in a data set. More specifically, variance measures how far each
 // number in the set is from the mean and thus from every other number in the
@@ -56,13 +114,14 @@ func (r *Var) Valid() bool {
 // root of the variance is the standard deviation (σ), which helps determine
 // the consistency of an investment’s returns over a period of time.
 //  https://www.investopedia.com/terms/v/variance.asp
-func VarArr(in []float64, n int64) []float64 {
+func VarArr(in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	s := NewVar(n)
 	for i, v := range in {
 		out[i] = s.Update(v)
 	}
+	warmup(mode, s.InitPeriod(), out)
 
 	return out
 }
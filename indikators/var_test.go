@@ -0,0 +1,24 @@
+package indikators_test
+
+import (
+	"testing"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCumVar(t *testing.T) {
+	closes := indikators.Closes(readOHLCV(t))
+	c := indikators.NewCumVar()
+	assert.False(t, c.Valid(), "it should be invalid before the first value")
+
+	for i, v := range closes {
+		got := c.Update(v)
+		// a Var over the whole prefix is the same population variance
+		n := int64(i + 1)
+		want := indikators.VarArr(closes[:n], n)[i]
+		assert.InDelta(t, want, got, 1e-12*(1+want), "bar %d should be equal", i)
+	}
+	assert.Equal(t, int64(len(closes)), c.Size(), "they should be equal")
+	assert.InDelta(t, indikators.SmaArr(closes, int64(len(closes)))[len(closes)-1], c.Mean(), 1e-9, "they should be equal")
+}
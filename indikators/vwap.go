@@ -0,0 +1,95 @@
+package indikators
+
+import (
+	"time"
+)
+
+// Volume Weighted Average Price (VWAP) is the ratio of the value traded to
+// total volume traded over a particular time horizon, usually one trading
+// session. It is a measure of the average price at which a pair is traded
+// over the session and is used by traders as a benchmark for the quality
+// of their fills. Price above VWAP is considered bullish intraday and
+// price below VWAP bearish. The typical price of every candle is used as
+// its traded price. Crypto markets trade around the clock so the session
+// boundary is configurable: VWAP is reset at midnight of the given
+// location, or never reset when the location is nil.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:vwap_intraday
+//  https://www.investopedia.com/terms/v/vwap.asp
+type Vwap struct {
+	loc     *time.Location
+	session time.Time
+	pv      float64
+	vol     float64
+	sz      int64
+}
+
+// NewVwap creates a VWAP anchored to daily sessions in loc, e.g. Jakarta.
+// A nil loc anchors VWAP to the first candle and never resets it.
+func NewVwap(loc *time.Location) *Vwap {
+	return &Vwap{
+		loc: loc,
+		pv:  0,
+		vol: 0,
+		sz:  0,
+	}
+}
+
+func (w *Vwap) Update(c Candle) float64 {
+	w.sz++
+
+	if w.loc != nil {
+		session := startOfDay(c.Time, w.loc)
+		if !session.Equal(w.session) {
+			w.session = session
+			w.pv = 0
+			w.vol = 0
+		}
+	}
+
+	w.pv += c.Typical() * c.Volume
+	w.vol += c.Volume
+
+	if almostZero(w.vol) {
+		return 0
+	}
+
+	return w.pv / w.vol
+}
+
+func (w *Vwap) InitPeriod() int64 {
+	return 0
+}
+
+func (w *Vwap) Valid() bool {
+	return w.sz > w.InitPeriod()
+}
+
+func (w *Vwap) state(s *stateCodec) {
+	s.time(&w.session)
+	s.float(&w.pv)
+	s.float(&w.vol)
+	s.int(&w.sz)
+}
+
+// Volume Weighted Average Price (VWAP) is the ratio of the value traded to
+// total volume traded over a particular time horizon, usually one trading
+// session. It is a measure of the average price at which a pair is traded
+// over the session and is used by traders as a benchmark for the quality
+// of their fills. Price above VWAP is considered bullish intraday and
+// price below VWAP bearish. The typical price of every candle is used as
+// its traded price. Crypto markets trade around the clock so the session
+// boundary is configurable: VWAP is reset at midnight of the given
+// location, or never reset when the location is nil.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:vwap_intraday
+//  https://www.investopedia.com/terms/v/vwap.asp
+func VwapArr(in []Candle, loc *time.Location, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	w := NewVwap(loc)
+	for i, v := range in {
+		out[i] = w.Update(v)
+	}
+	warmup(mode, w.InitPeriod(), out)
+
+	return out
+}
@@ -0,0 +1,38 @@
+package indikators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVwapArr(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 0, 0, 0, indikators.Jakarta)
+	}
+	// typical prices 10, 20 and 30
+	in := []indikators.Candle{
+		{Time: at(1, 10), High: 11, Low: 9, Close: 10, Volume: 1},
+		{Time: at(1, 11), High: 21, Low: 19, Close: 20, Volume: 3},
+		{Time: at(2, 9), High: 31, Low: 29, Close: 30, Volume: 2},
+	}
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		mode []indikators.WarmupMode
+		want []float64
+	}{
+		{name: "Reset at midnight", loc: indikators.Jakarta, want: []float64{10, 17.5, 30}},
+		{name: "Never reset", want: []float64{10, 17.5, 130.0 / 6}},
+		{name: "No warm-up to leave NaN", loc: indikators.Jakarta, mode: []indikators.WarmupMode{indikators.WarmupNaN}, want: []float64{10, 17.5, 30}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.InDeltaSlice(t, test.want, indikators.VwapArr(in, test.loc, test.mode...), 1e-9, "they should be equal")
+		})
+	}
+}
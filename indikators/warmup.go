@@ -0,0 +1,50 @@
+package indikators
+
+import "math"
+
+// WarmupMode selects what an Arr function writes for the warm-up bars,
+// the first InitPeriod() outputs that are not computed yet. It is passed
+// per call as the optional last argument, WarmupZero when omitted:
+//
+//	sma := SmaArr(closes, 30, WarmupNaN)
+type WarmupMode int
+
+const (
+	// WarmupZero fills the warm-up bars with 0, the streaming Update
+	// convention
+	WarmupZero WarmupMode = iota
+	// WarmupNaN fills the warm-up bars with NaN like TA-Lib wrappers
+	// (numpy/pandas) do, see Trim for TA-Lib's begin index form
+	WarmupNaN
+)
+
+// nanWarmup reports whether the mode passed to an Arr function is WarmupNaN
+func nanWarmup(mode []WarmupMode) bool {
+	return len(mode) > 0 && mode[0] == WarmupNaN
+}
+
+// warmup applies the mode passed to an Arr function to the first lookback
+// values of every out
+func warmup(mode []WarmupMode, lookback int64, outs ...[]float64) {
+	if !nanWarmup(mode) {
+		return
+	}
+	nan := math.NaN()
+	for _, out := range outs {
+		for i := int64(0); i < lookback && i < int64(len(out)); i++ {
+			out[i] = nan
+		}
+	}
+}
+
+// Trim drops the leading NaN warm-up values of an Arr output computed in
+// WarmupNaN mode. It returns TA-Lib's outBegIdx, the index of the input
+// the first remaining value belongs to, and the trimmed slice.
+func Trim(out []float64) (int, []float64) {
+	for i, v := range out {
+		if !math.IsNaN(v) {
+			return i, out[i:]
+		}
+	}
+	return len(out), out[len(out):]
+}
@@ -0,0 +1,82 @@
+package indikators
+
+// Developed by Larry Williams, Williams %R is a momentum indicator that is
+// the inverse of the Fast Stochastic Oscillator. Also referred to as %R,
+// Williams %R reflects the level of the close relative to the highest high
+// for the look-back period. In contrast, the Stochastic Oscillator reflects
+// the level of the close relative to the lowest low. %R corrects for the
+// inversion by multiplying the raw value by -100. As a result, the Fast
+// Stochastic Oscillator and Williams %R produce the exact same lines, only
+// the scaling is different. Williams %R oscillates from 0 to -100.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:williams_r
+//  https://www.investopedia.com/terms/w/williamsr.asp
+type WillR struct {
+	n  int64
+	hi *Rolling
+	lo *Rolling
+	sz int64
+}
+
+func NewWillR(n int64) *WillR {
+	return &WillR{
+		n:  n,
+		hi: NewRollingMax(n),
+		lo: NewRollingMin(n),
+		sz: 0,
+	}
+}
+
+func (w *WillR) Update(c Candle) float64 {
+	w.sz++
+
+	hh := w.hi.Update(c.High)
+	ll := w.lo.Update(c.Low)
+
+	if w.sz < w.n {
+		return 0
+	}
+
+	diff := hh - ll
+	if almostZero(diff) {
+		return 0
+	}
+
+	return (hh - c.Close) / diff * -100.0
+}
+
+func (w *WillR) InitPeriod() int64 {
+	return w.n - 1
+}
+
+func (w *WillR) Valid() bool {
+	return w.sz > w.InitPeriod()
+}
+
+func (w *WillR) state(s *stateCodec) {
+	s.param(w.n)
+	s.sub(w.hi)
+	s.sub(w.lo)
+	s.int(&w.sz)
+}
+
+// Developed by Larry Williams, Williams %R is a momentum indicator that is
+// the inverse of the Fast Stochastic Oscillator. Also referred to as %R,
+// Williams %R reflects the level of the close relative to the highest high
+// for the look-back period. In contrast, the Stochastic Oscillator reflects
+// the level of the close relative to the lowest low. %R corrects for the
+// inversion by multiplying the raw value by -100. As a result, the Fast
+// Stochastic Oscillator and Williams %R produce the exact same lines, only
+// the scaling is different. Williams %R oscillates from 0 to -100.
+//  https://school.stockcharts.com/doku.php?id=technical_indicators:williams_r
+//  https://www.investopedia.com/terms/w/williamsr.asp
+func WillRArr(in []Candle, n int64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	w := NewWillR(n)
+	for i, v := range in {
+		out[i] = w.Update(v)
+	}
+	warmup(mode, w.InitPeriod(), out)
+
+	return out
+}
@@ -53,18 +53,26 @@ func (w *Wma) Valid() bool {
 	return w.hist.Size() > w.InitPeriod()
 }
 
+func (w *Wma) state(s *stateCodec) {
+	s.param(w.n)
+	s.sub(w.hist)
+	s.float(&w.sum)
+	s.float(&w.wsum)
+}
+
 // A Weighted Moving Average puts more weight on recent data and less on past
 // data. This is done by multiplying each bar’s price by a weighting factor.
 // Because of its unique calculation, WMA will follow prices more closely
 // than a corresponding Simple Moving Average.
 //  https://www.fidelity.com/learning-center/trading-investing/technical-analysis/technical-indicator-guide/wma
-func WmaArr(in []float64, n int64) []float64 {
+func WmaArr(in []float64, n int64, mode ...WarmupMode) []float64 {
 	out := make([]float64, len(in))
 
 	w := NewWma(n)
 	for i, v := range in {
 		out[i] = w.Update(v)
 	}
+	warmup(mode, w.InitPeriod(), out)
 
 	return out
 }
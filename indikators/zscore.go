@@ -0,0 +1,59 @@
+package indikators
+
+// The Z-Score tells how many standard deviations the latest value is away
+// from the mean of the last n values. Applied on the spread of a pair of
+// correlated securities it is the usual mean reversion signal of pair
+// trading, e.g. enter beyond ±2 and exit around 0.
+//  https://www.investopedia.com/terms/z/zscore.asp
+type ZScore struct {
+	sma    *Sma
+	stdDev *StdDev
+}
+
+func NewZScore(n int64) *ZScore {
+	return &ZScore{
+		sma:    NewSma(n),
+		stdDev: NewStdDev(n),
+	}
+}
+
+func (z *ZScore) Update(v float64) float64 {
+	m := z.sma.Update(v)
+	sd := z.stdDev.Update(v)
+
+	if !z.sma.Valid() || almostZero(sd) {
+		return 0
+	}
+
+	return (v - m) / sd
+}
+
+func (z *ZScore) InitPeriod() int64 {
+	return z.sma.InitPeriod()
+}
+
+func (z *ZScore) Valid() bool {
+	return z.sma.Valid()
+}
+
+func (z *ZScore) state(s *stateCodec) {
+	s.sub(z.sma)
+	s.sub(z.stdDev)
+}
+
+// The Z-Score tells how many standard deviations the latest value is away
+// from the mean of the last n values. Applied on the spread of a pair of
+// correlated securities it is the usual mean reversion signal of pair
+// trading, e.g. enter beyond ±2 and exit around 0.
+//  https://www.investopedia.com/terms/z/zscore.asp
+func ZScoreArr(in []float64, n int64, mode ...WarmupMode) []float64 {
+	out := make([]float64, len(in))
+
+	z := NewZScore(n)
+	for i, v := range in {
+		out[i] = z.Update(v)
+	}
+	warmup(mode, z.InitPeriod(), out)
+
+	return out
+}
@@ -0,0 +1,190 @@
+// Package metrics computes performance and risk statistics of a return
+// series or equity curve. Stats accumulates the whole history and Rolling
+// a sliding window, both are fed one period at a time so they run live as
+// well as over history.
+package metrics
+
+import (
+	"math"
+)
+
+type Config struct {
+	// Periods in a year used to annualise, e.g. 252 for daily bars or
+	// 365 for crypto. Figures stay per period when 0.
+	PeriodsPerYear float64
+	// Risk free return per period for Sharpe and Sortino, also the
+	// target return of the downside deviation
+	RiskFree float64
+	// VaR/CVaR confidence level, 0.95 when 0
+	Confidence float64
+	// Latest returns Stats keeps for the historical VaR and CVaR, which
+	// stay 0 when it is 0. FromReturns and Rolling use the whole series
+	// or window.
+	VaRWindow int
+}
+
+// Report holds the statistics of a return series. Returns and drawdowns
+// are fractions, VaR and CVaR are losses reported as positive fractions.
+type Report struct {
+	Periods          int     `json:"periods"`
+	CumulativeReturn float64 `json:"cumulative_return"`
+	AnnualReturn     float64 `json:"annual_return"`
+	Volatility       float64 `json:"volatility"`
+	Sharpe           float64 `json:"sharpe"`
+	Sortino          float64 `json:"sortino"`
+	Calmar           float64 `json:"calmar"`
+	MaxDrawdown      float64 `json:"max_drawdown"`
+	// Longest stretch, in periods, spent below a previous equity peak
+	MaxDrawdownDuration int `json:"max_drawdown_duration"`
+	// Historical VaR/CVaR, from the observed returns
+	VaR  float64 `json:"var"`
+	CVaR float64 `json:"cvar"`
+	// Parametric VaR/CVaR, assuming normally distributed returns
+	ParametricVaR  float64 `json:"parametric_var"`
+	ParametricCVaR float64 `json:"parametric_cvar"`
+}
+
+// Returns converts an equity curve to period returns, one less than the
+// number of points. A return from zero equity is 0.
+func Returns(equity []float64) []float64 {
+	if len(equity) < 2 {
+		return nil
+	}
+	out := make([]float64, len(equity)-1)
+	for i := 1; i < len(equity); i++ {
+		if equity[i-1] != 0 {
+			out[i-1] = equity[i]/equity[i-1] - 1
+		}
+	}
+	return out
+}
+
+// FromReturns computes the Report of a whole return series
+func FromReturns(rets []float64, cfg Config) Report {
+	cfg.VaRWindow = len(rets)
+	s := NewStats(cfg)
+	for _, r := range rets {
+		s.Update(r)
+	}
+	return s.Report()
+}
+
+// FromEquity computes the Report of a whole equity curve
+func FromEquity(equity []float64, cfg Config) Report {
+	return FromReturns(Returns(equity), cfg)
+}
+
+// moments are the figures a Report is derived from
+type moments struct {
+	n    int
+	mean float64
+	// sample standard deviation
+	sd float64
+	// root mean square of the returns below RiskFree
+	downside float64
+	cum      float64
+	dd       float64
+	ddDur    int
+	// ascending, for the historical VaR
+	sorted []float64
+}
+
+func (m moments) report(cfg Config) Report {
+	r := Report{
+		Periods:             m.n,
+		CumulativeReturn:    m.cum,
+		MaxDrawdown:         m.dd,
+		MaxDrawdownDuration: m.ddDur,
+	}
+	if m.n == 0 {
+		return r
+	}
+
+	scale := 1.0
+	years := float64(m.n)
+	if cfg.PeriodsPerYear > 0 {
+		scale = math.Sqrt(cfg.PeriodsPerYear)
+		years = float64(m.n) / cfg.PeriodsPerYear
+	}
+	if m.cum > -1 {
+		r.AnnualReturn = math.Pow(1+m.cum, 1/years) - 1
+	} else {
+		r.AnnualReturn = -1
+	}
+
+	r.Volatility = m.sd * scale
+	if m.sd > 0 {
+		r.Sharpe = (m.mean - cfg.RiskFree) / m.sd * scale
+	}
+	if m.downside > 0 {
+		r.Sortino = (m.mean - cfg.RiskFree) / m.downside * scale
+	}
+	if m.dd > 0 {
+		r.Calmar = r.AnnualReturn / m.dd
+	}
+
+	conf := cfg.Confidence
+	if conf <= 0 || conf >= 1 {
+		conf = 0.95
+	}
+	r.VaR, r.CVaR = historicalVaR(m.sorted, conf)
+	r.ParametricVaR, r.ParametricCVaR = parametricVaR(m.mean, m.sd, conf)
+
+	return r
+}
+
+// historicalVaR of ascending returns: the loss not exceeded with
+// probability conf and the average loss beyond it
+func historicalVaR(sorted []float64, conf float64) (float64, float64) {
+	if len(sorted) == 0 {
+		return 0, 0
+	}
+	k := int(math.Ceil((1-conf)*float64(len(sorted)))) - 1
+	if k < 0 {
+		k = 0
+	}
+	tail := 0.0
+	for _, r := range sorted[:k+1] {
+		tail += r
+	}
+	return -sorted[k], -tail / float64(k+1)
+}
+
+// parametricVaR assuming normally distributed returns
+func parametricVaR(mean, sd, conf float64) (float64, float64) {
+	alpha := 1 - conf
+	// standard normal quantile of alpha, negative
+	z := math.Sqrt2 * math.Erfinv(2*alpha-1)
+	pdf := math.Exp(-z*z/2) / math.Sqrt(2*math.Pi)
+	return -(mean + z*sd), -(mean - sd*pdf/alpha)
+}
+
+// drawdown tracks the equity peak of a compounded return series
+type drawdown struct {
+	equity float64
+	peak   float64
+	max    float64
+	// periods since the last peak and the longest such stretch
+	under    int
+	maxUnder int
+}
+
+func newDrawdown() drawdown {
+	return drawdown{equity: 1, peak: 1}
+}
+
+func (d *drawdown) update(ret float64) {
+	d.equity *= 1 + ret
+	if d.equity >= d.peak {
+		d.peak = d.equity
+		d.under = 0
+		return
+	}
+	d.under++
+	if d.under > d.maxUnder {
+		d.maxUnder = d.under
+	}
+	if d.peak > 0 {
+		d.max = math.Max(d.max, (d.peak-d.equity)/d.peak)
+	}
+}
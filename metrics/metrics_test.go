@@ -0,0 +1,152 @@
+package metrics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Fatiri/areuy/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromEquity(t *testing.T) {
+	// +10%, -20%, +25%, -10%, +10%
+	r := metrics.FromEquity([]float64{100, 110, 88, 110, 99, 108.9}, metrics.Config{Confidence: 0.8})
+
+	assert.Equal(t, 5, r.Periods, "they should be equal")
+	assert.InDelta(t, 0.089, r.CumulativeReturn, 1e-12, "they should be equal")
+	assert.InDelta(t, math.Pow(1.089, 0.2)-1, r.AnnualReturn, 1e-12, "they should be equal")
+	// mean 0.03, sample variance 0.032
+	assert.InDelta(t, math.Sqrt(0.032), r.Volatility, 1e-12, "they should be equal")
+	assert.InDelta(t, 0.03/math.Sqrt(0.032), r.Sharpe, 1e-12, "they should be equal")
+	assert.InDelta(t, 0.03/math.Sqrt((0.04+0.01)/5), r.Sortino, 1e-12, "they should be equal")
+	assert.InDelta(t, 0.2, r.MaxDrawdown, 1e-12, "they should be equal")
+	assert.Equal(t, 2, r.MaxDrawdownDuration, "they should be equal")
+	assert.InDelta(t, r.AnnualReturn/0.2, r.Calmar, 1e-12, "they should be equal")
+	// worst of five returns at 80%
+	assert.InDelta(t, 0.2, r.VaR, 1e-12, "they should be equal")
+	assert.InDelta(t, 0.2, r.CVaR, 1e-12, "they should be equal")
+	assert.Greater(t, r.ParametricCVaR, r.ParametricVaR, "they should be greater")
+}
+
+func TestParametricVaR(t *testing.T) {
+	// returns of mean 0 and sample deviation 1
+	r := metrics.FromReturns([]float64{-1, 1, -1, 1}, metrics.Config{Confidence: 0.95})
+	sd := math.Sqrt(4.0 / 3.0)
+
+	assert.InDelta(t, 1.6448536269514722*sd, r.ParametricVaR, 1e-9, "they should be equal")
+	assert.InDelta(t, 2.0627128075074257*sd, r.ParametricCVaR, 1e-9, "they should be equal")
+}
+
+func TestAnnualised(t *testing.T) {
+	rets := []float64{0.01, -0.005, 0.002, 0.003}
+	per := metrics.FromReturns(rets, metrics.Config{})
+	ann := metrics.FromReturns(rets, metrics.Config{PeriodsPerYear: 252})
+
+	assert.InDelta(t, per.Sharpe*math.Sqrt(252), ann.Sharpe, 1e-12, "they should be equal")
+	assert.InDelta(t, per.Volatility*math.Sqrt(252), ann.Volatility, 1e-12, "they should be equal")
+	assert.InDelta(t, math.Pow(1+per.CumulativeReturn, 63)-1, ann.AnnualReturn, 1e-12, "they should be equal")
+}
+
+func TestRolling(t *testing.T) {
+	rets := []float64{0.1, -0.2, 0.25, -0.1, 0.1, 0.05, -0.03, 0.02}
+	cfg := metrics.Config{PeriodsPerYear: 12, Confidence: 0.9}
+	out := metrics.RollingArr(rets, 4, cfg)
+
+	assert.Equal(t, metrics.Report{}, out[2], "they should be empty during warm-up")
+	for i := 3; i < len(rets); i++ {
+		want := metrics.FromReturns(rets[i-3:i+1], cfg)
+		got := out[i]
+		assert.Equal(t, want.Periods, got.Periods, "they should be equal")
+		assert.InDelta(t, want.CumulativeReturn, got.CumulativeReturn, 1e-9, "they should be equal")
+		assert.InDelta(t, want.Volatility, got.Volatility, 1e-9, "they should be equal")
+		assert.InDelta(t, want.Sharpe, got.Sharpe, 1e-9, "they should be equal")
+		assert.InDelta(t, want.Sortino, got.Sortino, 1e-9, "they should be equal")
+		assert.InDelta(t, want.MaxDrawdown, got.MaxDrawdown, 1e-9, "they should be equal")
+		assert.Equal(t, want.MaxDrawdownDuration, got.MaxDrawdownDuration, "they should be equal")
+		assert.InDelta(t, want.VaR, got.VaR, 1e-9, "they should be equal")
+		assert.InDelta(t, want.ParametricCVaR, got.ParametricCVaR, 1e-9, "they should be equal")
+	}
+}
+
+func TestStatsUpdateEquity(t *testing.T) {
+	s := metrics.NewStats(metrics.Config{})
+	for _, e := range []float64{100, 110, 88} {
+		s.UpdateEquity(e)
+	}
+	r := s.Report()
+
+	assert.Equal(t, 2, r.Periods, "they should be equal")
+	assert.InDelta(t, -0.12, r.CumulativeReturn, 1e-12, "they should be equal")
+}
+
+func TestStats(t *testing.T) {
+	rets := []float64{0.1, -0.2, 0.25, -0.1, 0.1, 0.05, -0.03, 0.02}
+
+	tests := []struct {
+		name                string
+		funcUseCaseShouldBe func(t *testing.T)
+	}{
+		{
+			name: "Moments match the whole series",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				s := metrics.NewStats(metrics.Config{PeriodsPerYear: 12})
+				for _, r := range rets {
+					s.Update(r)
+				}
+				want := metrics.FromReturns(rets, metrics.Config{PeriodsPerYear: 12})
+				got := s.Report()
+				assert.InDelta(t, want.Volatility, got.Volatility, 1e-12, "they should be equal")
+				assert.InDelta(t, want.Sharpe, got.Sharpe, 1e-12, "they should be equal")
+				assert.InDelta(t, want.Sortino, got.Sortino, 1e-12, "they should be equal")
+				assert.InDelta(t, want.MaxDrawdown, got.MaxDrawdown, 1e-12, "they should be equal")
+			},
+		},
+		{
+			name: "Historical VaR is off without a window",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				s := metrics.NewStats(metrics.Config{})
+				for _, r := range rets {
+					s.Update(r)
+				}
+				assert.Zero(t, s.Report().VaR, "they should be zero")
+				assert.Zero(t, s.Report().CVaR, "they should be zero")
+				assert.NotZero(t, s.Report().ParametricVaR, "they should be non zero")
+			},
+		},
+		{
+			name: "Historical VaR covers the last VaRWindow returns",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				cfg := metrics.Config{Confidence: 0.75, VaRWindow: 4}
+				s := metrics.NewStats(cfg)
+				for i, r := range rets {
+					s.Update(r)
+					from := i + 1 - cfg.VaRWindow
+					if from < 0 {
+						from = 0
+					}
+					want := metrics.FromReturns(rets[from:i+1], cfg)
+					assert.InDelta(t, want.VaR, s.Report().VaR, 1e-12, "bar %d should be equal", i)
+					assert.InDelta(t, want.CVaR, s.Report().CVaR, 1e-12, "bar %d should be equal", i)
+				}
+			},
+		},
+		{
+			name: "First equity of zero is still the start",
+			funcUseCaseShouldBe: func(t *testing.T) {
+				s := metrics.NewStats(metrics.Config{})
+				for _, e := range []float64{0, 100, 110} {
+					s.UpdateEquity(e)
+				}
+				r := s.Report()
+				assert.Equal(t, 2, r.Periods, "they should be equal")
+				assert.InDelta(t, 0.1, r.CumulativeReturn, 1e-12, "a return from zero equity should be 0")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.funcUseCaseShouldBe(t)
+		})
+	}
+}
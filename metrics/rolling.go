@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// Rolling computes the statistics of the last n returns. Mean, variance
+// and downside deviation are the streaming indikators Sma and Var, the
+// drawdown and historical VaR are recomputed from the window in O(n).
+type Rolling struct {
+	n    int64
+	cfg  Config
+	mean *indikators.Sma
+	v    *indikators.Var
+	down *indikators.Sma
+	rets *indikators.CBuf
+	log  float64
+	logs *indikators.CBuf
+	last Report
+}
+
+func NewRolling(n int64, cfg Config) *Rolling {
+	return &Rolling{
+		n:    n,
+		cfg:  cfg,
+		mean: indikators.NewSma(n),
+		v:    indikators.NewVar(n),
+		down: indikators.NewSma(n),
+		rets: indikators.NewCBuf(n),
+		logs: indikators.NewCBuf(n),
+	}
+}
+
+// Update adds the return of one period and returns the Report of the
+// window, empty until n returns were fed
+func (r *Rolling) Update(ret float64) Report {
+	mean := r.mean.Update(ret)
+	variance := r.v.Update(ret)
+	d := math.Min(ret-r.cfg.RiskFree, 0)
+	down := r.down.Update(d * d)
+	r.rets.Append(ret)
+	l := math.Log1p(ret)
+	r.log += l - r.logs.Append(l)
+
+	if !r.Valid() {
+		return Report{}
+	}
+
+	m := moments{
+		n:        int(r.n),
+		mean:     mean,
+		downside: math.Sqrt(down),
+		cum:      math.Expm1(r.log),
+		sorted:   make([]float64, 0, r.n),
+	}
+	if r.n > 1 {
+		// Var is the population variance of the window
+		m.sd = math.Sqrt(variance * float64(r.n) / float64(r.n-1))
+	}
+	dd := newDrawdown()
+	r.rets.Iter(func(v float64) {
+		dd.update(v)
+		m.sorted = append(m.sorted, v)
+	})
+	m.dd, m.ddDur = dd.max, dd.maxUnder
+	sort.Float64s(m.sorted)
+
+	r.last = m.report(r.cfg)
+	return r.last
+}
+
+// Report of the last full window
+func (r *Rolling) Report() Report {
+	return r.last
+}
+
+func (r *Rolling) InitPeriod() int64 {
+	return r.n - 1
+}
+
+func (r *Rolling) Valid() bool {
+	return r.rets.Size() > r.InitPeriod()
+}
+
+// RollingArr returns the rolling Report of every return, empty during the
+// first n-1 periods
+func RollingArr(rets []float64, n int64, cfg Config) []Report {
+	out := make([]Report, len(rets))
+
+	r := NewRolling(n, cfg)
+	for i, v := range rets {
+		out[i] = r.Update(v)
+	}
+
+	return out
+}
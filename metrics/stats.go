@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// Stats accumulates the statistics of every return fed so far in constant
+// memory. Mean and deviation are an indikators.CumVar, the historical VaR
+// is computed on the last Config.VaRWindow returns only.
+type Stats struct {
+	cfg      Config
+	v        *indikators.CumVar
+	variance float64
+	down     float64
+	log      float64
+	dd       drawdown
+	// nil when VaRWindow is 0
+	rets    *indikators.CBuf
+	equity  float64
+	started bool
+}
+
+func NewStats(cfg Config) *Stats {
+	s := &Stats{
+		cfg: cfg,
+		v:   indikators.NewCumVar(),
+		dd:  newDrawdown(),
+	}
+	if cfg.VaRWindow > 0 {
+		s.rets = indikators.NewCBuf(int64(cfg.VaRWindow))
+	}
+	return s
+}
+
+// Update adds the return of one period
+func (s *Stats) Update(ret float64) {
+	s.variance = s.v.Update(ret)
+	if d := ret - s.cfg.RiskFree; d < 0 {
+		s.down += d * d
+	}
+	s.log += math.Log1p(ret)
+	s.dd.update(ret)
+	if s.rets != nil {
+		s.rets.Append(ret)
+	}
+}
+
+// UpdateEquity adds the return from the previous equity value, the first
+// call only records the starting equity
+func (s *Stats) UpdateEquity(equity float64) {
+	prev := s.equity
+	s.equity = equity
+	if !s.started {
+		s.started = true
+		return
+	}
+	var ret float64
+	if prev != 0 {
+		ret = equity/prev - 1
+	}
+	s.Update(ret)
+}
+
+func (s *Stats) Report() Report {
+	n := int(s.v.Size())
+	m := moments{
+		n:     n,
+		mean:  s.v.Mean(),
+		cum:   math.Expm1(s.log),
+		dd:    s.dd.max,
+		ddDur: s.dd.maxUnder,
+	}
+	if n > 1 {
+		// CumVar is the population variance
+		m.sd = math.Sqrt(s.variance * float64(n) / float64(n-1))
+	}
+	if n > 0 {
+		m.downside = math.Sqrt(s.down / float64(n))
+	}
+	if s.rets != nil {
+		k := s.rets.Size()
+		if k > s.rets.Cap() {
+			k = s.rets.Cap()
+		}
+		m.sorted = make([]float64, k)
+		for i := range m.sorted {
+			m.sorted[i] = s.rets.NthNewest(int64(i))
+		}
+		sort.Float64s(m.sorted)
+	}
+	return m.report(s.cfg)
+}
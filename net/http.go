@@ -1,7 +1,9 @@
 package net
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"github.com/go-resty/resty/v2"
 	"io/ioutil"
 	"net/http"
@@ -9,12 +11,18 @@ import (
 )
 
 func (hc HTTPClientCtx) Invoke(param *ParamaterHttpClient) (*http.Response, error) {
-	// byteBody := bytes.NewReader(param.BodyRequest)
+	var body io.Reader
+	if len(param.BodyRequest) != 0 {
+		body = bytes.NewReader(param.BodyRequest)
+	}
 
-	request, err := http.NewRequest(param.Method, param.URL, nil)
+	request, err := http.NewRequest(param.Method, param.URL, body)
 	if err != nil {
 		return nil, err
 	}
+	if param.ContentType != "" {
+		request.Header.Set("Content-Type", param.ContentType)
+	}
 	for _, header := range param.Headers {
 		request.Header.Set(header.Key, header.Value)
 	}
@@ -0,0 +1,171 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// Tick is a candle of one symbol
+type Tick struct {
+	Symbol string            `json:"symbol"`
+	Candle indikators.Candle `json:"candle"`
+}
+
+// Result holds the indicator values of one symbol on one candle, in the
+// order of Template.Columns
+type Result struct {
+	Symbol string    `json:"symbol"`
+	Time   time.Time `json:"time"`
+	Values []float64 `json:"values"`
+	// false while any indicator of the template is in its warm-up
+	Valid bool `json:"valid"`
+}
+
+type Config struct {
+	// Number of workers, runtime.NumCPU() when 0
+	Workers int
+	// Candles queued per worker and results queued for the consumer.
+	// Once full the producer blocks, a slow consumer slows the whole
+	// pipeline down instead of growing memory.
+	Buffer int
+}
+
+// Pipeline computes a Template over many symbols in parallel. Every symbol
+// is pinned to one worker, so its candles are processed, and its results
+// sent, in the order they were received. Symbols on different workers are
+// not ordered relative to each other.
+type Pipeline struct {
+	tmpl *Template
+	cfg  Config
+}
+
+func New(tmpl *Template, cfg Config) (*Pipeline, error) {
+	if tmpl == nil {
+		return nil, errors.New("pipeline: template is required")
+	}
+	if cfg.Workers < 0 || cfg.Buffer < 0 {
+		return nil, errors.New("pipeline: workers and buffer must not be negative")
+	}
+	if cfg.Workers == 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	return &Pipeline{
+		tmpl: tmpl,
+		cfg:  cfg,
+	}, nil
+}
+
+// Run reads in until it is closed or ctx is done and returns the results
+// channel, which is closed once every queued candle was processed or ctx
+// is done. Indicator state is kept per symbol for the duration of Run.
+func (p *Pipeline) Run(ctx context.Context, in <-chan Tick) <-chan Result {
+	out := make(chan Result, p.cfg.Buffer)
+	queues := make([]chan Tick, p.cfg.Workers)
+
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan Tick, p.cfg.Buffer)
+		wg.Add(1)
+		go func(q <-chan Tick) {
+			defer wg.Done()
+			p.work(ctx, q, out)
+		}(queues[i])
+	}
+
+	go func() {
+		defer func() {
+			for _, q := range queues {
+				close(q)
+			}
+			wg.Wait()
+			close(out)
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case queues[p.shard(t.Symbol)] <- t:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+func (p *Pipeline) work(ctx context.Context, q <-chan Tick, out chan<- Result) {
+	width := len(p.tmpl.Columns())
+	symbols := map[string]instance{}
+	for t := range q {
+		if ctx.Err() != nil {
+			// drain so the dispatcher is never blocked on us
+			continue
+		}
+		inst, ok := symbols[t.Symbol]
+		if !ok {
+			inst = p.tmpl.instance()
+			symbols[t.Symbol] = inst
+		}
+		values, valid := inst.update(t.Candle, width)
+		select {
+		case out <- Result{Symbol: t.Symbol, Time: t.Candle.Time, Values: values, Valid: valid}:
+		case <-ctx.Done():
+		}
+	}
+}
+
+func (p *Pipeline) shard(symbol string) int {
+	h := fnv.New32a()
+	h.Write([]byte(symbol))
+	return int(h.Sum32() % uint32(p.cfg.Workers))
+}
+
+// Merge fans the candle streams of several symbols into one Tick channel
+// for Run. The channel is closed once every stream is closed or ctx is
+// done.
+func Merge(ctx context.Context, streams map[string]<-chan indikators.Candle) <-chan Tick {
+	out := make(chan Tick)
+
+	var wg sync.WaitGroup
+	for symbol, stream := range streams {
+		wg.Add(1)
+		go func(symbol string, stream <-chan indikators.Candle) {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case c, ok := <-stream:
+					if !ok {
+						return
+					}
+					select {
+					case out <- Tick{Symbol: symbol, Candle: c}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}(symbol, stream)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
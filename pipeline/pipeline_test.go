@@ -0,0 +1,126 @@
+package pipeline_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/json"
+	"github.com/Fatiri/areuy/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+func candles(n int, base float64) []indikators.Candle {
+	t := time.Date(2023, 1, 1, 0, 0, 0, 0, indikators.Jakarta)
+	out := make([]indikators.Candle, n)
+	for i := range out {
+		p := base + float64(i%7) - float64(i%3)
+		out[i] = indikators.Candle{
+			Time:   t.Add(time.Duration(i) * time.Minute),
+			Open:   p,
+			High:   p + 1,
+			Low:    p - 1,
+			Close:  p,
+			Volume: 1,
+		}
+	}
+	return out
+}
+
+func template(t *testing.T) *pipeline.Template {
+	tmpl, err := pipeline.NewTemplate(
+		json.Object{"type": "sma", "n": 3},
+		json.Object{"type": "bbands", "name": "bb", "n": 5},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tmpl
+}
+
+func TestNewTemplate(t *testing.T) {
+	tmpl := template(t)
+	assert.Equal(t, []string{"sma", "bb.upper", "bb.middle", "bb.lower"}, tmpl.Columns(), "they should be equal")
+
+	_, err := pipeline.NewTemplate(json.Object{"type": "sma"}, json.Object{"type": "sma", "n": 5})
+	assert.EqualError(t, err, `pipeline: duplicate indicator name "sma"`, "they should be equal")
+
+	_, err = pipeline.NewTemplate(json.Object{"type": "foo"})
+	assert.Error(t, err, "they should be error")
+}
+
+func TestRun(t *testing.T) {
+	p, err := pipeline.New(template(t), pipeline.Config{Workers: 4, Buffer: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	streams := map[string]<-chan indikators.Candle{}
+	input := map[string][]indikators.Candle{}
+	for i := 0; i < 20; i++ {
+		symbol := fmt.Sprintf("SYM%d", i)
+		input[symbol] = candles(50, float64(100+i))
+		ch := make(chan indikators.Candle)
+		go func(in []indikators.Candle) {
+			defer close(ch)
+			for _, c := range in {
+				ch <- c
+			}
+		}(input[symbol])
+		streams[symbol] = ch
+	}
+
+	ctx := context.Background()
+	got := map[string][]pipeline.Result{}
+	for r := range p.Run(ctx, pipeline.Merge(ctx, streams)) {
+		got[r.Symbol] = append(got[r.Symbol], r)
+	}
+
+	assert.Len(t, got, 20, "they should be equal")
+	for symbol, in := range input {
+		sma := indikators.NewSma(3)
+		bb := indikators.NewBBands(indikators.SMA, 5, 2, 2)
+		results := got[symbol]
+		assert.Len(t, results, len(in), "they should be equal")
+		for i, c := range in {
+			u, m, l := bb.Update(c.Close)
+			want := []float64{sma.Update(c.Close), u, m, l}
+			assert.Equal(t, c.Time, results[i].Time, "they should be in order")
+			assert.Equal(t, want, results[i].Values, "they should be equal")
+			assert.Equal(t, bb.Valid(), results[i].Valid, "they should be equal")
+		}
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	p, err := pipeline.New(template(t), pipeline.Config{Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan pipeline.Tick)
+	out := p.Run(ctx, in)
+
+	in <- pipeline.Tick{Symbol: "BTCIDR", Candle: candles(1, 100)[0]}
+	r := <-out
+	assert.Equal(t, "BTCIDR", r.Symbol, "they should be equal")
+
+	// nobody reads the next result, cancel must still close the channel
+	in <- pipeline.Tick{Symbol: "BTCIDR", Candle: candles(2, 100)[1]}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for range out {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("results channel not closed after cancel")
+	}
+}
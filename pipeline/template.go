@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/json"
+)
+
+// Template is the set of indicators computed for every symbol. Each entry
+// is an indikators.Build parameter object, an optional "name" key labels
+// its columns (the indicator type by default).
+type Template struct {
+	params  []json.Object
+	columns []string
+}
+
+// NewTemplate checks every parameter object can be built
+func NewTemplate(params ...json.Object) (*Template, error) {
+	if len(params) == 0 {
+		return nil, errors.New("pipeline: template needs at least one indicator")
+	}
+	t := &Template{params: params}
+	seen := map[string]bool{}
+	for _, p := range params {
+		ind, err := indikators.Build(p)
+		if err != nil {
+			return nil, err
+		}
+		name := p.GetStringOr("name", p.GetString("type"))
+		if seen[name] {
+			return nil, fmt.Errorf("pipeline: duplicate indicator name %q", name)
+		}
+		seen[name] = true
+
+		outs := ind.Outputs()
+		for _, out := range outs {
+			if len(outs) == 1 {
+				t.columns = append(t.columns, name)
+			} else {
+				t.columns = append(t.columns, name+"."+out)
+			}
+		}
+	}
+	return t, nil
+}
+
+// Columns names the entries of Result.Values, "name" for single output
+// indicators and "name.output" otherwise, e.g. "bbands.upper"
+func (t *Template) Columns() []string {
+	return t.columns
+}
+
+// instance is the indicator state of one symbol
+type instance []indikators.Indicator
+
+func (t *Template) instance() instance {
+	out := make(instance, len(t.params))
+	for i, p := range t.params {
+		// checked by NewTemplate
+		out[i], _ = indikators.Build(p)
+	}
+	return out
+}
+
+// update feeds c to every indicator, valid is false while any of them is
+// in its warm-up
+func (in instance) update(c indikators.Candle, width int) (values []float64, valid bool) {
+	values = make([]float64, 0, width)
+	valid = true
+	for _, ind := range in {
+		values = append(values, ind.Update(c)...)
+		valid = valid && ind.Valid()
+	}
+	return values, valid
+}
@@ -0,0 +1,76 @@
+package rule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of rule"
+	}
+	return strconv.Quote(t.text)
+}
+
+// operators, longest first so "<=" wins over "<"
+var operators = []string{"&&", "||", "<=", ">=", "==", "!=", "<", ">", "!", "+", "-", "*", "/", "(", ")", "[", "]", ",", ".", "="}
+
+func lex(src string) ([]token, error) {
+	var out []token
+	i := 0
+	for i < len(src) {
+		r := rune(src[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.' && i+1 < len(src) && unicode.IsDigit(rune(src[i+1])):
+			start := i
+			for i < len(src) && (unicode.IsDigit(rune(src[i])) || src[i] == '.') {
+				i++
+			}
+			v, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("rule: invalid number %q at %d", src[start:i], start)
+			}
+			out = append(out, token{kind: tokNumber, text: src[start:i], num: v, pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(src) && (unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i])) || src[i] == '_') {
+				i++
+			}
+			out = append(out, token{kind: tokIdent, text: strings.ToLower(src[start:i]), pos: start})
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("rule: unexpected %q at %d", src[i], i)
+			}
+			out = append(out, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(out, token{kind: tokEOF, pos: len(src)}), nil
+}
@@ -0,0 +1,280 @@
+package rule
+
+import (
+	"math"
+
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// step is updated once per candle, children before parents
+type step interface {
+	update(c indikators.Candle)
+}
+
+// node is a series keeping as much history as its parents look back
+type node interface {
+	step
+	at(k int64) float64
+	need(depth int64)
+}
+
+// series is the history shared by every node
+type series struct {
+	depth int64
+	hist  *indikators.CBufOf[float64]
+}
+
+func (s *series) need(depth int64) {
+	if depth > s.depth {
+		s.depth = depth
+	}
+}
+
+func (s *series) push(v float64) {
+	if s.hist == nil {
+		s.hist = indikators.NewCBufOf[float64](s.depth)
+	}
+	s.hist.Append(v)
+}
+
+// value k bars ago, NaN when not available
+func (s *series) at(k int64) float64 {
+	if s.hist == nil || k >= s.hist.Size() || k >= s.hist.Cap() {
+		return math.NaN()
+	}
+	return s.hist.NthNewest(k)
+}
+
+type builder struct {
+	steps []step
+	// indicator calls by their normalised text, shared so that e.g. the
+	// macd and signal outputs of the same MACD read one indicator
+	calls map[string]*callNode
+}
+
+func (b *builder) add(n node) node {
+	n.need(1)
+	b.steps = append(b.steps, n)
+	return n
+}
+
+func (b *builder) build(e expr) node {
+	switch e := e.(type) {
+	case *numExpr:
+		return b.add(&constNode{v: e.v})
+	case *fieldExpr:
+		return b.add(&fieldNode{src: e.src})
+	case *callExpr:
+		return b.add(&outputNode{call: b.call(e), i: e.output})
+	case *lookbackExpr:
+		x := b.build(e.x)
+		x.need(e.k + 1)
+		return b.add(&lookbackNode{x: x, k: e.k})
+	case *unaryExpr:
+		return b.add(&unaryNode{op: e.op, x: b.build(e.x)})
+	case *binaryExpr:
+		return b.add(&binaryNode{op: e.op, l: b.build(e.l), r: b.build(e.r)})
+	case *crossExpr:
+		a, c := b.build(e.a), b.build(e.b)
+		a.need(2)
+		c.need(2)
+		return b.add(&crossNode{over: e.over, a: a, b: c})
+	}
+	panic("rule: unknown expression")
+}
+
+func (b *builder) call(e *callExpr) *callNode {
+	key := e.name + e.params.ToString()
+	if e.input != nil {
+		key += e.input.String()
+	}
+	if c, ok := b.calls[key]; ok {
+		return c
+	}
+
+	ind, err := indikators.Build(e.params)
+	if err != nil {
+		// the parameters were checked by Compile
+		panic(err)
+	}
+	c := &callNode{ind: ind}
+	if e.input != nil {
+		c.input = b.build(e.input)
+	}
+	b.calls[key] = c
+	b.steps = append(b.steps, c)
+	return c
+}
+
+type constNode struct {
+	series
+	v float64
+}
+
+func (n *constNode) update(c indikators.Candle) {
+	n.push(n.v)
+}
+
+type fieldNode struct {
+	series
+	src indikators.Source
+}
+
+func (n *fieldNode) update(c indikators.Candle) {
+	n.push(n.src.Value(c))
+}
+
+// callNode runs an indicator, outputNode picks one of its values
+type callNode struct {
+	ind   indikators.Indicator
+	input node
+	vals  []float64
+}
+
+func (n *callNode) update(c indikators.Candle) {
+	if n.input != nil {
+		v := n.input.at(0)
+		if math.IsNaN(v) {
+			// wait for the input to warm up
+			n.vals = nil
+			return
+		}
+		c = indikators.Candle{Time: c.Time, Open: v, High: v, Low: v, Close: v, Volume: c.Volume}
+	}
+	n.vals = n.ind.Update(c)
+	if !n.ind.Valid() {
+		n.vals = nil
+	}
+}
+
+type outputNode struct {
+	series
+	call *callNode
+	i    int
+}
+
+func (n *outputNode) update(c indikators.Candle) {
+	if n.call.vals == nil {
+		n.push(math.NaN())
+		return
+	}
+	n.push(n.call.vals[n.i])
+}
+
+type lookbackNode struct {
+	series
+	x node
+	k int64
+}
+
+func (n *lookbackNode) update(c indikators.Candle) {
+	n.push(n.x.at(n.k))
+}
+
+type unaryNode struct {
+	series
+	op string
+	x  node
+}
+
+func (n *unaryNode) update(c indikators.Candle) {
+	v := n.x.at(0)
+	switch n.op {
+	case "-":
+		n.push(-v)
+	case "!":
+		if math.IsNaN(v) {
+			n.push(v)
+		} else {
+			n.push(boolValue(v == 0))
+		}
+	}
+}
+
+type binaryNode struct {
+	series
+	op   string
+	l, r node
+}
+
+func (n *binaryNode) update(c indikators.Candle) {
+	l, r := n.l.at(0), n.r.at(0)
+	switch n.op {
+	case "+":
+		n.push(l + r)
+	case "-":
+		n.push(l - r)
+	case "*":
+		n.push(l * r)
+	case "/":
+		n.push(l / r)
+	case "&&":
+		// false wins over unknown
+		if l == 0 || r == 0 {
+			n.push(0)
+		} else if math.IsNaN(l) || math.IsNaN(r) {
+			n.push(math.NaN())
+		} else {
+			n.push(1)
+		}
+	case "||":
+		// true wins over unknown
+		if truthy(l) || truthy(r) {
+			n.push(1)
+		} else if math.IsNaN(l) || math.IsNaN(r) {
+			n.push(math.NaN())
+		} else {
+			n.push(0)
+		}
+	default:
+		if math.IsNaN(l) || math.IsNaN(r) {
+			n.push(math.NaN())
+			return
+		}
+		n.push(boolValue(compare(n.op, l, r)))
+	}
+}
+
+func compare(op string, l, r float64) bool {
+	switch op {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	case "==":
+		return l == r
+	default:
+		return l != r
+	}
+}
+
+// crossNode is true on the bar a crosses above (below) b
+type crossNode struct {
+	series
+	over bool
+	a, b node
+}
+
+func (n *crossNode) update(c indikators.Candle) {
+	a0, b0, a1, b1 := n.a.at(0), n.b.at(0), n.a.at(1), n.b.at(1)
+	if math.IsNaN(a0) || math.IsNaN(b0) || math.IsNaN(a1) || math.IsNaN(b1) {
+		n.push(math.NaN())
+		return
+	}
+	if n.over {
+		n.push(boolValue(a0 > b0 && a1 <= b1))
+	} else {
+		n.push(boolValue(a0 < b0 && a1 >= b1))
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
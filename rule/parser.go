@@ -0,0 +1,424 @@
+package rule
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/json"
+)
+
+type expr interface {
+	String() string
+}
+
+type numExpr struct {
+	v float64
+}
+
+type fieldExpr struct {
+	src indikators.Source
+}
+
+// callExpr is an indicator from the indikators registry. input is nil
+// when the indicator reads the candles, otherwise every value of input
+// is fed as a flat candle.
+type callExpr struct {
+	name   string
+	params json.Object
+	input  expr
+	output int
+	outs   []string
+}
+
+type lookbackExpr struct {
+	x expr
+	k int64
+}
+
+type unaryExpr struct {
+	op string
+	x  expr
+}
+
+type binaryExpr struct {
+	op   string
+	l, r expr
+}
+
+type crossExpr struct {
+	over bool
+	a, b expr
+}
+
+func (e *numExpr) String() string {
+	return strconv.FormatFloat(e.v, 'g', -1, 64)
+}
+
+func (e *fieldExpr) String() string {
+	return e.src.String()
+}
+
+func (e *callExpr) String() string {
+	var args []string
+	if e.input != nil {
+		args = append(args, e.input.String())
+	}
+	keys := make([]string, 0, len(e.params))
+	for k := range e.params {
+		if k != "type" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, fmt.Sprintf("%s=%v", k, e.params[k]))
+	}
+	return fmt.Sprintf("%s(%s).%s", e.name, strings.Join(args, ", "), e.outs[e.output])
+}
+
+func (e *lookbackExpr) String() string {
+	return fmt.Sprintf("%s[%d]", e.x, e.k)
+}
+
+func (e *unaryExpr) String() string {
+	if e.op == "!" {
+		return fmt.Sprintf("not %s", e.x)
+	}
+	return fmt.Sprintf("%s%s", e.op, e.x)
+}
+
+func (e *binaryExpr) String() string {
+	op := e.op
+	switch op {
+	case "&&":
+		op = "and"
+	case "||":
+		op = "or"
+	}
+	return fmt.Sprintf("(%s %s %s)", e.l, op, e.r)
+}
+
+func (e *crossExpr) String() string {
+	name := "crossunder"
+	if e.over {
+		name = "crossover"
+	}
+	return fmt.Sprintf("%s(%s, %s)", name, e.a, e.b)
+}
+
+// positional lists the parameter names numbers given without a name map
+// to, in order. Indicators not listed take a single period "n".
+var positional = map[string][]string{
+	"t3":        {"n", "vfactor"},
+	"mama":      {"fast", "slow"},
+	"bbands":    {"n", "up", "dn"},
+	"percentb":  {"n", "up", "dn"},
+	"bandwidth": {"n", "up", "dn"},
+	"squeeze":   {"n", "up", "dn", "lookback"},
+	"macd":      {"fast", "slow", "signal"},
+	"macdext":   {"fast", "slow", "signal"},
+	"stochrsi":  {"n", "fastk", "fastd"},
+	"stoch":     {"fastk", "slowk", "slowd"},
+	"adosc":     {"fast", "slow"},
+	"keltner":   {"n", "atr", "mult"},
+	"ichimoku":  {"tenkan", "kijun", "senkou", "disp"},
+	"sar":       {"accel", "max"},
+}
+
+// keywords spelled as words
+var wordOps = map[string]string{"and": "&&", "or": "||", "not": "!"}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// op returns the operator of the current token, word operators included
+func (p *parser) op() string {
+	t := p.peek()
+	switch t.kind {
+	case tokOp:
+		return t.text
+	case tokIdent:
+		return wordOps[t.text]
+	}
+	return ""
+}
+
+func (p *parser) accept(ops ...string) (string, bool) {
+	cur := p.op()
+	for _, op := range ops {
+		if cur == op {
+			p.next()
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		return p.errorf("expected %q, got %s", op, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("rule: %s at %d", fmt.Sprintf(format, args...), p.peek().pos)
+}
+
+func (p *parser) parseOr() (expr, error) {
+	return p.binary(p.parseAnd, "||")
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	return p.binary(p.parseNot, "&&")
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if _, ok := p.accept("!"); ok {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "!", x: x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (expr, error) {
+	l, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.accept("<", "<=", ">", ">=", "==", "!="); ok {
+		r, err := p.parseAdd()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryExpr{op: op, l: l, r: r}, nil
+	}
+	return l, nil
+}
+
+func (p *parser) parseAdd() (expr, error) {
+	return p.binary(p.parseMul, "+", "-")
+}
+
+func (p *parser) parseMul() (expr, error) {
+	return p.binary(p.parseUnary, "*", "/")
+}
+
+// left associative chain of ops
+func (p *parser) binary(operand func() (expr, error), ops ...string) (expr, error) {
+	l, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return l, nil
+		}
+		r, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: op, l: l, r: r}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if _, ok := p.accept("-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if n, ok := x.(*numExpr); ok {
+			return &numExpr{v: -n.v}, nil
+		}
+		return &unaryExpr{op: "-", x: x}, nil
+	}
+	if _, ok := p.accept("+"); ok {
+		return p.parseUnary()
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (expr, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("["); !ok {
+			return x, nil
+		}
+		t := p.next()
+		if t.kind != tokNumber || t.num != math.Trunc(t.num) {
+			return nil, fmt.Errorf("rule: lookback must be a whole number of bars, got %s at %d", t, t.pos)
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		x = &lookbackExpr{x: x, k: int64(t.num)}
+	}
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokNumber:
+		p.next()
+		return &numExpr{v: t.num}, nil
+	case t.kind == tokIdent && wordOps[t.text] == "":
+		p.next()
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+		switch t.text {
+		case "true":
+			return &numExpr{v: 1}, nil
+		case "false":
+			return &numExpr{v: 0}, nil
+		}
+		src, err := indikators.ParseSource(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("rule: unknown identifier %q at %d", t.text, t.pos)
+		}
+		return &fieldExpr{src: src}, nil
+	}
+	if _, ok := p.accept("("); ok {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return nil, p.errorf("unexpected %s", t)
+}
+
+// parseCall parses the arguments of name( up to the closing parenthesis
+// and an optional .output selector
+func (p *parser) parseCall(name token) (expr, error) {
+	var args []expr
+	params := json.Object{"type": name.text}
+	if _, ok := p.accept(")"); !ok {
+		for {
+			if t := p.peek(); t.kind == tokIdent && p.toks[p.pos+1].text == "=" {
+				p.pos += 2
+				v, err := p.parseParam()
+				if err != nil {
+					return nil, err
+				}
+				params[t.text] = v
+			} else {
+				x, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, x)
+			}
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	switch name.text {
+	case "crossover", "crossunder":
+		if len(args) != 2 || len(params) != 1 {
+			return nil, fmt.Errorf("rule: %s takes two series at %d", name.text, name.pos)
+		}
+		return &crossExpr{over: name.text == "crossover", a: args[0], b: args[1]}, nil
+	}
+
+	call := &callExpr{name: name.text, params: params}
+	names := positional[name.text]
+	if names == nil {
+		names = []string{"n"}
+	}
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case *numExpr:
+			if len(names) == 0 {
+				return nil, fmt.Errorf("rule: too many arguments for %s at %d", name.text, name.pos)
+			}
+			params[names[0]] = a.v
+			names = names[1:]
+		case *fieldExpr:
+			if i != 0 {
+				return nil, fmt.Errorf("rule: the source of %s must be its first argument at %d", name.text, name.pos)
+			}
+			params["src"] = a.src.String()
+		default:
+			if i != 0 {
+				return nil, fmt.Errorf("rule: the input series of %s must be its first argument at %d", name.text, name.pos)
+			}
+			call.input = arg
+		}
+	}
+
+	ind, err := indikators.Build(params)
+	if err != nil {
+		return nil, fmt.Errorf("rule: %s at %d: %w", name.text, name.pos, err)
+	}
+	call.outs = ind.Outputs()
+
+	if _, ok := p.accept("."); ok {
+		t := p.next()
+		call.output = -1
+		for i, out := range call.outs {
+			if out == t.text {
+				call.output = i
+			}
+		}
+		if t.kind != tokIdent || call.output < 0 {
+			return nil, fmt.Errorf("rule: %s has no output %s at %d, want one of %s",
+				name.text, t, t.pos, strings.Join(call.outs, ", "))
+		}
+	}
+
+	return call, nil
+}
+
+// parseParam reads the value of a name=value argument, a number or a
+// word such as an MA type
+func (p *parser) parseParam() (interface{}, error) {
+	neg := false
+	if _, ok := p.accept("-"); ok {
+		neg = true
+	}
+	t := p.next()
+	switch {
+	case t.kind == tokNumber && neg:
+		return -t.num, nil
+	case t.kind == tokNumber:
+		return t.num, nil
+	case t.kind == tokIdent && !neg:
+		return t.text, nil
+	}
+	return nil, fmt.Errorf("rule: invalid parameter value %s at %d", t, t.pos)
+}
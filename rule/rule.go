@@ -0,0 +1,126 @@
+// Package rule is a small expression language for trading signals, e.g.
+//
+//	crossover(ema(close, 12), ema(close, 26)) and rsi(close, 14) < 30
+//
+// Any indicator of the indikators registry can be called by name. The
+// first argument is the candle field to read (close, hl2, ...) or another
+// series, numbers fill the indicator periods in order and name=value
+// arguments set any parameter, e.g. bbands(close, 20, ma=ema). Indicators
+// with several outputs default to the first one, pick another with a
+// selector: macd(close, 12, 26, 9).hist.
+//
+// Series support arithmetic (+ - * /), comparison (< <= > >= == !=),
+// boolean logic (and, or, not or &&, ||, !), lookback (x[1] is the value
+// of x one bar ago) and crossover(a, b) / crossunder(a, b).
+//
+// Values not available yet, indicators in their warm-up or lookback past
+// the first bar, are NaN. Comparisons on NaN are NaN and a rule only
+// fires when its value is a number other than 0.
+package rule
+
+import (
+	"math"
+
+	"github.com/Fatiri/areuy/indikators"
+)
+
+// Program is a compiled rule. It holds no state, call New for every
+// candle stream it is evaluated on.
+type Program struct {
+	src  string
+	root expr
+}
+
+// Compile parses src and checks every indicator call against the
+// indikators registry
+func Compile(src string) (*Program, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", t)
+	}
+	return &Program{src: src, root: root}, nil
+}
+
+// MustCompile is Compile panicking on error, for rules known at build time
+func MustCompile(src string) *Program {
+	p, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Source text the program was compiled from
+func (p *Program) Source() string {
+	return p.src
+}
+
+// String is the normalised form of the program with every indicator
+// parameter and operator precedence spelled out
+func (p *Program) String() string {
+	return p.root.String()
+}
+
+// New creates an evaluator of p with fresh indicator state
+func (p *Program) New() *Rule {
+	b := &builder{calls: map[string]*callNode{}}
+	root := b.build(p.root)
+	return &Rule{prog: p, steps: b.steps, root: root}
+}
+
+// Rule evaluates a Program bar by bar
+type Rule struct {
+	prog  *Program
+	steps []step
+	root  node
+	sz    int64
+}
+
+// Update feeds the next candle and reports whether the rule holds on it.
+// Every indicator is updated on every candle, whatever the boolean
+// operators short circuit.
+func (r *Rule) Update(c indikators.Candle) bool {
+	r.sz++
+	for _, s := range r.steps {
+		s.update(c)
+	}
+	return truthy(r.root.at(0))
+}
+
+// Value of the expression on the last candle, 1 or 0 for boolean
+// expressions and NaN while not available
+func (r *Rule) Value() float64 {
+	return r.root.at(0)
+}
+
+// Number of candles fed
+func (r *Rule) Size() int64 {
+	return r.sz
+}
+
+func (r *Rule) Program() *Program {
+	return r.prog
+}
+
+// Eval runs a fresh evaluator of p over candles and reports on which
+// bars the rule holds
+func Eval(p *Program, candles []indikators.Candle) []bool {
+	r := p.New()
+	out := make([]bool, len(candles))
+	for i, c := range candles {
+		out[i] = r.Update(c)
+	}
+	return out
+}
+
+func truthy(v float64) bool {
+	return !math.IsNaN(v) && v != 0
+}
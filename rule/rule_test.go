@@ -0,0 +1,168 @@
+package rule_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/rule"
+	"github.com/stretchr/testify/assert"
+)
+
+func candles(prices ...float64) []indikators.Candle {
+	t := time.Date(2023, 1, 1, 0, 0, 0, 0, indikators.Jakarta)
+	out := make([]indikators.Candle, len(prices))
+	for i, p := range prices {
+		out[i] = indikators.Candle{
+			Time:   t.AddDate(0, 0, i),
+			Open:   p,
+			High:   p + 1,
+			Low:    p - 1,
+			Close:  p,
+			Volume: 1,
+		}
+	}
+	return out
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name                string
+		src                 string
+		funcUseCaseShouldBe func(t *testing.T, p *rule.Program, err error)
+	}{
+		{
+			name: "Positional arguments fill the indicator periods",
+			src:  "crossover(ema(close,12), ema(close,26)) and rsi(close,14) < 30",
+			funcUseCaseShouldBe: func(t *testing.T, p *rule.Program, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, "(crossover(ema(n=12, src=close).ema, ema(n=26, src=close).ema) and (rsi(n=14, src=close).rsi < 30))", p.String(), "they should be equal")
+			},
+		},
+		{
+			name: "Named arguments and output selectors",
+			src:  "macd(hl2, 8, signal=5).hist[1] > 0 || NOT bbands(close, 20, ma=ema).upper > close",
+			funcUseCaseShouldBe: func(t *testing.T, p *rule.Program, err error) {
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, "((macd(fast=8, signal=5, src=hl2).hist[1] > 0) or not (bbands(ma=ema, n=20, src=close).upper > close))", p.String(), "they should be equal")
+			},
+		},
+		{
+			name: "Unknown indicator",
+			src:  "foo(close) > 1",
+			funcUseCaseShouldBe: func(t *testing.T, p *rule.Program, err error) {
+				assert.EqualError(t, err, `rule: foo at 0: indikators: unknown indicator type "foo"`, "they should be equal")
+			},
+		},
+		{
+			name: "Unknown output",
+			src:  "macd(close).foo > 0",
+			funcUseCaseShouldBe: func(t *testing.T, p *rule.Program, err error) {
+				assert.EqualError(t, err, `rule: macd has no output "foo" at 12, want one of macd, signal, hist`, "they should be equal")
+			},
+		},
+		{
+			name: "Unknown identifier",
+			src:  "closee > 1",
+			funcUseCaseShouldBe: func(t *testing.T, p *rule.Program, err error) {
+				assert.EqualError(t, err, `rule: unknown identifier "closee" at 0`, "they should be equal")
+			},
+		},
+		{
+			name: "Trailing tokens",
+			src:  "close > 1 )",
+			funcUseCaseShouldBe: func(t *testing.T, p *rule.Program, err error) {
+				assert.EqualError(t, err, `rule: unexpected ")" at 10`, "they should be equal")
+			},
+		},
+		{
+			name: "Fractional lookback",
+			src:  "close[1.5] > 1",
+			funcUseCaseShouldBe: func(t *testing.T, p *rule.Program, err error) {
+				assert.EqualError(t, err, `rule: lookback must be a whole number of bars, got "1.5" at 6`, "they should be equal")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := rule.Compile(test.src)
+			test.funcUseCaseShouldBe(t, p, err)
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		candles []indikators.Candle
+		want    []bool
+	}{
+		{
+			name:    "Crossover fires on the crossing bar only",
+			src:     "crossover(close, sma(close, 3))",
+			candles: candles(5, 4, 3, 2, 6, 7, 8),
+			want:    []bool{false, false, false, false, true, false, false},
+		},
+		{
+			name:    "Crossunder",
+			src:     "crossunder(close, 4)",
+			candles: candles(5, 4, 3, 5, 3),
+			want:    []bool{false, false, true, false, true},
+		},
+		{
+			name:    "Lookback reads previous bars",
+			src:     "close > close[1] and close[1] > close[2]",
+			candles: candles(1, 2, 3, 2, 3, 4),
+			want:    []bool{false, false, true, false, false, true},
+		},
+		{
+			name:    "Indicators are not available during warm-up",
+			src:     "not sma(close, 3) > 100",
+			candles: candles(1, 2, 3, 4),
+			want:    []bool{false, false, true, true},
+		},
+		{
+			name:    "Or is true when one side is true even if the other is unknown",
+			src:     "sma(close, 3) > 0 or close > 1",
+			candles: candles(1, 2, 1),
+			want:    []bool{false, true, true},
+		},
+		{
+			name:    "Indicators on a computed series",
+			src:     "sma(close - close[1], 2) > 0",
+			candles: candles(1, 2, 3, 2, 1),
+			want:    []bool{false, false, true, false, false},
+		},
+		{
+			name:    "Arithmetic",
+			src:     "(high - low) / 2 == 1 and -close < -2",
+			candles: candles(1, 3),
+			want:    []bool{false, true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := rule.Compile(test.src)
+			assert.NoError(t, err, "they should be no error")
+			assert.Equal(t, test.want, rule.Eval(p, test.candles), "they should be equal")
+		})
+	}
+}
+
+func TestRuleIndependentState(t *testing.T) {
+	p := rule.MustCompile("sma(close, 2)")
+	a, b := p.New(), p.New()
+
+	for _, c := range candles(1, 3) {
+		a.Update(c)
+	}
+	b.Update(candles(10)[0])
+
+	assert.Equal(t, 2.0, a.Value(), "they should be equal")
+	assert.True(t, math.IsNaN(b.Value()), "it should be NaN")
+	assert.Equal(t, int64(2), a.Size(), "they should be equal")
+}
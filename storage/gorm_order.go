@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Fatiri/areuy/exchange/order"
+	"gorm.io/gorm"
+)
+
+// GormOrderStore keeps tracked orders in the exchange_order table, it
+// implements order.Store
+type GormOrderStore struct {
+	db *gorm.DB
+}
+
+// NewGormOrderStore stores orders with db, call Migrate once to create
+// the table
+func NewGormOrderStore(db *gorm.DB) *GormOrderStore {
+	return &GormOrderStore{db: db}
+}
+
+// Migrate creates or updates the exchange_order table
+func (s *GormOrderStore) Migrate() error {
+	return s.db.AutoMigrate(&order.Order{})
+}
+
+func (s *GormOrderStore) Save(ctx context.Context, o *order.Order) error {
+	return s.db.WithContext(ctx).Save(o).Error
+}
+
+func (s *GormOrderStore) Load(ctx context.Context, id string) (*order.Order, error) {
+	var o order.Order
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&o).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, order.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (s *GormOrderStore) Active(ctx context.Context) ([]order.Order, error) {
+	var orders []order.Order
+	err := s.db.WithContext(ctx).
+		Where("state IN ?", []order.State{order.New, order.PartiallyFilled}).
+		Order("created_at, id").
+		Find(&orders).Error
+	return orders, err
+}
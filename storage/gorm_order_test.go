@@ -0,0 +1,120 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Fatiri/areuy/exchange/order"
+	"github.com/Fatiri/areuy/storage"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var orderColumns = []string{"id", "exchange_id", "pair", "side", "type", "price", "amount", "remaining", "state", "error", "created_at", "updated_at"}
+
+func newGormOrderStore(t *testing.T) (*storage.GormOrderStore, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return storage.NewGormOrderStore(gdb), mock
+}
+
+func orderRow(o order.Order) []driver.Value {
+	return []driver.Value{o.ID, o.ExchangeID, o.Pair, o.Side, o.Type, o.Price, o.Amount, o.Remaining, string(o.State), o.Error, o.CreatedAt, o.UpdatedAt}
+}
+
+func TestGormOrderStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := order.Order{ID: "areuy-1", ExchangeID: 7, Pair: "btc_idr", Side: "buy", Type: "limit", Price: 900, Amount: 90000, Remaining: 90000, State: order.New, CreatedAt: now, UpdatedAt: now}
+	args := orderRow(o)
+
+	tests := []struct {
+		name                string
+		funcUseCaseShouldBe func(t *testing.T, store *storage.GormOrderStore, mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "Save updates an existing order",
+			funcUseCaseShouldBe: func(t *testing.T, store *storage.GormOrderStore, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE "exchange_order" SET "exchange_id"=$1,"pair"=$2,"side"=$3,"type"=$4,"price"=$5,"amount"=$6,"remaining"=$7,"state"=$8,"error"=$9,"created_at"=$10,"updated_at"=$11 WHERE "id" = $12`).
+					WithArgs(append(args[1:], o.ID)...).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				assert.NoError(t, store.Save(ctx, &o), "they should be no error")
+			},
+		},
+		{
+			name: "Save inserts an unknown order",
+			funcUseCaseShouldBe: func(t *testing.T, store *storage.GormOrderStore, mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE "exchange_order" SET "exchange_id"=$1,"pair"=$2,"side"=$3,"type"=$4,"price"=$5,"amount"=$6,"remaining"=$7,"state"=$8,"error"=$9,"created_at"=$10,"updated_at"=$11 WHERE "id" = $12`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`INSERT INTO "exchange_order" ("id","exchange_id","pair","side","type","price","amount","remaining","state","error","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) ON CONFLICT ("id") DO UPDATE SET "exchange_id"="excluded"."exchange_id","pair"="excluded"."pair","side"="excluded"."side","type"="excluded"."type","price"="excluded"."price","amount"="excluded"."amount","remaining"="excluded"."remaining","state"="excluded"."state","error"="excluded"."error","created_at"="excluded"."created_at","updated_at"="excluded"."updated_at"`).
+					WithArgs(args...).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				assert.NoError(t, store.Save(ctx, &o), "they should be no error")
+			},
+		},
+		{
+			name: "Load",
+			funcUseCaseShouldBe: func(t *testing.T, store *storage.GormOrderStore, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT * FROM "exchange_order" WHERE id = $1 ORDER BY "exchange_order"."id" LIMIT 1`).
+					WithArgs(o.ID).
+					WillReturnRows(sqlmock.NewRows(orderColumns).AddRow(args...))
+
+				got, err := store.Load(ctx, o.ID)
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, o, *got, "they should be equal")
+			},
+		},
+		{
+			name: "Load of an unknown order is ErrNotFound",
+			funcUseCaseShouldBe: func(t *testing.T, store *storage.GormOrderStore, mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT * FROM "exchange_order" WHERE id = $1 ORDER BY "exchange_order"."id" LIMIT 1`).
+					WithArgs("areuy-2").
+					WillReturnRows(sqlmock.NewRows(orderColumns))
+
+				_, err := store.Load(ctx, "areuy-2")
+				assert.Equal(t, order.ErrNotFound, err, "they should be equal")
+			},
+		},
+		{
+			name: "Active selects the non final states oldest first",
+			funcUseCaseShouldBe: func(t *testing.T, store *storage.GormOrderStore, mock sqlmock.Sqlmock) {
+				partial := o
+				partial.ID, partial.State, partial.CreatedAt = "areuy-2", order.PartiallyFilled, now.Add(time.Minute)
+				mock.ExpectQuery(`SELECT * FROM "exchange_order" WHERE state IN ($1,$2) ORDER BY created_at, id`).
+					WithArgs("new", "partially_filled").
+					WillReturnRows(sqlmock.NewRows(orderColumns).AddRow(args...).AddRow(orderRow(partial)...))
+
+				active, err := store.Active(ctx)
+				assert.NoError(t, err, "they should be no error")
+				assert.Equal(t, []order.Order{o, partial}, active, "they should be equal")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store, mock := newGormOrderStore(t)
+			test.funcUseCaseShouldBe(t, store, mock)
+			assert.NoError(t, mock.ExpectationsWereMet(), "they should be no error")
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// reserve stores max(min, last+1) and returns it
+var reserve = redis.NewScript(`
+local last = tonumber(redis.call('GET', KEYS[1]) or '0')
+local n = tonumber(ARGV[1])
+if n <= last then
+	n = last + 1
+end
+redis.call('SET', KEYS[1], n)
+return n
+`)
+
+// RedisNonce shares the last nonce of an API key between processes, it
+// implements crypto.NonceStore
+type RedisNonce struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisNonce keeps the last nonce under key, use one key per API key
+func NewRedisNonce(r Redis, key string) *RedisNonce {
+	return &RedisNonce{
+		client: r.Run(),
+		key:    key,
+	}
+}
+
+func (r *RedisNonce) Reserve(ctx context.Context, min int64) (int64, error) {
+	return reserve.Run(ctx, r.client, []string{r.key}, min).Int64()
+}
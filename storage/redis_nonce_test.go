@@ -0,0 +1,92 @@
+package storage_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/crypto"
+	"github.com/Fatiri/areuy/storage"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisNonce(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	store := storage.NewRedisNonce(storage.NewRedis(srv.Addr(), "", 0), "nonce:key")
+
+	tests := []struct {
+		name string
+		min  int64
+		want int64
+	}{
+		{name: "First reserve stores min", min: 1000, want: 1000},
+		{name: "Above the stored value", min: 1500, want: 1500},
+		{name: "Equal to the stored value", min: 1500, want: 1501},
+		{name: "Below the stored value", min: 1200, want: 1502},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := store.Reserve(ctx, test.min)
+			assert.NoError(t, err, "they should be no error")
+			assert.Equal(t, test.want, got, "they should be equal")
+			stored, err := srv.Get("nonce:key")
+			assert.NoError(t, err, "they should be no error")
+			assert.Equal(t, test.want, mustInt(t, stored), "they should be equal")
+		})
+	}
+}
+
+func TestRedisNonceShared(t *testing.T) {
+	srv := miniredis.RunT(t)
+	// the clock stands still, every nonce comes from the shared counter
+	now := func() time.Time { return time.UnixMilli(1578304294000) }
+	nonces := []*crypto.Nonce{
+		crypto.NewNonce(crypto.NonceConfig{Store: storage.NewRedisNonce(storage.NewRedis(srv.Addr(), "", 0), "nonce:key"), Now: now}),
+		crypto.NewNonce(crypto.NonceConfig{Store: storage.NewRedisNonce(storage.NewRedis(srv.Addr(), "", 0), "nonce:key"), Now: now}),
+	}
+
+	const each = 50
+	issued := make([][]int64, len(nonces))
+	var wg sync.WaitGroup
+	for i, n := range nonces {
+		wg.Add(1)
+		go func(i int, n *crypto.Nonce) {
+			defer wg.Done()
+			for j := 0; j < each; j++ {
+				v, err := n.Next()
+				assert.NoError(t, err, "they should be no error")
+				issued[i] = append(issued[i], v)
+			}
+		}(i, n)
+	}
+	wg.Wait()
+
+	seen := map[int64]bool{}
+	for _, vals := range issued {
+		for j, v := range vals {
+			assert.False(t, seen[v], "%d should be issued once", v)
+			seen[v] = true
+			if j > 0 {
+				assert.Greater(t, v, vals[j-1], "they should increase")
+			}
+		}
+	}
+	assert.Len(t, seen, 2*each, "they should be equal")
+	stored, err := srv.Get("nonce:key")
+	assert.NoError(t, err, "they should be no error")
+	assert.Equal(t, int64(1578304294000+2*each-1), mustInt(t, stored), "they should be equal")
+}
+
+func mustInt(t *testing.T, s string) int64 {
+	t.Helper()
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
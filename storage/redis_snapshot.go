@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisSnapshot stores indicator snapshots in Redis, it implements
+// indikators.SnapshotStore
+type RedisSnapshot struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisSnapshot stores snapshots under prefix+key, a zero ttl keeps
+// them until overwritten
+func NewRedisSnapshot(r Redis, prefix string, ttl time.Duration) *RedisSnapshot {
+	return &RedisSnapshot{
+		client: r.Run(),
+		prefix: prefix,
+		ttl:    ttl,
+	}
+}
+
+func (r *RedisSnapshot) Save(ctx context.Context, key string, data []byte) error {
+	return r.client.Set(ctx, r.prefix+key, data, r.ttl).Err()
+}
+
+func (r *RedisSnapshot) Load(ctx context.Context, key string) ([]byte, error) {
+	data, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, indikators.ErrNoSnapshot
+	}
+	return data, err
+}
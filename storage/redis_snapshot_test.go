@@ -0,0 +1,37 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Fatiri/areuy/indikators"
+	"github.com/Fatiri/areuy/storage"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisSnapshot(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	store := storage.NewRedisSnapshot(storage.NewRedis(srv.Addr(), "", 0), "snapshot:", time.Hour)
+
+	_, err := store.Load(ctx, "btc_idr:sma")
+	assert.Equal(t, indikators.ErrNoSnapshot, err, "they should be equal")
+
+	x := indikators.NewSma(3)
+	for _, v := range []float64{1, 2, 3, 4} {
+		x.Update(v)
+	}
+	assert.NoError(t, indikators.SaveSnapshot(ctx, store, "btc_idr:sma", x), "they should be no error")
+	assert.True(t, srv.Exists("snapshot:btc_idr:sma"), "it should be stored under the prefix")
+	assert.Equal(t, time.Hour, srv.TTL("snapshot:btc_idr:sma"), "they should be equal")
+
+	restored := indikators.NewSma(3)
+	assert.NoError(t, indikators.LoadSnapshot(ctx, store, "btc_idr:sma", restored), "they should be no error")
+	assert.Equal(t, x.Update(5), restored.Update(5), "they should be equal")
+
+	srv.FastForward(time.Hour)
+	_, err = store.Load(ctx, "btc_idr:sma")
+	assert.Equal(t, indikators.ErrNoSnapshot, err, "it should be expired")
+}